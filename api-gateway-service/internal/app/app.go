@@ -7,9 +7,14 @@ import (
 	"api-gateway-service/config"
 
 	"api-gateway-service/internal/transport/http"
+	"api-gateway-service/internal/transport/natshandlers/alerts"
 	"api-gateway-service/internal/transport/natshandlers/auth"
 	"api-gateway-service/internal/transport/natshandlers/devices"
+	"api-gateway-service/internal/transport/natshandlers/heartbeats"
+	"api-gateway-service/internal/transport/natshandlers/quarantine"
 	"api-gateway-service/internal/transport/natshandlers/reports"
+	"api-gateway-service/internal/transport/natshandlers/rules"
+	"api-gateway-service/internal/transport/natshandlers/silences"
 	"api-gateway-service/internal/transport/natshandlers/tags"
 	"api-gateway-service/pkg/closer"
 	"api-gateway-service/pkg/logger"
@@ -50,6 +55,31 @@ func Run(ctx context.Context, cfg *config.Config, stop context.CancelFunc) {
 		Timeout:  cfg.Nats.Timeout,
 	})
 
+	quarantineHandlers := quarantine.NewQuarantineHandlers(quarantine.Config{
+		NatsConn: nats.NatsConn,
+		Timeout:  cfg.Nats.Timeout,
+	})
+
+	rulesHandlers := rules.NewRulesHandlers(rules.Config{
+		NatsConn: nats.NatsConn,
+		Timeout:  cfg.Nats.Timeout,
+	})
+
+	alertsHandlers := alerts.NewAlertsHandlers(alerts.Config{
+		NatsConn: nats.NatsConn,
+		Timeout:  cfg.Nats.Timeout,
+	})
+
+	silencesHandlers := silences.NewSilencesHandlers(silences.Config{
+		NatsConn: nats.NatsConn,
+		Timeout:  cfg.Nats.Timeout,
+	})
+
+	heartbeatsHandlers := heartbeats.NewHeartbeatsHandlers(heartbeats.Config{
+		NatsConn: nats.NatsConn,
+		Timeout:  cfg.Nats.Timeout,
+	})
+
 	httpServer := http.NewServer(http.Config{
 		Log:             log,
 		JwtKey:          cfg.Server.JwtKey,
@@ -59,6 +89,12 @@ func Run(ctx context.Context, cfg *config.Config, stop context.CancelFunc) {
 		DevicesHandlers: devicesHandlers,
 		TagsHandler:     tagsHandlers,
 		ReportsHandler:  reportsHandlers,
+
+		QuarantineHandler: quarantineHandlers,
+		RulesHandler:      rulesHandlers,
+		AlertsHandler:     alertsHandlers,
+		SilencesHandler:   silencesHandlers,
+		HeartbeatsHandler: heartbeatsHandlers,
 	})
 
 	go func() {
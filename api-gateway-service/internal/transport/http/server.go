@@ -8,9 +8,14 @@ import (
 	"time"
 
 	v1 "api-gateway-service/internal/transport/http/v1"
+	"api-gateway-service/internal/transport/natshandlers/alerts"
 	"api-gateway-service/internal/transport/natshandlers/auth"
 	"api-gateway-service/internal/transport/natshandlers/devices"
+	"api-gateway-service/internal/transport/natshandlers/heartbeats"
+	"api-gateway-service/internal/transport/natshandlers/quarantine"
 	"api-gateway-service/internal/transport/natshandlers/reports"
+	"api-gateway-service/internal/transport/natshandlers/rules"
+	"api-gateway-service/internal/transport/natshandlers/silences"
 	"api-gateway-service/internal/transport/natshandlers/tags"
 
 	"github.com/gofiber/fiber/v3"
@@ -27,6 +32,12 @@ type Server struct {
 	devicesHandlers *devices.DevicesHandler
 	tagsHandler     *tags.TagsHandler
 	reportsHandler  *reports.ReportsHandler
+
+	quarantineHandler *quarantine.QuarantineHandler
+	rulesHandler      *rules.RulesHandler
+	alertsHandler     *alerts.AlertsHandler
+	silencesHandler   *silences.SilencesHandler
+	heartbeatsHandler *heartbeats.HeartbeatsHandler
 }
 
 type Config struct {
@@ -39,6 +50,12 @@ type Config struct {
 	DevicesHandlers *devices.DevicesHandler
 	TagsHandler     *tags.TagsHandler
 	ReportsHandler  *reports.ReportsHandler
+
+	QuarantineHandler *quarantine.QuarantineHandler
+	RulesHandler      *rules.RulesHandler
+	AlertsHandler     *alerts.AlertsHandler
+	SilencesHandler   *silences.SilencesHandler
+	HeartbeatsHandler *heartbeats.HeartbeatsHandler
 }
 
 func NewServer(cfg Config) *Server {
@@ -50,7 +67,13 @@ func NewServer(cfg Config) *Server {
 		devicesHandlers: cfg.DevicesHandlers,
 		tagsHandler:     cfg.TagsHandler,
 		reportsHandler:  cfg.ReportsHandler,
-		app:             nil,
+
+		quarantineHandler: cfg.QuarantineHandler,
+		rulesHandler:      cfg.RulesHandler,
+		alertsHandler:     cfg.AlertsHandler,
+		silencesHandler:   cfg.SilencesHandler,
+		heartbeatsHandler: cfg.HeartbeatsHandler,
+		app:               nil,
 	}
 
 	server.app = fiber.New(
@@ -169,6 +192,12 @@ func (s *Server) setHandlers() {
 		DevicesHandlers: s.devicesHandlers,
 		TagsHandlers:    s.tagsHandler,
 		ReportsHandlers: s.reportsHandler,
+
+		QuarantineHandlers: s.quarantineHandler,
+		RulesHandlers:      s.rulesHandler,
+		AlertsHandlers:     s.alertsHandler,
+		SilencesHandlers:   s.silencesHandler,
+		HeartbeatsHandlers: s.heartbeatsHandler,
 	})
 	{
 		apiV1 := rootRoute.Group("/v1")
@@ -0,0 +1,278 @@
+package alerts
+
+import (
+	"api-gateway-service/internal/transport/natshandlers/alerts"
+	pbalerts "api-gateway-service/proto/api-gateway/alerts"
+	"errors"
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	localID = "localID"
+)
+
+type alertsHandler struct {
+	natsHandlers *alerts.AlertsHandler
+	jwtKey       string
+}
+
+type Config struct {
+	JWTKey       string
+	NatsHandlers *alerts.AlertsHandler
+}
+
+func NewAlertsHandler(cfg *Config) *alertsHandler {
+	return &alertsHandler{
+		jwtKey:       cfg.JWTKey,
+		natsHandlers: cfg.NatsHandlers,
+	}
+}
+
+func (h *alertsHandler) InitAlertsRoutes(api fiber.Router) {
+	servicesRoute := api.Group("/alerts", h.deserializeMW)
+	servicesRoute.Get("/read", h.read)
+	servicesRoute.Put("/acknowledge", h.acknowledge)
+	servicesRoute.Put("/resolve", h.resolve)
+}
+
+type (
+	// readReq lists the newest alerts first, filtered by the fields that are
+	// set.
+	readReq struct {
+		State    string `form:"state"     json:"state"     validate:"omitempty,oneof=OPEN ACKNOWLEDGED FLAPPING RESOLVED" xml:"state"`
+		DeviceID int32  `form:"device_id" json:"device_id" validate:"gte=0"                                           xml:"device_id"`
+		Limit    int32  `form:"limit"     json:"limit"     validate:"gte=0,lte=1000"                                  xml:"limit"`
+	}
+
+	readResp struct {
+		Data *pbalerts.ReadResp `json:"data"`
+	}
+)
+
+func (h *alertsHandler) read(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body readReq
+
+	if len(ctx.Body()) > 0 {
+		if err := ctx.Bind().Body(&body); err != nil {
+			return fiber.NewError(
+				fiber.StatusUnprocessableEntity,
+				fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+			)
+		}
+	}
+
+	res, err := h.natsHandlers.PublishRead(
+		&pbalerts.ReadReq{
+			State:    body.State,
+			DeviceID: body.DeviceID,
+			Limit:    body.Limit,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishRead: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&readResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+	return nil
+}
+
+type (
+	// acknowledgeReq is also the body of resolve, the user is taken from
+	// the token.
+	acknowledgeReq struct {
+		ID int32 `form:"id" json:"id" validate:"required" xml:"id"`
+	}
+
+	acknowledgeResp struct {
+		Data *pbalerts.AcknowledgeResp `json:"data"`
+	}
+)
+
+func (h *alertsHandler) acknowledge(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	userID, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body acknowledgeReq
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishAcknowledge(
+		&pbalerts.AcknowledgeReq{
+			ID:     body.ID,
+			UserID: int32(userID),
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishAcknowledge: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&acknowledgeResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+	return nil
+}
+
+type (
+	resolveResp struct {
+		Data *pbalerts.ResolveResp `json:"data"`
+	}
+)
+
+func (h *alertsHandler) resolve(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	userID, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body acknowledgeReq
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishResolve(
+		&pbalerts.ResolveReq{
+			ID:     body.ID,
+			UserID: int32(userID),
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishResolve: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&resolveResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+	return nil
+}
+
+func (h *alertsHandler) deserializeMW(ctx fiber.Ctx) error {
+	tokenString := ctx.Get("Authorization")
+
+	if tokenString == "" {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("tokenString is empty").Error(),
+		)
+	}
+
+	tokenString = strings.ReplaceAll(tokenString, "Bearer ", "")
+	token, err := jwt.Parse(tokenString, func(_ *jwt.Token) (interface{}, error) {
+		return []byte(h.jwtKey), nil
+	})
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			fmt.Errorf("jwt.Parse: %w", err).Error(),
+		)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("token.Claims.(jwt.MapClaims): invalid token").Error(),
+		)
+	}
+
+	userID, ok := claims[localID].(float64) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("claims["+localID+"].(float64): invalid token").Error(),
+		)
+	}
+
+	ctx.Locals(localID, int(userID))
+
+	return ctx.Next() //nolint:wrapcheck
+}
@@ -40,6 +40,7 @@ func (h *devicesHandler) InitDevicesRoutes(api fiber.Router) {
 	servicesRoute.Get("/read", h.read)
 	servicesRoute.Put("/update", h.update)
 	servicesRoute.Delete("/delete", h.delete)
+	servicesRoute.Post("/rotate_key", h.rotateKey)
 
 }
 
@@ -305,6 +306,73 @@ func (h *devicesHandler) delete(ctx fiber.Ctx) error {
 	return nil
 }
 
+type (
+	rotateKeyReq struct {
+		ID int32 `form:"id" json:"id" validate:"required" xml:"id"`
+	}
+
+	rotateKeyResp struct {
+		Data *pbdevices.RotateKeyResp `json:"data"`
+	}
+)
+
+// rotateKey issues a new ingestion key for the device, the previous one is
+// rejected by data-ingestion-service from then on.
+func (h *devicesHandler) rotateKey(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	body := rotateKeyReq{
+		ID: 0,
+	}
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishRotateKey(
+		&pbdevices.RotateKeyReq{
+			ID: body.ID,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishRotateKey: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&rotateKeyResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
 func (h *devicesHandler) deserializeMW(ctx fiber.Ctx) error {
 	tokenString := ctx.Get("Authorization")
 
@@ -1,14 +1,24 @@
 package v1
 
 import (
+	alertsHandlers "api-gateway-service/internal/transport/http/v1/alerts"
 	authHandlers "api-gateway-service/internal/transport/http/v1/auth"
 	devicesHandlers "api-gateway-service/internal/transport/http/v1/devices"
+	heartbeatsHandlers "api-gateway-service/internal/transport/http/v1/heartbeats"
+	quarantineHandlers "api-gateway-service/internal/transport/http/v1/quarantine"
 	reportsHandlers "api-gateway-service/internal/transport/http/v1/reports"
+	rulesHandlers "api-gateway-service/internal/transport/http/v1/rules"
+	silencesHandlers "api-gateway-service/internal/transport/http/v1/silences"
 	tagsHandlers "api-gateway-service/internal/transport/http/v1/tags"
 
+	"api-gateway-service/internal/transport/natshandlers/alerts"
 	"api-gateway-service/internal/transport/natshandlers/auth"
 	"api-gateway-service/internal/transport/natshandlers/devices"
+	"api-gateway-service/internal/transport/natshandlers/heartbeats"
+	"api-gateway-service/internal/transport/natshandlers/quarantine"
 	"api-gateway-service/internal/transport/natshandlers/reports"
+	"api-gateway-service/internal/transport/natshandlers/rules"
+	"api-gateway-service/internal/transport/natshandlers/silences"
 	"api-gateway-service/internal/transport/natshandlers/tags"
 
 	"github.com/gofiber/fiber/v3"
@@ -20,6 +30,12 @@ type Handler struct {
 	devicesHandlers *devices.DevicesHandler
 	tagsHandlers    *tags.TagsHandler
 	reportsHandlers *reports.ReportsHandler
+
+	quarantineHandlers *quarantine.QuarantineHandler
+	rulesHandlers      *rules.RulesHandler
+	alertsHandlers     *alerts.AlertsHandler
+	silencesHandlers   *silences.SilencesHandler
+	heartbeatsHandlers *heartbeats.HeartbeatsHandler
 }
 
 type Config struct {
@@ -28,6 +44,12 @@ type Config struct {
 	DevicesHandlers *devices.DevicesHandler
 	TagsHandlers    *tags.TagsHandler
 	ReportsHandlers *reports.ReportsHandler
+
+	QuarantineHandlers *quarantine.QuarantineHandler
+	RulesHandlers      *rules.RulesHandler
+	AlertsHandlers     *alerts.AlertsHandler
+	SilencesHandlers   *silences.SilencesHandler
+	HeartbeatsHandlers *heartbeats.HeartbeatsHandler
 }
 
 func NewHandler(cfg Config) *Handler {
@@ -37,6 +59,12 @@ func NewHandler(cfg Config) *Handler {
 		devicesHandlers: cfg.DevicesHandlers,
 		tagsHandlers:    cfg.TagsHandlers,
 		reportsHandlers: cfg.ReportsHandlers,
+
+		quarantineHandlers: cfg.QuarantineHandlers,
+		rulesHandlers:      cfg.RulesHandlers,
+		alertsHandlers:     cfg.AlertsHandlers,
+		silencesHandlers:   cfg.SilencesHandlers,
+		heartbeatsHandlers: cfg.HeartbeatsHandlers,
 	}
 }
 
@@ -60,4 +88,29 @@ func (h *Handler) InitRouter(routeV1 fiber.Router) {
 		NatsHandlers: h.reportsHandlers,
 		JWTKey:       h.jwtKey,
 	}).InitReportsRoutes(routeV1)
+
+	quarantineHandlers.NewQuarantineHandler(&quarantineHandlers.Config{
+		NatsHandlers: h.quarantineHandlers,
+		JWTKey:       h.jwtKey,
+	}).InitQuarantineRoutes(routeV1)
+
+	rulesHandlers.NewRulesHandler(&rulesHandlers.Config{
+		NatsHandlers: h.rulesHandlers,
+		JWTKey:       h.jwtKey,
+	}).InitRulesRoutes(routeV1)
+
+	alertsHandlers.NewAlertsHandler(&alertsHandlers.Config{
+		NatsHandlers: h.alertsHandlers,
+		JWTKey:       h.jwtKey,
+	}).InitAlertsRoutes(routeV1)
+
+	silencesHandlers.NewSilencesHandler(&silencesHandlers.Config{
+		NatsHandlers: h.silencesHandlers,
+		JWTKey:       h.jwtKey,
+	}).InitSilencesRoutes(routeV1)
+
+	heartbeatsHandlers.NewHeartbeatsHandler(&heartbeatsHandlers.Config{
+		NatsHandlers: h.heartbeatsHandlers,
+		JWTKey:       h.jwtKey,
+	}).InitHeartbeatsRoutes(routeV1)
 }
@@ -0,0 +1,271 @@
+package heartbeats
+
+import (
+	"api-gateway-service/internal/transport/natshandlers/heartbeats"
+	pbheartbeats "api-gateway-service/proto/api-gateway/heartbeats"
+	"errors"
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	localID = "localID"
+)
+
+type heartbeatsHandler struct {
+	natsHandlers *heartbeats.HeartbeatsHandler
+	jwtKey       string
+}
+
+type Config struct {
+	JWTKey       string
+	NatsHandlers *heartbeats.HeartbeatsHandler
+}
+
+func NewHeartbeatsHandler(cfg *Config) *heartbeatsHandler {
+	return &heartbeatsHandler{
+		jwtKey:       cfg.JWTKey,
+		natsHandlers: cfg.NatsHandlers,
+	}
+}
+
+func (h *heartbeatsHandler) InitHeartbeatsRoutes(api fiber.Router) {
+	servicesRoute := api.Group("/heartbeats", h.deserializeMW)
+	servicesRoute.Post("/create", h.create)
+	servicesRoute.Get("/read", h.read)
+	servicesRoute.Delete("/delete", h.delete)
+}
+
+type (
+	// createReq needs either device_id or device_type, a device_type
+	// heartbeat expects messages from every device of the type. An empty
+	// message_type accepts messages of any type.
+	createReq struct {
+		Name          string `form:"name"           json:"name"           validate:"required"                                xml:"name"`
+		DeviceID      int32  `form:"device_id"      json:"device_id"      validate:"required_without=DeviceType,gte=0"       xml:"device_id"`
+		DeviceType    string `form:"device_type"    json:"device_type"    validate:"excluded_with=DeviceID"                  xml:"device_type"`
+		MessageType   string `form:"message_type"   json:"message_type"   validate:"omitempty"                               xml:"message_type"`
+		IntervalSec   int32  `form:"interval_sec"   json:"interval_sec"   validate:"required,gte=10"                         xml:"interval_sec"`
+		Subject       string `form:"subject"        json:"subject"        validate:"omitempty"                               xml:"subject"`
+		SeverityLevel string `form:"severity_level" json:"severity_level" validate:"omitempty" xml:"severity_level"`
+	}
+
+	createResp struct {
+		Data *pbheartbeats.CreateResp `json:"data"`
+	}
+)
+
+func (h *heartbeatsHandler) create(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body createReq
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishCreate(
+		&pbheartbeats.CreateReq{
+			Heartbeat: &pbheartbeats.Heartbeat{
+				Name:          body.Name,
+				DeviceID:      body.DeviceID,
+				DeviceType:    body.DeviceType,
+				MessageType:   body.MessageType,
+				IntervalSec:   body.IntervalSec,
+				Subject:       body.Subject,
+				SeverityLevel: body.SeverityLevel,
+			},
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishCreate: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&createResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+type (
+	readResp struct {
+		Data *pbheartbeats.ReadResp `json:"data"`
+	}
+)
+
+func (h *heartbeatsHandler) read(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishRead(&pbheartbeats.ReadReq{})
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishRead: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&readResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+	return nil
+}
+
+type (
+	deleteReq struct {
+		ID int32 `form:"id" json:"id" validate:"required" xml:"id"`
+	}
+
+	deleteResp struct {
+		Data int `json:"data"`
+	}
+)
+
+func (h *heartbeatsHandler) delete(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body deleteReq
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	err := h.natsHandlers.PublishDelete(
+		&pbheartbeats.DeleteReq{
+			ID: body.ID,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishDelete: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&deleteResp{
+			Data: fiber.StatusOK,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+	return nil
+}
+
+func (h *heartbeatsHandler) deserializeMW(ctx fiber.Ctx) error {
+	tokenString := ctx.Get("Authorization")
+
+	if tokenString == "" {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("tokenString is empty").Error(),
+		)
+	}
+
+	tokenString = strings.ReplaceAll(tokenString, "Bearer ", "")
+	token, err := jwt.Parse(tokenString, func(_ *jwt.Token) (interface{}, error) {
+		return []byte(h.jwtKey), nil
+	})
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			fmt.Errorf("jwt.Parse: %w", err).Error(),
+		)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("token.Claims.(jwt.MapClaims): invalid token").Error(),
+		)
+	}
+
+	userID, ok := claims[localID].(float64) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("claims["+localID+"].(float64): invalid token").Error(),
+		)
+	}
+
+	ctx.Locals(localID, int(userID))
+
+	return ctx.Next() //nolint:wrapcheck
+}
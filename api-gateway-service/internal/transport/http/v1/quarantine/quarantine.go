@@ -0,0 +1,245 @@
+package quarantine
+
+import (
+	"api-gateway-service/internal/transport/natshandlers/quarantine"
+	pbquarantine "api-gateway-service/proto/api-gateway/quarantine"
+	"errors"
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	localID = "localID"
+)
+
+type quarantineHandler struct {
+	natsHandlers *quarantine.QuarantineHandler
+	jwtKey       string
+}
+
+type Config struct {
+	JWTKey       string
+	NatsHandlers *quarantine.QuarantineHandler
+}
+
+func NewQuarantineHandler(cfg *Config) *quarantineHandler {
+	return &quarantineHandler{
+		jwtKey:       cfg.JWTKey,
+		natsHandlers: cfg.NatsHandlers,
+	}
+}
+
+func (h *quarantineHandler) InitQuarantineRoutes(api fiber.Router) {
+	servicesRoute := api.Group("/quarantine", h.deserializeMW)
+	servicesRoute.Get("/list", h.list)
+	servicesRoute.Post("/register", h.register)
+	servicesRoute.Post("/replay", h.replay)
+}
+
+type (
+	listResp struct {
+		Data *pbquarantine.ListResp `json:"data"`
+	}
+)
+
+func (h *quarantineHandler) list(ctx fiber.Ctx) error {
+	res, err := h.natsHandlers.PublishList()
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishList: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&listResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+type (
+	registerReq struct {
+		Address     string  `form:"address"      json:"address"      validate:"required,ip"    xml:"address"`
+		Name        string  `form:"name"         json:"name"         validate:"required"       xml:"name"`
+		DeviceType  string  `form:"device_type"  json:"device_type"  validate:"required"       xml:"device_type"`
+		Responsible []int32 `form:"responsible"  json:"responsible"  validate:"required"       xml:"responsible"`
+		Replay      bool    `form:"replay"       json:"replay"                                 xml:"replay"`
+	}
+
+	registerResp struct {
+		Data *pbquarantine.RegisterResp `json:"data"`
+	}
+)
+
+func (h *quarantineHandler) register(ctx fiber.Ctx) error {
+	body := registerReq{
+		Address:     "",
+		Name:        "",
+		DeviceType:  "",
+		Responsible: []int32{},
+		Replay:      false,
+	}
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishRegister(
+		&pbquarantine.RegisterReq{
+			Address:     body.Address,
+			Name:        body.Name,
+			DeviceType:  body.DeviceType,
+			Responsible: body.Responsible,
+			Replay:      body.Replay,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishRegister: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&registerResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+type (
+	replayReq struct {
+		Address string `form:"address" json:"address" validate:"required,ip" xml:"address"`
+	}
+
+	replayResp struct {
+		Data *pbquarantine.ReplayResp `json:"data"`
+	}
+)
+
+func (h *quarantineHandler) replay(ctx fiber.Ctx) error {
+	body := replayReq{
+		Address: "",
+	}
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishReplay(
+		&pbquarantine.ReplayReq{
+			Address: body.Address,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishReplay: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&replayResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+func (h *quarantineHandler) deserializeMW(ctx fiber.Ctx) error {
+	tokenString := ctx.Get("Authorization")
+
+	if tokenString == "" {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("tokenString is empty").Error(),
+		)
+	}
+
+	tokenString = strings.ReplaceAll(tokenString, "Bearer ", "")
+	token, err := jwt.Parse(tokenString, func(_ *jwt.Token) (interface{}, error) {
+		return []byte(h.jwtKey), nil
+	})
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			fmt.Errorf("jwt.Parse: %w", err).Error(),
+		)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("token.Claims.(jwt.MapClaims): invalid token").Error(),
+		)
+	}
+
+	userID, ok := claims[localID].(float64) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("claims["+localID+"].(float64): invalid token").Error(),
+		)
+	}
+
+	ctx.Locals(localID, int(userID))
+
+	return ctx.Next() //nolint:wrapcheck
+}
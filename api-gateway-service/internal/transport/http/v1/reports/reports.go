@@ -47,7 +47,8 @@ func (h *reportsHandler) InitReportsRoutes(api fiber.Router) {
 
 type (
 	getAllByDeviceIdReq struct {
-		DeviceID int `form:"device_id" json:"device_id" validate:"required" xml:"device_id"`
+		DeviceID    int  `form:"device_id"     json:"device_id"     validate:"required" xml:"device_id"`
+		ByEventTime bool `form:"by_event_time" json:"by_event_time"                      xml:"by_event_time"`
 	}
 
 	getAllByDeviceIdResp struct {
@@ -69,7 +70,8 @@ func (h *reportsHandler) getAllByDeviceId(ctx fiber.Ctx) error {
 
 	res, err := h.natsHandlers.PublishGetAllByDeviceId(
 		pbmessages.ReportGetAllByDeviceIdReq{
-			DeviceId: int32(body.DeviceID),
+			DeviceId:    int32(body.DeviceID),
+			ByEventTime: body.ByEventTime,
 		},
 	)
 	if err != nil {
@@ -105,6 +107,8 @@ type (
 	getAllByPeriodReq struct {
 		StartTime time.Time `form:"start_time" json:"start_time" validate:"required" xml:"start_time"`
 		EndTime   time.Time `form:"end_time"   json:"end_time"   validate:"required" xml:"end_time"`
+		// Filter and order by the time reported by the device.
+		ByEventTime bool `form:"by_event_time" json:"by_event_time" xml:"by_event_time"`
 	}
 
 	getAllByPeriodResp struct {
@@ -127,8 +131,9 @@ func (h *reportsHandler) getAllByPeriod(ctx fiber.Ctx) error {
 
 	res, err := h.natsHandlers.PublishGetAllByPeriod(
 		pbmessages.ReportGetAllByPeriodReq{
-			StartTime: timestamppb.New(body.StartTime),
-			EndTime:   timestamppb.New(body.EndTime),
+			StartTime:   timestamppb.New(body.StartTime),
+			EndTime:     timestamppb.New(body.EndTime),
+			ByEventTime: body.ByEventTime,
 		},
 	)
 	if err != nil {
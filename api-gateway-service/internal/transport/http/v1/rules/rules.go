@@ -0,0 +1,526 @@
+package rules
+
+import (
+	"api-gateway-service/internal/transport/natshandlers/rules"
+	pbrules "api-gateway-service/proto/api-gateway/rules"
+	"errors"
+	"fmt"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	localID = "localID"
+)
+
+type rulesHandler struct {
+	natsHandlers *rules.RulesHandler
+	jwtKey       string
+}
+
+type Config struct {
+	JWTKey       string
+	NatsHandlers *rules.RulesHandler
+}
+
+func NewRulesHandler(cfg *Config) *rulesHandler {
+	return &rulesHandler{
+		jwtKey:       cfg.JWTKey,
+		natsHandlers: cfg.NatsHandlers,
+	}
+}
+
+func (h *rulesHandler) InitRulesRoutes(api fiber.Router) {
+	servicesRoute := api.Group("/rules", h.deserializeMW)
+	servicesRoute.Post("/create", h.create)
+	servicesRoute.Get("/read", h.read)
+	servicesRoute.Put("/update", h.update)
+	servicesRoute.Delete("/delete", h.delete)
+	servicesRoute.Get("/baselines", h.readBaselines)
+}
+
+// conditionReq is either a group of conditions joined by op or, without op, a
+// check of one message field: "message" (the default), "component",
+// "message_type" or "attributes.<name>".
+type conditionReq struct {
+	Op         string         `json:"op"           validate:"omitempty,oneof=and or"`
+	Conditions []conditionReq `json:"conditions"   validate:"required_with=Op,omitempty,dive"`
+
+	Field       string `json:"field"        validate:"omitempty"`
+	Regexp      string `json:"regexp"       validate:"required_without=Op"`
+	ArrayIndex  int32  `json:"array_index"  validate:"gte=0"`
+	CompareType string `json:"compare_type" validate:"omitempty,oneof='<' '>' '=' '!=' '<=' '>='"`
+	Value       string `json:"value"        validate:"required_with=CompareType"`
+}
+
+func conditionsToProto(conditions []conditionReq) []*pbrules.Condition {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	result := make([]*pbrules.Condition, 0, len(conditions))
+	for _, c := range conditions {
+		result = append(result, &pbrules.Condition{
+			Op:          c.Op,
+			Conditions:  conditionsToProto(c.Conditions),
+			Field:       c.Field,
+			Regexp:      c.Regexp,
+			ArrayIndex:  c.ArrayIndex,
+			CompareType: c.CompareType,
+			Value:       c.Value,
+		})
+	}
+	return result
+}
+
+// windowReq makes the rule aggregate the messages passing its conditions
+// instead of firing on each. Without regexp every message counts as 1.
+type windowReq struct {
+	Type        string  `json:"type"         validate:"required,oneof=sliding tumbling"`
+	DurationSec int64   `json:"duration_sec" validate:"required,gte=1,lte=86400"`
+	Aggregation string  `json:"aggregation"  validate:"required,oneof=count sum avg min max rate"`
+	Field       string  `json:"field"        validate:"omitempty"`
+	Regexp      string  `json:"regexp"       validate:"required_unless=Aggregation count Aggregation rate"`
+	ArrayIndex  int32   `json:"array_index"  validate:"gte=0"`
+	CompareType string  `json:"compare_type" validate:"required,oneof='<' '>' '=' '!=' '<=' '>='"`
+	Threshold   float64 `json:"threshold"`
+}
+
+func windowToProto(window *windowReq) *pbrules.Window {
+	if window == nil {
+		return nil
+	}
+
+	return &pbrules.Window{
+		Type:        window.Type,
+		DurationSec: window.DurationSec,
+		Aggregation: window.Aggregation,
+		Field:       window.Field,
+		Regexp:      window.Regexp,
+		ArrayIndex:  window.ArrayIndex,
+		CompareType: window.CompareType,
+		Threshold:   window.Threshold,
+	}
+}
+
+// anomalyReq makes the rule learn a baseline per device of the number
+// extracted by regexp and fire once a value deviates from it by more than
+// sigma standard deviations, instead of firing on each message. Left out
+// alpha, sigma and min_samples get defaults.
+type anomalyReq struct {
+	Field       string  `json:"field"        validate:"omitempty"`
+	Regexp      string  `json:"regexp"       validate:"required"`
+	ArrayIndex  int32   `json:"array_index"  validate:"gte=0"`
+	Alpha       float64 `json:"alpha"        validate:"gte=0,lte=1"`
+	Sigma       float64 `json:"sigma"        validate:"gte=0"`
+	Direction   string  `json:"direction"    validate:"omitempty,oneof=above below"`
+	Seasonality string  `json:"seasonality"  validate:"omitempty,oneof=hour_of_day hour_of_week"`
+	MinSamples  int32   `json:"min_samples"  validate:"gte=0"`
+}
+
+func anomalyToProto(anomaly *anomalyReq) *pbrules.Anomaly {
+	if anomaly == nil {
+		return nil
+	}
+
+	return &pbrules.Anomaly{
+		Field:       anomaly.Field,
+		Regexp:      anomaly.Regexp,
+		ArrayIndex:  anomaly.ArrayIndex,
+		Alpha:       anomaly.Alpha,
+		Sigma:       anomaly.Sigma,
+		Direction:   anomaly.Direction,
+		Seasonality: anomaly.Seasonality,
+		MinSamples:  anomaly.MinSamples,
+	}
+}
+
+type (
+	createReq struct {
+		Name          string         `form:"name"           json:"name"           validate:"required"              xml:"name"`
+		DeviceID      int32          `form:"device_id"      json:"device_id"      validate:"gte=0"                 xml:"device_id"`
+		Op            string         `form:"op"             json:"op"             validate:"required,oneof=and or" xml:"op"`
+		Conditions    []conditionReq `form:"conditions"     json:"conditions"     validate:"required,min=1,dive"   xml:"conditions"`
+		Window        *windowReq     `form:"window"         json:"window"         validate:"omitempty"             xml:"window"`
+		Anomaly       *anomalyReq    `form:"anomaly"        json:"anomaly"        validate:"excluded_with=Window"  xml:"anomaly"`
+		Subject       string         `form:"subject"        json:"subject"        validate:"required"              xml:"subject"`
+		SeverityLevel string         `form:"severity_level" json:"severity_level" validate:"omitempty"             xml:"severity_level"`
+	}
+
+	createResp struct {
+		Data *pbrules.CreateResp `json:"data"`
+	}
+)
+
+func (h *rulesHandler) create(ctx fiber.Ctx) error {
+	var body createReq
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishCreate(
+		&pbrules.CreateReq{
+			Rule: &pbrules.Rule{
+				Name:          body.Name,
+				DeviceID:      body.DeviceID,
+				Op:            body.Op,
+				Conditions:    conditionsToProto(body.Conditions),
+				Window:        windowToProto(body.Window),
+				Anomaly:       anomalyToProto(body.Anomaly),
+				Subject:       body.Subject,
+				SeverityLevel: body.SeverityLevel,
+			},
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.nats.PublishCreate: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&createResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+type (
+	read struct {
+		Data *pbrules.ReadResp `json:"data"`
+	}
+)
+
+func (h *rulesHandler) read(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishRead()
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishRead: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&read{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+	return nil
+}
+
+type (
+	// updateReq replaces the conditions, the window and the anomaly as a
+	// whole if they are given, giving one of the last two removes the other.
+	// remove_window and remove_anomaly turn the rule back into a plain one.
+	updateReq struct {
+		ID            int32          `form:"id"             json:"id"             validate:"required"               xml:"id"`
+		Name          string         `form:"name"           json:"name"           validate:"omitempty"              xml:"name"`
+		DeviceID      int32          `form:"device_id"      json:"device_id"      validate:"omitempty"              xml:"device_id"`
+		Op            string         `form:"op"             json:"op"             validate:"omitempty,oneof=and or" xml:"op"`
+		Conditions    []conditionReq `form:"conditions"     json:"conditions"     validate:"omitempty,dive"         xml:"conditions"`
+		Window        *windowReq     `form:"window"         json:"window"         validate:"omitempty"              xml:"window"`
+		RemoveWindow  bool           `form:"remove_window"  json:"remove_window"  validate:"excluded_with=Window"   xml:"remove_window"`
+		Anomaly       *anomalyReq    `form:"anomaly"        json:"anomaly"        validate:"excluded_with=Window"   xml:"anomaly"`
+		RemoveAnomaly bool           `form:"remove_anomaly" json:"remove_anomaly" validate:"excluded_with=Anomaly"  xml:"remove_anomaly"`
+		Subject       string         `form:"subject"        json:"subject"        validate:"omitempty"              xml:"subject"`
+		SeverityLevel string         `form:"severity_level" json:"severity_level" validate:"omitempty"              xml:"severity_level"`
+	}
+
+	updateResp struct {
+		Data int `json:"data"`
+	}
+)
+
+func (h *rulesHandler) update(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body updateReq
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	if body.Name == "" && body.DeviceID == 0 && body.Op == "" &&
+		len(body.Conditions) == 0 && body.Window == nil && !body.RemoveWindow &&
+		body.Anomaly == nil && !body.RemoveAnomaly &&
+		body.Subject == "" && body.SeverityLevel == "" {
+		return fiber.NewError(
+			fiber.StatusBadRequest,
+			errors.New("nothing to update").Error(),
+		)
+	}
+
+	err := h.natsHandlers.PublishUpdate(
+		&pbrules.UpdateReq{
+			Rule: &pbrules.Rule{
+				ID:            body.ID,
+				Name:          body.Name,
+				DeviceID:      body.DeviceID,
+				Op:            body.Op,
+				Conditions:    conditionsToProto(body.Conditions),
+				Window:        windowToProto(body.Window),
+				Anomaly:       anomalyToProto(body.Anomaly),
+				Subject:       body.Subject,
+				SeverityLevel: body.SeverityLevel,
+			},
+			RemoveWindow:  body.RemoveWindow,
+			RemoveAnomaly: body.RemoveAnomaly,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishUpdate: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&updateResp{
+			Data: fiber.StatusOK,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+	return nil
+}
+
+type (
+	deleteReq struct {
+		ID int `form:"id"     json:"id"     validate:"required"            xml:"id"`
+	}
+
+	deleteResp struct {
+		Data int `json:"data"`
+	}
+)
+
+func (h *rulesHandler) delete(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body deleteReq
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	err := h.natsHandlers.PublishDelete(
+		&pbrules.DeleteReq{
+			ID: int32(body.ID),
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishDelete: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&deleteResp{
+			Data: fiber.StatusOK,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+type (
+	// readBaselinesReq filters by the ids that are set.
+	readBaselinesReq struct {
+		RuleID   int32 `form:"rule_id"   json:"rule_id"   validate:"gte=0" xml:"rule_id"`
+		DeviceID int32 `form:"device_id" json:"device_id" validate:"gte=0" xml:"device_id"`
+	}
+
+	readBaselinesResp struct {
+		Data *pbrules.ReadBaselinesResp `json:"data"`
+	}
+)
+
+// readBaselines shows what the anomaly rules learned per device, to see why
+// one fired.
+func (h *rulesHandler) readBaselines(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body readBaselinesReq
+
+	if len(ctx.Body()) > 0 {
+		if err := ctx.Bind().Body(&body); err != nil {
+			return fiber.NewError(
+				fiber.StatusUnprocessableEntity,
+				fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+			)
+		}
+	}
+
+	res, err := h.natsHandlers.PublishReadBaselines(
+		&pbrules.ReadBaselinesReq{
+			RuleID:   body.RuleID,
+			DeviceID: body.DeviceID,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishReadBaselines: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&readBaselinesResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+	return nil
+}
+
+func (h *rulesHandler) deserializeMW(ctx fiber.Ctx) error {
+	tokenString := ctx.Get("Authorization")
+
+	if tokenString == "" {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("tokenString is empty").Error(),
+		)
+	}
+
+	tokenString = strings.ReplaceAll(tokenString, "Bearer ", "")
+	token, err := jwt.Parse(tokenString, func(_ *jwt.Token) (interface{}, error) {
+		return []byte(h.jwtKey), nil
+	})
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			fmt.Errorf("jwt.Parse: %w", err).Error(),
+		)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("token.Claims.(jwt.MapClaims): invalid token").Error(),
+		)
+	}
+
+	userID, ok := claims[localID].(float64) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("claims["+localID+"].(float64): invalid token").Error(),
+		)
+	}
+
+	ctx.Locals(localID, int(userID))
+
+	return ctx.Next() //nolint:wrapcheck
+}
@@ -0,0 +1,297 @@
+package silences
+
+import (
+	"api-gateway-service/internal/transport/natshandlers/silences"
+	pbsilences "api-gateway-service/proto/api-gateway/silences"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	localID = "localID"
+)
+
+type silencesHandler struct {
+	natsHandlers *silences.SilencesHandler
+	jwtKey       string
+}
+
+type Config struct {
+	JWTKey       string
+	NatsHandlers *silences.SilencesHandler
+}
+
+func NewSilencesHandler(cfg *Config) *silencesHandler {
+	return &silencesHandler{
+		jwtKey:       cfg.JWTKey,
+		natsHandlers: cfg.NatsHandlers,
+	}
+}
+
+func (h *silencesHandler) InitSilencesRoutes(api fiber.Router) {
+	servicesRoute := api.Group("/silences", h.deserializeMW)
+	servicesRoute.Post("/create", h.create)
+	servicesRoute.Get("/read", h.read)
+	servicesRoute.Delete("/delete", h.delete)
+}
+
+type (
+	// createReq needs at least one matcher. A recurring silence is active
+	// for duration_sec seconds after each time the cron recurrence fires
+	// between starts_at and ends_at.
+	createReq struct {
+		DeviceID    int32     `form:"device_id"    json:"device_id"    validate:"required_without_all=DeviceType RuleName Component,gte=0" xml:"device_id"`
+		DeviceType  string    `form:"device_type"  json:"device_type"  validate:"omitempty"                                              xml:"device_type"`
+		RuleName    string    `form:"rule_name"    json:"rule_name"    validate:"omitempty"                                              xml:"rule_name"`
+		Component   string    `form:"component"    json:"component"    validate:"omitempty"                                              xml:"component"`
+		StartsAt    time.Time `form:"starts_at"    json:"starts_at"    validate:"required"                                               xml:"starts_at"`
+		EndsAt      time.Time `form:"ends_at"      json:"ends_at"      validate:"required,gtfield=StartsAt"                              xml:"ends_at"`
+		Recurrence  string    `form:"recurrence"   json:"recurrence"   validate:"required_with=DurationSec"                              xml:"recurrence"`
+		DurationSec int32     `form:"duration_sec" json:"duration_sec" validate:"required_with=Recurrence,gte=0,lte=604800"              xml:"duration_sec"`
+		Comment     string    `form:"comment"      json:"comment"      validate:"omitempty"                                              xml:"comment"`
+	}
+
+	createResp struct {
+		Data *pbsilences.CreateResp `json:"data"`
+	}
+)
+
+func (h *silencesHandler) create(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	userID, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body createReq
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishCreate(
+		&pbsilences.CreateReq{
+			Silence: &pbsilences.Silence{
+				DeviceID:    body.DeviceID,
+				DeviceType:  body.DeviceType,
+				RuleName:    body.RuleName,
+				Component:   body.Component,
+				StartsAt:    timestamppb.New(body.StartsAt),
+				EndsAt:      timestamppb.New(body.EndsAt),
+				Recurrence:  body.Recurrence,
+				DurationSec: body.DurationSec,
+				CreatedBy:   int32(userID),
+				Comment:     body.Comment,
+			},
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishCreate: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&createResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+type (
+	readReq struct {
+		WithExpired bool `form:"with_expired" json:"with_expired" xml:"with_expired"`
+	}
+
+	readResp struct {
+		Data *pbsilences.ReadResp `json:"data"`
+	}
+)
+
+func (h *silencesHandler) read(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body readReq
+
+	if len(ctx.Body()) > 0 {
+		if err := ctx.Bind().Body(&body); err != nil {
+			return fiber.NewError(
+				fiber.StatusUnprocessableEntity,
+				fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+			)
+		}
+	}
+
+	res, err := h.natsHandlers.PublishRead(
+		&pbsilences.ReadReq{
+			WithExpired: body.WithExpired,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishRead: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&readResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+	return nil
+}
+
+type (
+	deleteReq struct {
+		ID int32 `form:"id" json:"id" validate:"required" xml:"id"`
+	}
+
+	deleteResp struct {
+		Data int `json:"data"`
+	}
+)
+
+func (h *silencesHandler) delete(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body deleteReq
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	err := h.natsHandlers.PublishDelete(
+		&pbsilences.DeleteReq{
+			ID: body.ID,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishDelete: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&deleteResp{
+			Data: fiber.StatusOK,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+	return nil
+}
+
+func (h *silencesHandler) deserializeMW(ctx fiber.Ctx) error {
+	tokenString := ctx.Get("Authorization")
+
+	if tokenString == "" {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("tokenString is empty").Error(),
+		)
+	}
+
+	tokenString = strings.ReplaceAll(tokenString, "Bearer ", "")
+	token, err := jwt.Parse(tokenString, func(_ *jwt.Token) (interface{}, error) {
+		return []byte(h.jwtKey), nil
+	})
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			fmt.Errorf("jwt.Parse: %w", err).Error(),
+		)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("token.Claims.(jwt.MapClaims): invalid token").Error(),
+		)
+	}
+
+	userID, ok := claims[localID].(float64) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("claims["+localID+"].(float64): invalid token").Error(),
+		)
+	}
+
+	ctx.Locals(localID, int(userID))
+
+	return ctx.Next() //nolint:wrapcheck
+}
@@ -6,11 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 
 	"github.com/gofiber/fiber/v3"
 	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
@@ -40,7 +42,8 @@ func (h *tagsHandler) InitTagsRoutes(api fiber.Router) {
 	servicesRoute.Get("/read", h.read)
 	servicesRoute.Put("/update", h.update)
 	servicesRoute.Delete("/delete", h.delete)
-
+	servicesRoute.Post("/test", h.test)
+	servicesRoute.Get("/version", h.version)
 }
 
 type (
@@ -323,6 +326,173 @@ func (h *tagsHandler) delete(ctx fiber.Ctx) error {
 	return nil
 }
 
+type (
+	testMessageReq struct {
+		Message     string            `json:"message"      validate:"required"`
+		MessageType string            `json:"message_type" validate:"omitempty"`
+		Component   string            `json:"component"    validate:"omitempty"`
+		Attributes  map[string]string `json:"attributes"   validate:"omitempty"`
+		ReceivedAt  *time.Time        `json:"received_at"  validate:"omitempty"`
+	}
+
+	// testReq runs a draft tag against messages or, without them, against
+	// the stored messages of the device from start_time to end_time. The id
+	// of a stored tag tests a change of it. Nothing is saved or sent.
+	testReq struct {
+		ID            int32            `form:"id"             json:"id"             validate:"gte=0"                                   xml:"id"`
+		DeviceID      int32            `form:"device_id"      json:"device_id"      validate:"required"                                xml:"device_id"`
+		Regexp        string           `form:"regexp"         json:"regexp"         validate:"required"                                xml:"regexp"`
+		CompareType   string           `form:"compare_type"   json:"compare_type"   validate:"required,oneof='<' '>' '='"              xml:"compare_type"`
+		Value         string           `form:"value"          json:"value"          validate:"required"                                xml:"value"`
+		ArrayIndex    int32            `form:"array_index"    json:"array_index"    validate:"gte=0"                                   xml:"array_index"`
+		Subject       string           `form:"subject"        json:"subject"        validate:"omitempty"                               xml:"subject"`
+		SeverityLevel string           `form:"severity_level" json:"severity_level" validate:"omitempty"                               xml:"severity_level"`
+		Messages      []testMessageReq `form:"messages"       json:"messages"       validate:"required_without=EndTime,max=1000,dive"  xml:"messages"`
+		StartTime     *time.Time       `form:"start_time"     json:"start_time"     validate:"required_with=EndTime"                   xml:"start_time"`
+		EndTime       *time.Time       `form:"end_time"       json:"end_time"       validate:"omitempty,gtfield=StartTime"             xml:"end_time"`
+	}
+
+	testResp struct {
+		Data *pbtags.TestResp `json:"data"`
+	}
+)
+
+func (h *tagsHandler) test(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	var body testReq
+
+	if err := ctx.Bind().Body(&body); err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("ctx.Bind().Body: %w", err).Error(),
+		)
+	}
+
+	messages := make([]*pbtags.TestMessage, 0, len(body.Messages))
+	for _, m := range body.Messages {
+		var receivedAt *timestamppb.Timestamp
+		if m.ReceivedAt != nil {
+			receivedAt = timestamppb.New(*m.ReceivedAt)
+		}
+		messages = append(messages, &pbtags.TestMessage{
+			Message:     m.Message,
+			MessageType: m.MessageType,
+			Component:   m.Component,
+			Attributes:  m.Attributes,
+			ReceivedAt:  receivedAt,
+		})
+	}
+
+	var startTime, endTime *timestamppb.Timestamp
+	if body.StartTime != nil {
+		startTime = timestamppb.New(*body.StartTime)
+	}
+	if body.EndTime != nil {
+		endTime = timestamppb.New(*body.EndTime)
+	}
+
+	res, err := h.natsHandlers.PublishTest(
+		&pbtags.TestReq{
+			Tag: &pbtags.Tag{
+				ID:            body.ID,
+				DeviceID:      body.DeviceID,
+				Regexp:        body.Regexp,
+				CompareType:   body.CompareType,
+				Value:         body.Value,
+				ArrayIndex:    body.ArrayIndex,
+				Subject:       body.Subject,
+				SeverityLevel: body.SeverityLevel,
+			},
+			Messages:  messages,
+			StartTime: startTime,
+			EndTime:   endTime,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishTest: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&testResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+type (
+	// versionResp lists the tag set version each data-processing replica
+	// has loaded, they differ while a change is spreading.
+	versionResp struct {
+		Data []*pbtags.VersionResp `json:"data"`
+	}
+)
+
+func (h *tagsHandler) version(ctx fiber.Ctx) error {
+	idLocals := ctx.Locals(localID)
+	_, ok := idLocals.(int) //nolint:varnamelen
+	if !ok {
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New("idLocals.(int): invalid token").Error(),
+		)
+	}
+
+	res, err := h.natsHandlers.PublishVersion()
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("h.natsHandlers.PublishVersion: %w", err).Error(),
+		)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(
+		&versionResp{
+			Data: res,
+		},
+	)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	if err = ctx.Status(fiber.StatusOK).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
 func (h *tagsHandler) deserializeMW(ctx fiber.Ctx) error {
 	tokenString := ctx.Get("Authorization")
 
@@ -0,0 +1,108 @@
+package alerts
+
+import (
+	pbalerts "api-gateway-service/proto/api-gateway/alerts"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+type AlertsHandler struct {
+	natsConn *nats.Conn
+	timeout  time.Duration
+}
+
+type Config struct {
+	NatsConn *nats.Conn
+	Timeout  time.Duration
+}
+
+func NewAlertsHandlers(cfg Config) *AlertsHandler {
+	return &AlertsHandler{
+		natsConn: cfg.NatsConn,
+		timeout:  cfg.Timeout,
+	}
+}
+
+const (
+	alertsReadSubject = "alerts.read"
+)
+
+func (n *AlertsHandler) PublishRead(req *pbalerts.ReadReq) (*pbalerts.ReadResp, error) {
+	readReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(alertsReadSubject, readReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbalerts.ReadResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	alertsAcknowledgeSubject = "alerts.acknowledge"
+)
+
+func (n *AlertsHandler) PublishAcknowledge(req *pbalerts.AcknowledgeReq) (*pbalerts.AcknowledgeResp, error) {
+	acknowledgeReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(alertsAcknowledgeSubject, acknowledgeReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbalerts.AcknowledgeResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	alertsResolveSubject = "alerts.resolve"
+)
+
+func (n *AlertsHandler) PublishResolve(req *pbalerts.ResolveReq) (*pbalerts.ResolveResp, error) {
+	resolveReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(alertsResolveSubject, resolveReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbalerts.ResolveResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
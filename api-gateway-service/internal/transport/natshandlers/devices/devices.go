@@ -126,3 +126,30 @@ func (n *DevicesHandler) PublishDelete(req pbdevices.DeleteReq) error {
 	}
 	return nil
 }
+
+const (
+	devicesRotateKeySubject = "devices.rotate_key"
+)
+
+func (n *DevicesHandler) PublishRotateKey(req *pbdevices.RotateKeyReq) (*pbdevices.RotateKeyResp, error) {
+	rotateKeyReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(devicesRotateKeySubject, rotateKeyReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbdevices.RotateKeyResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
@@ -0,0 +1,108 @@
+package heartbeats
+
+import (
+	pbheartbeats "api-gateway-service/proto/api-gateway/heartbeats"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+type HeartbeatsHandler struct {
+	natsConn *nats.Conn
+	timeout  time.Duration
+}
+
+type Config struct {
+	NatsConn *nats.Conn
+	Timeout  time.Duration
+}
+
+func NewHeartbeatsHandlers(cfg Config) *HeartbeatsHandler {
+	return &HeartbeatsHandler{
+		natsConn: cfg.NatsConn,
+		timeout:  cfg.Timeout,
+	}
+}
+
+const (
+	heartbeatsCreateSubject = "heartbeats.create"
+)
+
+func (n *HeartbeatsHandler) PublishCreate(req *pbheartbeats.CreateReq) (*pbheartbeats.CreateResp, error) {
+	createReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(heartbeatsCreateSubject, createReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbheartbeats.CreateResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	heartbeatsReadSubject = "heartbeats.read"
+)
+
+func (n *HeartbeatsHandler) PublishRead(req *pbheartbeats.ReadReq) (*pbheartbeats.ReadResp, error) {
+	readReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(heartbeatsReadSubject, readReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbheartbeats.ReadResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	heartbeatsDeleteSubject = "heartbeats.delete"
+)
+
+func (n *HeartbeatsHandler) PublishDelete(req *pbheartbeats.DeleteReq) error {
+	deleteReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(heartbeatsDeleteSubject, deleteReqBytes, n.timeout)
+	if err != nil {
+		return fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbheartbeats.DeleteResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package quarantine
+
+import (
+	pbquarantine "api-gateway-service/proto/api-gateway/quarantine"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+type QuarantineHandler struct {
+	natsConn *nats.Conn
+	timeout  time.Duration
+}
+
+type Config struct {
+	NatsConn *nats.Conn
+	Timeout  time.Duration
+}
+
+func NewQuarantineHandlers(cfg Config) *QuarantineHandler {
+	return &QuarantineHandler{
+		natsConn: cfg.NatsConn,
+		timeout:  cfg.Timeout,
+	}
+}
+
+const (
+	quarantineListSubject = "quarantine.list"
+)
+
+func (n *QuarantineHandler) PublishList() (*pbquarantine.ListResp, error) {
+	replyMsg, err := n.natsConn.Request(quarantineListSubject, nil, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbquarantine.ListResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	quarantineRegisterSubject = "quarantine.register"
+)
+
+func (n *QuarantineHandler) PublishRegister(req *pbquarantine.RegisterReq) (*pbquarantine.RegisterResp, error) {
+	registerReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(quarantineRegisterSubject, registerReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbquarantine.RegisterResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	quarantineReplaySubject = "quarantine.replay"
+)
+
+func (n *QuarantineHandler) PublishReplay(req *pbquarantine.ReplayReq) (*pbquarantine.ReplayResp, error) {
+	replayReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(quarantineReplaySubject, replayReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbquarantine.ReplayResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
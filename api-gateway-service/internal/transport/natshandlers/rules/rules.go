@@ -0,0 +1,155 @@
+package rules
+
+import (
+	pbrules "api-gateway-service/proto/api-gateway/rules"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+type RulesHandler struct {
+	natsConn *nats.Conn
+	timeout  time.Duration
+}
+
+type Config struct {
+	NatsConn *nats.Conn
+	Timeout  time.Duration
+}
+
+func NewRulesHandlers(cfg Config) *RulesHandler {
+	return &RulesHandler{
+		natsConn: cfg.NatsConn,
+		timeout:  cfg.Timeout,
+	}
+}
+
+const (
+	rulesCreateSubject = "rules.create"
+)
+
+func (n *RulesHandler) PublishCreate(req *pbrules.CreateReq) (*pbrules.CreateResp, error) {
+	createReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+	replyMsg, err := n.natsConn.Request(rulesCreateSubject, createReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbrules.CreateResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	rulesReadSubject = "rules.read"
+)
+
+func (n *RulesHandler) PublishRead() (*pbrules.ReadResp, error) {
+	replyMsg, err := n.natsConn.Request(rulesReadSubject, nil, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbrules.ReadResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	rulesUpdateSubject = "rules.update"
+)
+
+func (n *RulesHandler) PublishUpdate(req *pbrules.UpdateReq) error {
+	updateReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(rulesUpdateSubject, updateReqBytes, n.timeout)
+	if err != nil {
+		return fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbrules.UpdateResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return nil
+}
+
+const (
+	rulesDeleteSubject = "rules.delete"
+)
+
+func (n *RulesHandler) PublishDelete(req *pbrules.DeleteReq) error {
+	deleteReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(rulesDeleteSubject, deleteReqBytes, n.timeout)
+	if err != nil {
+		return fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbrules.DeleteResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return nil
+}
+
+const (
+	rulesReadBaselinesSubject = "rules.baselines"
+)
+
+func (n *RulesHandler) PublishReadBaselines(req *pbrules.ReadBaselinesReq) (*pbrules.ReadBaselinesResp, error) {
+	readReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(rulesReadBaselinesSubject, readReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbrules.ReadBaselinesResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
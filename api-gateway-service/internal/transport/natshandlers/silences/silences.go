@@ -0,0 +1,108 @@
+package silences
+
+import (
+	pbsilences "api-gateway-service/proto/api-gateway/silences"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"google.golang.org/protobuf/proto"
+)
+
+type SilencesHandler struct {
+	natsConn *nats.Conn
+	timeout  time.Duration
+}
+
+type Config struct {
+	NatsConn *nats.Conn
+	Timeout  time.Duration
+}
+
+func NewSilencesHandlers(cfg Config) *SilencesHandler {
+	return &SilencesHandler{
+		natsConn: cfg.NatsConn,
+		timeout:  cfg.Timeout,
+	}
+}
+
+const (
+	silencesCreateSubject = "silences.create"
+)
+
+func (n *SilencesHandler) PublishCreate(req *pbsilences.CreateReq) (*pbsilences.CreateResp, error) {
+	createReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(silencesCreateSubject, createReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbsilences.CreateResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	silencesReadSubject = "silences.read"
+)
+
+func (n *SilencesHandler) PublishRead(req *pbsilences.ReadReq) (*pbsilences.ReadResp, error) {
+	readReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(silencesReadSubject, readReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbsilences.ReadResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	silencesDeleteSubject = "silences.delete"
+)
+
+func (n *SilencesHandler) PublishDelete(req *pbsilences.DeleteReq) error {
+	deleteReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(silencesDeleteSubject, deleteReqBytes, n.timeout)
+	if err != nil {
+		return fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbsilences.DeleteResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package tags
 
 import (
 	pbtags "api-gateway-service/proto/api-gateway/tags"
+	"errors"
 	"fmt"
 	"time"
 
@@ -126,3 +127,75 @@ func (n *TagsHandler) PublishDelete(req pbtags.DeleteReq) error {
 	}
 	return nil
 }
+
+const (
+	tagsTestSubject = "tags.test"
+)
+
+func (n *TagsHandler) PublishTest(req *pbtags.TestReq) (*pbtags.TestResp, error) {
+	testReqBytes, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(tagsTestSubject, testReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbtags.TestResp
+	err = proto.Unmarshal(replyMsg.Data, &reply)
+	if err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	if reply.Error != "" {
+		return nil, fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+	return &reply, nil
+}
+
+const (
+	tagsVersionSubject = "tags.version"
+	// Every data-processing replica answers, the answers are collected for
+	// this long.
+	tagsVersionWait = time.Second
+)
+
+// PublishVersion returns the tag set version loaded by each replica that
+// answered in time.
+func (n *TagsHandler) PublishVersion() ([]*pbtags.VersionResp, error) {
+	inbox := n.natsConn.NewInbox()
+	sub, err := n.natsConn.SubscribeSync(inbox)
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.SubscribeSync: %w", err)
+	}
+	defer sub.Unsubscribe() //nolint:errcheck
+
+	if err = n.natsConn.PublishRequest(tagsVersionSubject, inbox, nil); err != nil {
+		return nil, fmt.Errorf("natsConn.PublishRequest: %w", err)
+	}
+
+	replies := make([]*pbtags.VersionResp, 0)
+	deadline := time.Now().Add(min(tagsVersionWait, n.timeout))
+	for {
+		replyMsg, err := sub.NextMsg(time.Until(deadline))
+		if errors.Is(err, nats.ErrTimeout) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sub.NextMsg: %w", err)
+		}
+
+		var reply pbtags.VersionResp
+		if err = proto.Unmarshal(replyMsg.Data, &reply); err != nil {
+			return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+		}
+		replies = append(replies, &reply)
+	}
+
+	if len(replies) == 0 {
+		return nil, errors.New("no data-processing replica answered")
+	}
+	return replies, nil
+}
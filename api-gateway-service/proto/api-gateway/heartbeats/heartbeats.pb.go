@@ -0,0 +1,501 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: heartbeats.proto
+
+package pbheartbeats
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Heartbeat expects a message, of MessageType if it is set, at least every
+// IntervalSec seconds from the device with DeviceID or from every device of
+// DeviceType. A missed one raises an alert that resolves once a message
+// arrives again.
+type Heartbeat struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	DeviceID      int32                  `protobuf:"varint,3,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,4,opt,name=DeviceType,proto3" json:"DeviceType,omitempty"`
+	MessageType   string                 `protobuf:"bytes,5,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
+	IntervalSec   int32                  `protobuf:"varint,6,opt,name=IntervalSec,proto3" json:"IntervalSec,omitempty"`
+	Subject       string                 `protobuf:"bytes,7,opt,name=Subject,proto3" json:"Subject,omitempty"`
+	SeverityLevel string                 `protobuf:"bytes,8,opt,name=SeverityLevel,proto3" json:"SeverityLevel,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_heartbeats_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_heartbeats_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_heartbeats_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Heartbeat) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetDeviceID() int32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetIntervalSec() int32 {
+	if x != nil {
+		return x.IntervalSec
+	}
+	return 0
+}
+
+func (x *Heartbeat) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetSeverityLevel() string {
+	if x != nil {
+		return x.SeverityLevel
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Heartbeat     *Heartbeat             `protobuf:"bytes,1,opt,name=Heartbeat,proto3" json:"Heartbeat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReq) Reset() {
+	*x = CreateReq{}
+	mi := &file_heartbeats_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReq) ProtoMessage() {}
+
+func (x *CreateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_heartbeats_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReq.ProtoReflect.Descriptor instead.
+func (*CreateReq) Descriptor() ([]byte, []int) {
+	return file_heartbeats_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateReq) GetHeartbeat() *Heartbeat {
+	if x != nil {
+		return x.Heartbeat
+	}
+	return nil
+}
+
+type CreateResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       *Heartbeat             `protobuf:"bytes,1,opt,name=Created,proto3" json:"Created,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResp) Reset() {
+	*x = CreateResp{}
+	mi := &file_heartbeats_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResp) ProtoMessage() {}
+
+func (x *CreateResp) ProtoReflect() protoreflect.Message {
+	mi := &file_heartbeats_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResp.ProtoReflect.Descriptor instead.
+func (*CreateResp) Descriptor() ([]byte, []int) {
+	return file_heartbeats_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateResp) GetCreated() *Heartbeat {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *CreateResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ReadReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadReq) Reset() {
+	*x = ReadReq{}
+	mi := &file_heartbeats_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadReq) ProtoMessage() {}
+
+func (x *ReadReq) ProtoReflect() protoreflect.Message {
+	mi := &file_heartbeats_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadReq.ProtoReflect.Descriptor instead.
+func (*ReadReq) Descriptor() ([]byte, []int) {
+	return file_heartbeats_proto_rawDescGZIP(), []int{3}
+}
+
+type ReadResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Heartbeats    []*Heartbeat           `protobuf:"bytes,1,rep,name=Heartbeats,proto3" json:"Heartbeats,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadResp) Reset() {
+	*x = ReadResp{}
+	mi := &file_heartbeats_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadResp) ProtoMessage() {}
+
+func (x *ReadResp) ProtoReflect() protoreflect.Message {
+	mi := &file_heartbeats_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadResp.ProtoReflect.Descriptor instead.
+func (*ReadResp) Descriptor() ([]byte, []int) {
+	return file_heartbeats_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReadResp) GetHeartbeats() []*Heartbeat {
+	if x != nil {
+		return x.Heartbeats
+	}
+	return nil
+}
+
+func (x *ReadResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DeleteReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReq) Reset() {
+	*x = DeleteReq{}
+	mi := &file_heartbeats_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReq) ProtoMessage() {}
+
+func (x *DeleteReq) ProtoReflect() protoreflect.Message {
+	mi := &file_heartbeats_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReq.ProtoReflect.Descriptor instead.
+func (*DeleteReq) Descriptor() ([]byte, []int) {
+	return file_heartbeats_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteReq) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+type DeleteResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResp) Reset() {
+	*x = DeleteResp{}
+	mi := &file_heartbeats_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResp) ProtoMessage() {}
+
+func (x *DeleteResp) ProtoReflect() protoreflect.Message {
+	mi := &file_heartbeats_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResp.ProtoReflect.Descriptor instead.
+func (*DeleteResp) Descriptor() ([]byte, []int) {
+	return file_heartbeats_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_heartbeats_proto protoreflect.FileDescriptor
+
+const file_heartbeats_proto_rawDesc = "" +
+	"\n" +
+	"\x10heartbeats.proto\x12\fpbheartbeats\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa9\x02\n" +
+	"\tHeartbeat\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\x12\x12\n" +
+	"\x04Name\x18\x02 \x01(\tR\x04Name\x12\x1a\n" +
+	"\bDeviceID\x18\x03 \x01(\x05R\bDeviceID\x12\x1e\n" +
+	"\n" +
+	"DeviceType\x18\x04 \x01(\tR\n" +
+	"DeviceType\x12 \n" +
+	"\vMessageType\x18\x05 \x01(\tR\vMessageType\x12 \n" +
+	"\vIntervalSec\x18\x06 \x01(\x05R\vIntervalSec\x12\x18\n" +
+	"\aSubject\x18\a \x01(\tR\aSubject\x12$\n" +
+	"\rSeverityLevel\x18\b \x01(\tR\rSeverityLevel\x128\n" +
+	"\tCreatedAt\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tCreatedAt\"B\n" +
+	"\tCreateReq\x125\n" +
+	"\tHeartbeat\x18\x01 \x01(\v2\x17.pbheartbeats.HeartbeatR\tHeartbeat\"U\n" +
+	"\n" +
+	"CreateResp\x121\n" +
+	"\aCreated\x18\x01 \x01(\v2\x17.pbheartbeats.HeartbeatR\aCreated\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"\t\n" +
+	"\aReadReq\"Y\n" +
+	"\bReadResp\x127\n" +
+	"\n" +
+	"Heartbeats\x18\x01 \x03(\v2\x17.pbheartbeats.HeartbeatR\n" +
+	"Heartbeats\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"\x1b\n" +
+	"\tDeleteReq\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\"\"\n" +
+	"\n" +
+	"DeleteResp\x12\x14\n" +
+	"\x05Error\x18\x01 \x01(\tR\x05ErrorB\x10Z\x0e.;pbheartbeatsb\x06proto3"
+
+var (
+	file_heartbeats_proto_rawDescOnce sync.Once
+	file_heartbeats_proto_rawDescData []byte
+)
+
+func file_heartbeats_proto_rawDescGZIP() []byte {
+	file_heartbeats_proto_rawDescOnce.Do(func() {
+		file_heartbeats_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_heartbeats_proto_rawDesc), len(file_heartbeats_proto_rawDesc)))
+	})
+	return file_heartbeats_proto_rawDescData
+}
+
+var file_heartbeats_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_heartbeats_proto_goTypes = []any{
+	(*Heartbeat)(nil),             // 0: pbheartbeats.Heartbeat
+	(*CreateReq)(nil),             // 1: pbheartbeats.CreateReq
+	(*CreateResp)(nil),            // 2: pbheartbeats.CreateResp
+	(*ReadReq)(nil),               // 3: pbheartbeats.ReadReq
+	(*ReadResp)(nil),              // 4: pbheartbeats.ReadResp
+	(*DeleteReq)(nil),             // 5: pbheartbeats.DeleteReq
+	(*DeleteResp)(nil),            // 6: pbheartbeats.DeleteResp
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_heartbeats_proto_depIdxs = []int32{
+	7, // 0: pbheartbeats.Heartbeat.CreatedAt:type_name -> google.protobuf.Timestamp
+	0, // 1: pbheartbeats.CreateReq.Heartbeat:type_name -> pbheartbeats.Heartbeat
+	0, // 2: pbheartbeats.CreateResp.Created:type_name -> pbheartbeats.Heartbeat
+	0, // 3: pbheartbeats.ReadResp.Heartbeats:type_name -> pbheartbeats.Heartbeat
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_heartbeats_proto_init() }
+func file_heartbeats_proto_init() {
+	if File_heartbeats_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_heartbeats_proto_rawDesc), len(file_heartbeats_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_heartbeats_proto_goTypes,
+		DependencyIndexes: file_heartbeats_proto_depIdxs,
+		MessageInfos:      file_heartbeats_proto_msgTypes,
+	}.Build()
+	File_heartbeats_proto = out.File
+	file_heartbeats_proto_goTypes = nil
+	file_heartbeats_proto_depIdxs = nil
+}
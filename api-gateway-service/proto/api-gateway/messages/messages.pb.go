@@ -23,12 +23,19 @@ const (
 )
 
 type MessageSave struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	DeviceID      int32                  `protobuf:"varint,2,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=Message,proto3" json:"Message,omitempty"`
-	MessageType   string                 `protobuf:"bytes,4,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
-	Component     string                 `protobuf:"bytes,5,opt,name=Component,proto3" json:"Component,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ID          int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	DeviceID    int32                  `protobuf:"varint,2,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Message     string                 `protobuf:"bytes,3,opt,name=Message,proto3" json:"Message,omitempty"`
+	MessageType string                 `protobuf:"bytes,4,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
+	Component   string                 `protobuf:"bytes,5,opt,name=Component,proto3" json:"Component,omitempty"`
+	// Time reported by the device, unset if it did not send one.
+	EventTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=EventTime,proto3" json:"EventTime,omitempty"`
+	// Time data-ingestion-service received the message.
+	ReceivedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=ReceivedAt,proto3" json:"ReceivedAt,omitempty"`
+	Attributes map[string]string      `protobuf:"bytes,8,rep,name=Attributes,proto3" json:"Attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Set by the sender to deduplicate retries, unique per device.
+	MessageID     string `protobuf:"bytes,9,opt,name=MessageID,proto3" json:"MessageID,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -98,10 +105,40 @@ func (x *MessageSave) GetComponent() string {
 	return ""
 }
 
+func (x *MessageSave) GetEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTime
+	}
+	return nil
+}
+
+func (x *MessageSave) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *MessageSave) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *MessageSave) GetMessageID() string {
+	if x != nil {
+		return x.MessageID
+	}
+	return ""
+}
+
 type ReportGetAllByPeriodReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	StartTime     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=StartTime,proto3" json:"StartTime,omitempty"`
-	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	StartTime *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=StartTime,proto3" json:"StartTime,omitempty"`
+	EndTime   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
+	// Filter and order by the device event time instead of the processing time.
+	ByEventTime   bool `protobuf:"varint,3,opt,name=ByEventTime,proto3" json:"ByEventTime,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -150,6 +187,13 @@ func (x *ReportGetAllByPeriodReq) GetEndTime() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *ReportGetAllByPeriodReq) GetByEventTime() bool {
+	if x != nil {
+		return x.ByEventTime
+	}
+	return false
+}
+
 type ReportGetAllByPeriod struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DeviceID      int32                  `protobuf:"varint,1,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
@@ -162,6 +206,12 @@ type ReportGetAllByPeriod struct {
 	MessageType   string                 `protobuf:"bytes,8,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
 	SeverityLevel string                 `protobuf:"bytes,9,opt,name=SeverityLevel,proto3" json:"SeverityLevel,omitempty"`
 	Component     string                 `protobuf:"bytes,10,opt,name=Component,proto3" json:"Component,omitempty"`
+	EventTime     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=EventTime,proto3" json:"EventTime,omitempty"`
+	ReceivedAt    *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=ReceivedAt,proto3" json:"ReceivedAt,omitempty"`
+	Attributes    map[string]string      `protobuf:"bytes,13,rep,name=Attributes,proto3" json:"Attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Silenced messages did not notify, SilenceID is the silence.
+	Silenced      bool  `protobuf:"varint,14,opt,name=Silenced,proto3" json:"Silenced,omitempty"`
+	SilenceID     int32 `protobuf:"varint,15,opt,name=SilenceID,proto3" json:"SilenceID,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -266,6 +316,41 @@ func (x *ReportGetAllByPeriod) GetComponent() string {
 	return ""
 }
 
+func (x *ReportGetAllByPeriod) GetEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTime
+	}
+	return nil
+}
+
+func (x *ReportGetAllByPeriod) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *ReportGetAllByPeriod) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *ReportGetAllByPeriod) GetSilenced() bool {
+	if x != nil {
+		return x.Silenced
+	}
+	return false
+}
+
+func (x *ReportGetAllByPeriod) GetSilenceID() int32 {
+	if x != nil {
+		return x.SilenceID
+	}
+	return 0
+}
+
 type ReportGetAllByPeriodResp struct {
 	state         protoimpl.MessageState  `protogen:"open.v1"`
 	Report        []*ReportGetAllByPeriod `protobuf:"bytes,1,rep,name=Report,proto3" json:"Report,omitempty"`
@@ -321,6 +406,7 @@ func (x *ReportGetAllByPeriodResp) GetError() string {
 type ReportGetAllByDeviceIdReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DeviceId      int32                  `protobuf:"varint,1,opt,name=DeviceId,proto3" json:"DeviceId,omitempty"`
+	ByEventTime   bool                   `protobuf:"varint,2,opt,name=ByEventTime,proto3" json:"ByEventTime,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -362,6 +448,13 @@ func (x *ReportGetAllByDeviceIdReq) GetDeviceId() int32 {
 	return 0
 }
 
+func (x *ReportGetAllByDeviceIdReq) GetByEventTime() bool {
+	if x != nil {
+		return x.ByEventTime
+	}
+	return false
+}
+
 type ReportGetAllByDeviceId struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DeviceID      int32                  `protobuf:"varint,1,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
@@ -374,6 +467,12 @@ type ReportGetAllByDeviceId struct {
 	MessageType   string                 `protobuf:"bytes,8,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
 	SeverityLevel string                 `protobuf:"bytes,9,opt,name=SeverityLevel,proto3" json:"SeverityLevel,omitempty"`
 	Component     string                 `protobuf:"bytes,10,opt,name=Component,proto3" json:"Component,omitempty"`
+	EventTime     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=EventTime,proto3" json:"EventTime,omitempty"`
+	ReceivedAt    *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=ReceivedAt,proto3" json:"ReceivedAt,omitempty"`
+	Attributes    map[string]string      `protobuf:"bytes,13,rep,name=Attributes,proto3" json:"Attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Silenced messages did not notify, SilenceID is the silence.
+	Silenced      bool  `protobuf:"varint,14,opt,name=Silenced,proto3" json:"Silenced,omitempty"`
+	SilenceID     int32 `protobuf:"varint,15,opt,name=SilenceID,proto3" json:"SilenceID,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -478,6 +577,41 @@ func (x *ReportGetAllByDeviceId) GetComponent() string {
 	return ""
 }
 
+func (x *ReportGetAllByDeviceId) GetEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTime
+	}
+	return nil
+}
+
+func (x *ReportGetAllByDeviceId) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *ReportGetAllByDeviceId) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *ReportGetAllByDeviceId) GetSilenced() bool {
+	if x != nil {
+		return x.Silenced
+	}
+	return false
+}
+
+func (x *ReportGetAllByDeviceId) GetSilenceID() int32 {
+	if x != nil {
+		return x.SilenceID
+	}
+	return 0
+}
+
 type ReportGetAllByDeviceIdResp struct {
 	state         protoimpl.MessageState    `protogen:"open.v1"`
 	Report        []*ReportGetAllByDeviceId `protobuf:"bytes,1,rep,name=Report,proto3" json:"Report,omitempty"`
@@ -931,16 +1065,28 @@ var File_messages_proto protoreflect.FileDescriptor
 const file_messages_proto_rawDesc = "" +
 	"\n" +
 	"\x0emessages.proto\x12\n" +
-	"pbmessages\x1a\x1fgoogle/protobuf/timestamp.proto\"\x93\x01\n" +
+	"pbmessages\x1a\x1fgoogle/protobuf/timestamp.proto\"\xaf\x03\n" +
 	"\vMessageSave\x12\x0e\n" +
 	"\x02ID\x18\x01 \x01(\x05R\x02ID\x12\x1a\n" +
 	"\bDeviceID\x18\x02 \x01(\x05R\bDeviceID\x12\x18\n" +
 	"\aMessage\x18\x03 \x01(\tR\aMessage\x12 \n" +
 	"\vMessageType\x18\x04 \x01(\tR\vMessageType\x12\x1c\n" +
-	"\tComponent\x18\x05 \x01(\tR\tComponent\"\x89\x01\n" +
+	"\tComponent\x18\x05 \x01(\tR\tComponent\x128\n" +
+	"\tEventTime\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tEventTime\x12:\n" +
+	"\n" +
+	"ReceivedAt\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"ReceivedAt\x12G\n" +
+	"\n" +
+	"Attributes\x18\b \x03(\v2'.pbmessages.MessageSave.AttributesEntryR\n" +
+	"Attributes\x12\x1c\n" +
+	"\tMessageID\x18\t \x01(\tR\tMessageID\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xab\x01\n" +
 	"\x17ReportGetAllByPeriodReq\x128\n" +
 	"\tStartTime\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tStartTime\x124\n" +
-	"\aEndTime\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aEndTime\"\xd4\x02\n" +
+	"\aEndTime\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aEndTime\x12 \n" +
+	"\vByEventTime\x18\x03 \x01(\bR\vByEventTime\"\x95\x05\n" +
 	"\x14ReportGetAllByPeriod\x12\x1a\n" +
 	"\bDeviceID\x18\x01 \x01(\x05R\bDeviceID\x12\x12\n" +
 	"\x04Name\x18\x02 \x01(\tR\x04Name\x12\x1e\n" +
@@ -954,12 +1100,25 @@ const file_messages_proto_rawDesc = "" +
 	"\vMessageType\x18\b \x01(\tR\vMessageType\x12$\n" +
 	"\rSeverityLevel\x18\t \x01(\tR\rSeverityLevel\x12\x1c\n" +
 	"\tComponent\x18\n" +
-	" \x01(\tR\tComponent\"j\n" +
+	" \x01(\tR\tComponent\x128\n" +
+	"\tEventTime\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tEventTime\x12:\n" +
+	"\n" +
+	"ReceivedAt\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"ReceivedAt\x12P\n" +
+	"\n" +
+	"Attributes\x18\r \x03(\v20.pbmessages.ReportGetAllByPeriod.AttributesEntryR\n" +
+	"Attributes\x12\x1a\n" +
+	"\bSilenced\x18\x0e \x01(\bR\bSilenced\x12\x1c\n" +
+	"\tSilenceID\x18\x0f \x01(\x05R\tSilenceID\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"j\n" +
 	"\x18ReportGetAllByPeriodResp\x128\n" +
 	"\x06Report\x18\x01 \x03(\v2 .pbmessages.ReportGetAllByPeriodR\x06Report\x12\x14\n" +
-	"\x05Error\x18\x02 \x01(\tR\x05Error\"7\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"Y\n" +
 	"\x19ReportGetAllByDeviceIdReq\x12\x1a\n" +
-	"\bDeviceId\x18\x01 \x01(\x05R\bDeviceId\"\xd6\x02\n" +
+	"\bDeviceId\x18\x01 \x01(\x05R\bDeviceId\x12 \n" +
+	"\vByEventTime\x18\x02 \x01(\bR\vByEventTime\"\x99\x05\n" +
 	"\x16ReportGetAllByDeviceId\x12\x1a\n" +
 	"\bDeviceID\x18\x01 \x01(\x05R\bDeviceID\x12\x12\n" +
 	"\x04Name\x18\x02 \x01(\tR\x04Name\x12\x1e\n" +
@@ -973,7 +1132,19 @@ const file_messages_proto_rawDesc = "" +
 	"\vMessageType\x18\b \x01(\tR\vMessageType\x12$\n" +
 	"\rSeverityLevel\x18\t \x01(\tR\rSeverityLevel\x12\x1c\n" +
 	"\tComponent\x18\n" +
-	" \x01(\tR\tComponent\"n\n" +
+	" \x01(\tR\tComponent\x128\n" +
+	"\tEventTime\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tEventTime\x12:\n" +
+	"\n" +
+	"ReceivedAt\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"ReceivedAt\x12R\n" +
+	"\n" +
+	"Attributes\x18\r \x03(\v22.pbmessages.ReportGetAllByDeviceId.AttributesEntryR\n" +
+	"Attributes\x12\x1a\n" +
+	"\bSilenced\x18\x0e \x01(\bR\bSilenced\x12\x1c\n" +
+	"\tSilenceID\x18\x0f \x01(\x05R\tSilenceID\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"n\n" +
 	"\x1aReportGetAllByDeviceIdResp\x12:\n" +
 	"\x06Report\x18\x01 \x03(\v2\".pbmessages.ReportGetAllByDeviceIdR\x06Report\x12\x14\n" +
 	"\x05Error\x18\x02 \x01(\tR\x05Error\"B\n" +
@@ -1027,7 +1198,7 @@ func file_messages_proto_rawDescGZIP() []byte {
 	return file_messages_proto_rawDescData
 }
 
-var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_messages_proto_goTypes = []any{
 	(*MessageSave)(nil),                     // 0: pbmessages.MessageSave
 	(*ReportGetAllByPeriodReq)(nil),         // 1: pbmessages.ReportGetAllByPeriodReq
@@ -1041,24 +1212,36 @@ var file_messages_proto_goTypes = []any{
 	(*ReportGetCountByMessageTypeResp)(nil), // 9: pbmessages.ReportGetCountByMessageTypeResp
 	(*MonthReportRow)(nil),                  // 10: pbmessages.MonthReportRow
 	(*MonthReport)(nil),                     // 11: pbmessages.MonthReport
-	(*timestamppb.Timestamp)(nil),           // 12: google.protobuf.Timestamp
+	nil,                                     // 12: pbmessages.MessageSave.AttributesEntry
+	nil,                                     // 13: pbmessages.ReportGetAllByPeriod.AttributesEntry
+	nil,                                     // 14: pbmessages.ReportGetAllByDeviceId.AttributesEntry
+	(*timestamppb.Timestamp)(nil),           // 15: google.protobuf.Timestamp
 }
 var file_messages_proto_depIdxs = []int32{
-	12, // 0: pbmessages.ReportGetAllByPeriodReq.StartTime:type_name -> google.protobuf.Timestamp
-	12, // 1: pbmessages.ReportGetAllByPeriodReq.EndTime:type_name -> google.protobuf.Timestamp
-	12, // 2: pbmessages.ReportGetAllByPeriod.GotAt:type_name -> google.protobuf.Timestamp
-	2,  // 3: pbmessages.ReportGetAllByPeriodResp.Report:type_name -> pbmessages.ReportGetAllByPeriod
-	12, // 4: pbmessages.ReportGetAllByDeviceId.GotAt:type_name -> google.protobuf.Timestamp
-	5,  // 5: pbmessages.ReportGetAllByDeviceIdResp.Report:type_name -> pbmessages.ReportGetAllByDeviceId
-	8,  // 6: pbmessages.ReportGetCountByMessageTypeResp.Report:type_name -> pbmessages.ReportGetCountByMessageType
-	12, // 7: pbmessages.MonthReportRow.FirstCriticalTime:type_name -> google.protobuf.Timestamp
-	12, // 8: pbmessages.MonthReportRow.LastCriticalTime:type_name -> google.protobuf.Timestamp
-	10, // 9: pbmessages.MonthReport.MonthReport:type_name -> pbmessages.MonthReportRow
-	10, // [10:10] is the sub-list for method output_type
-	10, // [10:10] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	15, // 0: pbmessages.MessageSave.EventTime:type_name -> google.protobuf.Timestamp
+	15, // 1: pbmessages.MessageSave.ReceivedAt:type_name -> google.protobuf.Timestamp
+	12, // 2: pbmessages.MessageSave.Attributes:type_name -> pbmessages.MessageSave.AttributesEntry
+	15, // 3: pbmessages.ReportGetAllByPeriodReq.StartTime:type_name -> google.protobuf.Timestamp
+	15, // 4: pbmessages.ReportGetAllByPeriodReq.EndTime:type_name -> google.protobuf.Timestamp
+	15, // 5: pbmessages.ReportGetAllByPeriod.GotAt:type_name -> google.protobuf.Timestamp
+	15, // 6: pbmessages.ReportGetAllByPeriod.EventTime:type_name -> google.protobuf.Timestamp
+	15, // 7: pbmessages.ReportGetAllByPeriod.ReceivedAt:type_name -> google.protobuf.Timestamp
+	13, // 8: pbmessages.ReportGetAllByPeriod.Attributes:type_name -> pbmessages.ReportGetAllByPeriod.AttributesEntry
+	2,  // 9: pbmessages.ReportGetAllByPeriodResp.Report:type_name -> pbmessages.ReportGetAllByPeriod
+	15, // 10: pbmessages.ReportGetAllByDeviceId.GotAt:type_name -> google.protobuf.Timestamp
+	15, // 11: pbmessages.ReportGetAllByDeviceId.EventTime:type_name -> google.protobuf.Timestamp
+	15, // 12: pbmessages.ReportGetAllByDeviceId.ReceivedAt:type_name -> google.protobuf.Timestamp
+	14, // 13: pbmessages.ReportGetAllByDeviceId.Attributes:type_name -> pbmessages.ReportGetAllByDeviceId.AttributesEntry
+	5,  // 14: pbmessages.ReportGetAllByDeviceIdResp.Report:type_name -> pbmessages.ReportGetAllByDeviceId
+	8,  // 15: pbmessages.ReportGetCountByMessageTypeResp.Report:type_name -> pbmessages.ReportGetCountByMessageType
+	15, // 16: pbmessages.MonthReportRow.FirstCriticalTime:type_name -> google.protobuf.Timestamp
+	15, // 17: pbmessages.MonthReportRow.LastCriticalTime:type_name -> google.protobuf.Timestamp
+	10, // 18: pbmessages.MonthReport.MonthReport:type_name -> pbmessages.MonthReportRow
+	19, // [19:19] is the sub-list for method output_type
+	19, // [19:19] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_messages_proto_init() }
@@ -1072,7 +1255,7 @@ func file_messages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_messages_proto_rawDesc), len(file_messages_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
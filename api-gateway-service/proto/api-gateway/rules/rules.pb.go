@@ -0,0 +1,1267 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: rules.proto
+
+package pbrules
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Condition is a group of conditions joined by Op or, with Op empty, a check
+// of one message field.
+type Condition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Op            string                 `protobuf:"bytes,1,opt,name=Op,proto3" json:"Op,omitempty"`
+	Conditions    []*Condition           `protobuf:"bytes,2,rep,name=Conditions,proto3" json:"Conditions,omitempty"`
+	Field         string                 `protobuf:"bytes,3,opt,name=Field,proto3" json:"Field,omitempty"`
+	Regexp        string                 `protobuf:"bytes,4,opt,name=Regexp,proto3" json:"Regexp,omitempty"`
+	ArrayIndex    int32                  `protobuf:"varint,5,opt,name=ArrayIndex,proto3" json:"ArrayIndex,omitempty"`
+	CompareType   string                 `protobuf:"bytes,6,opt,name=CompareType,proto3" json:"CompareType,omitempty"`
+	Value         string                 `protobuf:"bytes,7,opt,name=Value,proto3" json:"Value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Condition) Reset() {
+	*x = Condition{}
+	mi := &file_rules_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Condition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Condition) ProtoMessage() {}
+
+func (x *Condition) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Condition.ProtoReflect.Descriptor instead.
+func (*Condition) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Condition) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *Condition) GetConditions() []*Condition {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
+func (x *Condition) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Condition) GetRegexp() string {
+	if x != nil {
+		return x.Regexp
+	}
+	return ""
+}
+
+func (x *Condition) GetArrayIndex() int32 {
+	if x != nil {
+		return x.ArrayIndex
+	}
+	return 0
+}
+
+func (x *Condition) GetCompareType() string {
+	if x != nil {
+		return x.CompareType
+	}
+	return ""
+}
+
+func (x *Condition) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+// Window aggregates the messages passing the conditions over DurationSec
+// seconds, the rule fires when the aggregate compared to Threshold holds.
+type Window struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=Type,proto3" json:"Type,omitempty"`
+	DurationSec   int64                  `protobuf:"varint,2,opt,name=DurationSec,proto3" json:"DurationSec,omitempty"`
+	Aggregation   string                 `protobuf:"bytes,3,opt,name=Aggregation,proto3" json:"Aggregation,omitempty"`
+	Field         string                 `protobuf:"bytes,4,opt,name=Field,proto3" json:"Field,omitempty"`
+	Regexp        string                 `protobuf:"bytes,5,opt,name=Regexp,proto3" json:"Regexp,omitempty"`
+	ArrayIndex    int32                  `protobuf:"varint,6,opt,name=ArrayIndex,proto3" json:"ArrayIndex,omitempty"`
+	CompareType   string                 `protobuf:"bytes,7,opt,name=CompareType,proto3" json:"CompareType,omitempty"`
+	Threshold     float64                `protobuf:"fixed64,8,opt,name=Threshold,proto3" json:"Threshold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Window) Reset() {
+	*x = Window{}
+	mi := &file_rules_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Window) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Window) ProtoMessage() {}
+
+func (x *Window) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Window.ProtoReflect.Descriptor instead.
+func (*Window) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Window) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Window) GetDurationSec() int64 {
+	if x != nil {
+		return x.DurationSec
+	}
+	return 0
+}
+
+func (x *Window) GetAggregation() string {
+	if x != nil {
+		return x.Aggregation
+	}
+	return ""
+}
+
+func (x *Window) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Window) GetRegexp() string {
+	if x != nil {
+		return x.Regexp
+	}
+	return ""
+}
+
+func (x *Window) GetArrayIndex() int32 {
+	if x != nil {
+		return x.ArrayIndex
+	}
+	return 0
+}
+
+func (x *Window) GetCompareType() string {
+	if x != nil {
+		return x.CompareType
+	}
+	return ""
+}
+
+func (x *Window) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+// Anomaly learns a baseline per device of the number extracted by Regexp and
+// fires once a value deviates from it by more than Sigma standard deviations.
+// Left out Alpha, Sigma and MinSamples get defaults.
+type Anomaly struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Field         string                 `protobuf:"bytes,1,opt,name=Field,proto3" json:"Field,omitempty"`
+	Regexp        string                 `protobuf:"bytes,2,opt,name=Regexp,proto3" json:"Regexp,omitempty"`
+	ArrayIndex    int32                  `protobuf:"varint,3,opt,name=ArrayIndex,proto3" json:"ArrayIndex,omitempty"`
+	Alpha         float64                `protobuf:"fixed64,4,opt,name=Alpha,proto3" json:"Alpha,omitempty"`
+	Sigma         float64                `protobuf:"fixed64,5,opt,name=Sigma,proto3" json:"Sigma,omitempty"`
+	Direction     string                 `protobuf:"bytes,6,opt,name=Direction,proto3" json:"Direction,omitempty"`
+	Seasonality   string                 `protobuf:"bytes,7,opt,name=Seasonality,proto3" json:"Seasonality,omitempty"`
+	MinSamples    int32                  `protobuf:"varint,8,opt,name=MinSamples,proto3" json:"MinSamples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Anomaly) Reset() {
+	*x = Anomaly{}
+	mi := &file_rules_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Anomaly) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Anomaly) ProtoMessage() {}
+
+func (x *Anomaly) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Anomaly.ProtoReflect.Descriptor instead.
+func (*Anomaly) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Anomaly) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+func (x *Anomaly) GetRegexp() string {
+	if x != nil {
+		return x.Regexp
+	}
+	return ""
+}
+
+func (x *Anomaly) GetArrayIndex() int32 {
+	if x != nil {
+		return x.ArrayIndex
+	}
+	return 0
+}
+
+func (x *Anomaly) GetAlpha() float64 {
+	if x != nil {
+		return x.Alpha
+	}
+	return 0
+}
+
+func (x *Anomaly) GetSigma() float64 {
+	if x != nil {
+		return x.Sigma
+	}
+	return 0
+}
+
+func (x *Anomaly) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *Anomaly) GetSeasonality() string {
+	if x != nil {
+		return x.Seasonality
+	}
+	return ""
+}
+
+func (x *Anomaly) GetMinSamples() int32 {
+	if x != nil {
+		return x.MinSamples
+	}
+	return 0
+}
+
+type Rule struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	DeviceID      int32                  `protobuf:"varint,3,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Op            string                 `protobuf:"bytes,4,opt,name=Op,proto3" json:"Op,omitempty"`
+	Conditions    []*Condition           `protobuf:"bytes,5,rep,name=Conditions,proto3" json:"Conditions,omitempty"`
+	Subject       string                 `protobuf:"bytes,6,opt,name=Subject,proto3" json:"Subject,omitempty"`
+	SeverityLevel string                 `protobuf:"bytes,7,opt,name=SeverityLevel,proto3" json:"SeverityLevel,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=UpdatedAt,proto3" json:"UpdatedAt,omitempty"`
+	Window        *Window                `protobuf:"bytes,10,opt,name=Window,proto3" json:"Window,omitempty"`
+	Anomaly       *Anomaly               `protobuf:"bytes,11,opt,name=Anomaly,proto3" json:"Anomaly,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rule) Reset() {
+	*x = Rule{}
+	mi := &file_rules_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rule) ProtoMessage() {}
+
+func (x *Rule) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rule.ProtoReflect.Descriptor instead.
+func (*Rule) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Rule) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *Rule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Rule) GetDeviceID() int32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *Rule) GetOp() string {
+	if x != nil {
+		return x.Op
+	}
+	return ""
+}
+
+func (x *Rule) GetConditions() []*Condition {
+	if x != nil {
+		return x.Conditions
+	}
+	return nil
+}
+
+func (x *Rule) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Rule) GetSeverityLevel() string {
+	if x != nil {
+		return x.SeverityLevel
+	}
+	return ""
+}
+
+func (x *Rule) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Rule) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Rule) GetWindow() *Window {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *Rule) GetAnomaly() *Anomaly {
+	if x != nil {
+		return x.Anomaly
+	}
+	return nil
+}
+
+type CreateReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *Rule                  `protobuf:"bytes,1,opt,name=Rule,proto3" json:"Rule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReq) Reset() {
+	*x = CreateReq{}
+	mi := &file_rules_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReq) ProtoMessage() {}
+
+func (x *CreateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReq.ProtoReflect.Descriptor instead.
+func (*CreateReq) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CreateReq) GetRule() *Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+type CreateResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       *Rule                  `protobuf:"bytes,1,opt,name=Created,proto3" json:"Created,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResp) Reset() {
+	*x = CreateResp{}
+	mi := &file_rules_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResp) ProtoMessage() {}
+
+func (x *CreateResp) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResp.ProtoReflect.Descriptor instead.
+func (*CreateResp) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateResp) GetCreated() *Rule {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *CreateResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ReadResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rules         []*Rule                `protobuf:"bytes,1,rep,name=Rules,proto3" json:"Rules,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadResp) Reset() {
+	*x = ReadResp{}
+	mi := &file_rules_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadResp) ProtoMessage() {}
+
+func (x *ReadResp) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadResp.ProtoReflect.Descriptor instead.
+func (*ReadResp) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ReadResp) GetRules() []*Rule {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+func (x *ReadResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type UpdateReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rule          *Rule                  `protobuf:"bytes,1,opt,name=Rule,proto3" json:"Rule,omitempty"`
+	RemoveWindow  bool                   `protobuf:"varint,2,opt,name=RemoveWindow,proto3" json:"RemoveWindow,omitempty"`
+	RemoveAnomaly bool                   `protobuf:"varint,3,opt,name=RemoveAnomaly,proto3" json:"RemoveAnomaly,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateReq) Reset() {
+	*x = UpdateReq{}
+	mi := &file_rules_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateReq) ProtoMessage() {}
+
+func (x *UpdateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateReq.ProtoReflect.Descriptor instead.
+func (*UpdateReq) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateReq) GetRule() *Rule {
+	if x != nil {
+		return x.Rule
+	}
+	return nil
+}
+
+func (x *UpdateReq) GetRemoveWindow() bool {
+	if x != nil {
+		return x.RemoveWindow
+	}
+	return false
+}
+
+func (x *UpdateReq) GetRemoveAnomaly() bool {
+	if x != nil {
+		return x.RemoveAnomaly
+	}
+	return false
+}
+
+type UpdateResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateResp) Reset() {
+	*x = UpdateResp{}
+	mi := &file_rules_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateResp) ProtoMessage() {}
+
+func (x *UpdateResp) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateResp.ProtoReflect.Descriptor instead.
+func (*UpdateResp) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DeleteReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReq) Reset() {
+	*x = DeleteReq{}
+	mi := &file_rules_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReq) ProtoMessage() {}
+
+func (x *DeleteReq) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReq.ProtoReflect.Descriptor instead.
+func (*DeleteReq) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *DeleteReq) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+type DeleteResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResp) Reset() {
+	*x = DeleteResp{}
+	mi := &file_rules_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResp) ProtoMessage() {}
+
+func (x *DeleteResp) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResp.ProtoReflect.Descriptor instead.
+func (*DeleteResp) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// Season is the learned offset from the level in one hour of the season.
+type Season struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Offset        float64                `protobuf:"fixed64,1,opt,name=Offset,proto3" json:"Offset,omitempty"`
+	Samples       int64                  `protobuf:"varint,2,opt,name=Samples,proto3" json:"Samples,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Season) Reset() {
+	*x = Season{}
+	mi := &file_rules_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Season) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Season) ProtoMessage() {}
+
+func (x *Season) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Season.ProtoReflect.Descriptor instead.
+func (*Season) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Season) GetOffset() float64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *Season) GetSamples() int64 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+// Baseline is what an anomaly rule learned for a device. The last value was
+// compared with LastExpected, LastScore is its deviation in standard
+// deviations.
+type Baseline struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleID        int32                  `protobuf:"varint,1,opt,name=RuleID,proto3" json:"RuleID,omitempty"`
+	DeviceID      int32                  `protobuf:"varint,2,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Anomaly       *Anomaly               `protobuf:"bytes,3,opt,name=Anomaly,proto3" json:"Anomaly,omitempty"`
+	Samples       int64                  `protobuf:"varint,4,opt,name=Samples,proto3" json:"Samples,omitempty"`
+	Level         float64                `protobuf:"fixed64,5,opt,name=Level,proto3" json:"Level,omitempty"`
+	Variance      float64                `protobuf:"fixed64,6,opt,name=Variance,proto3" json:"Variance,omitempty"`
+	Seasons       []*Season              `protobuf:"bytes,7,rep,name=Seasons,proto3" json:"Seasons,omitempty"`
+	LastValue     float64                `protobuf:"fixed64,8,opt,name=LastValue,proto3" json:"LastValue,omitempty"`
+	LastExpected  float64                `protobuf:"fixed64,9,opt,name=LastExpected,proto3" json:"LastExpected,omitempty"`
+	LastScore     float64                `protobuf:"fixed64,10,opt,name=LastScore,proto3" json:"LastScore,omitempty"`
+	Anomalous     bool                   `protobuf:"varint,11,opt,name=Anomalous,proto3" json:"Anomalous,omitempty"`
+	LastAnomalyAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=LastAnomalyAt,proto3" json:"LastAnomalyAt,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=UpdatedAt,proto3" json:"UpdatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Baseline) Reset() {
+	*x = Baseline{}
+	mi := &file_rules_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Baseline) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Baseline) ProtoMessage() {}
+
+func (x *Baseline) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Baseline.ProtoReflect.Descriptor instead.
+func (*Baseline) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Baseline) GetRuleID() int32 {
+	if x != nil {
+		return x.RuleID
+	}
+	return 0
+}
+
+func (x *Baseline) GetDeviceID() int32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *Baseline) GetAnomaly() *Anomaly {
+	if x != nil {
+		return x.Anomaly
+	}
+	return nil
+}
+
+func (x *Baseline) GetSamples() int64 {
+	if x != nil {
+		return x.Samples
+	}
+	return 0
+}
+
+func (x *Baseline) GetLevel() float64 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *Baseline) GetVariance() float64 {
+	if x != nil {
+		return x.Variance
+	}
+	return 0
+}
+
+func (x *Baseline) GetSeasons() []*Season {
+	if x != nil {
+		return x.Seasons
+	}
+	return nil
+}
+
+func (x *Baseline) GetLastValue() float64 {
+	if x != nil {
+		return x.LastValue
+	}
+	return 0
+}
+
+func (x *Baseline) GetLastExpected() float64 {
+	if x != nil {
+		return x.LastExpected
+	}
+	return 0
+}
+
+func (x *Baseline) GetLastScore() float64 {
+	if x != nil {
+		return x.LastScore
+	}
+	return 0
+}
+
+func (x *Baseline) GetAnomalous() bool {
+	if x != nil {
+		return x.Anomalous
+	}
+	return false
+}
+
+func (x *Baseline) GetLastAnomalyAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAnomalyAt
+	}
+	return nil
+}
+
+func (x *Baseline) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// ReadBaselinesReq filters by the IDs that are not 0.
+type ReadBaselinesReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RuleID        int32                  `protobuf:"varint,1,opt,name=RuleID,proto3" json:"RuleID,omitempty"`
+	DeviceID      int32                  `protobuf:"varint,2,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadBaselinesReq) Reset() {
+	*x = ReadBaselinesReq{}
+	mi := &file_rules_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadBaselinesReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadBaselinesReq) ProtoMessage() {}
+
+func (x *ReadBaselinesReq) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadBaselinesReq.ProtoReflect.Descriptor instead.
+func (*ReadBaselinesReq) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReadBaselinesReq) GetRuleID() int32 {
+	if x != nil {
+		return x.RuleID
+	}
+	return 0
+}
+
+func (x *ReadBaselinesReq) GetDeviceID() int32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+type ReadBaselinesResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Baselines     []*Baseline            `protobuf:"bytes,1,rep,name=Baselines,proto3" json:"Baselines,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadBaselinesResp) Reset() {
+	*x = ReadBaselinesResp{}
+	mi := &file_rules_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadBaselinesResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadBaselinesResp) ProtoMessage() {}
+
+func (x *ReadBaselinesResp) ProtoReflect() protoreflect.Message {
+	mi := &file_rules_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadBaselinesResp.ProtoReflect.Descriptor instead.
+func (*ReadBaselinesResp) Descriptor() ([]byte, []int) {
+	return file_rules_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ReadBaselinesResp) GetBaselines() []*Baseline {
+	if x != nil {
+		return x.Baselines
+	}
+	return nil
+}
+
+func (x *ReadBaselinesResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_rules_proto protoreflect.FileDescriptor
+
+const file_rules_proto_rawDesc = "" +
+	"\n" +
+	"\vrules.proto\x12\apbrules\x1a\x1fgoogle/protobuf/timestamp.proto\"\xd5\x01\n" +
+	"\tCondition\x12\x0e\n" +
+	"\x02Op\x18\x01 \x01(\tR\x02Op\x122\n" +
+	"\n" +
+	"Conditions\x18\x02 \x03(\v2\x12.pbrules.ConditionR\n" +
+	"Conditions\x12\x14\n" +
+	"\x05Field\x18\x03 \x01(\tR\x05Field\x12\x16\n" +
+	"\x06Regexp\x18\x04 \x01(\tR\x06Regexp\x12\x1e\n" +
+	"\n" +
+	"ArrayIndex\x18\x05 \x01(\x05R\n" +
+	"ArrayIndex\x12 \n" +
+	"\vCompareType\x18\x06 \x01(\tR\vCompareType\x12\x14\n" +
+	"\x05Value\x18\a \x01(\tR\x05Value\"\xee\x01\n" +
+	"\x06Window\x12\x12\n" +
+	"\x04Type\x18\x01 \x01(\tR\x04Type\x12 \n" +
+	"\vDurationSec\x18\x02 \x01(\x03R\vDurationSec\x12 \n" +
+	"\vAggregation\x18\x03 \x01(\tR\vAggregation\x12\x14\n" +
+	"\x05Field\x18\x04 \x01(\tR\x05Field\x12\x16\n" +
+	"\x06Regexp\x18\x05 \x01(\tR\x06Regexp\x12\x1e\n" +
+	"\n" +
+	"ArrayIndex\x18\x06 \x01(\x05R\n" +
+	"ArrayIndex\x12 \n" +
+	"\vCompareType\x18\a \x01(\tR\vCompareType\x12\x1c\n" +
+	"\tThreshold\x18\b \x01(\x01R\tThreshold\"\xe3\x01\n" +
+	"\aAnomaly\x12\x14\n" +
+	"\x05Field\x18\x01 \x01(\tR\x05Field\x12\x16\n" +
+	"\x06Regexp\x18\x02 \x01(\tR\x06Regexp\x12\x1e\n" +
+	"\n" +
+	"ArrayIndex\x18\x03 \x01(\x05R\n" +
+	"ArrayIndex\x12\x14\n" +
+	"\x05Alpha\x18\x04 \x01(\x01R\x05Alpha\x12\x14\n" +
+	"\x05Sigma\x18\x05 \x01(\x01R\x05Sigma\x12\x1c\n" +
+	"\tDirection\x18\x06 \x01(\tR\tDirection\x12 \n" +
+	"\vSeasonality\x18\a \x01(\tR\vSeasonality\x12\x1e\n" +
+	"\n" +
+	"MinSamples\x18\b \x01(\x05R\n" +
+	"MinSamples\"\x93\x03\n" +
+	"\x04Rule\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\x12\x12\n" +
+	"\x04Name\x18\x02 \x01(\tR\x04Name\x12\x1a\n" +
+	"\bDeviceID\x18\x03 \x01(\x05R\bDeviceID\x12\x0e\n" +
+	"\x02Op\x18\x04 \x01(\tR\x02Op\x122\n" +
+	"\n" +
+	"Conditions\x18\x05 \x03(\v2\x12.pbrules.ConditionR\n" +
+	"Conditions\x12\x18\n" +
+	"\aSubject\x18\x06 \x01(\tR\aSubject\x12$\n" +
+	"\rSeverityLevel\x18\a \x01(\tR\rSeverityLevel\x128\n" +
+	"\tCreatedAt\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tCreatedAt\x128\n" +
+	"\tUpdatedAt\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tUpdatedAt\x12'\n" +
+	"\x06Window\x18\n" +
+	" \x01(\v2\x0f.pbrules.WindowR\x06Window\x12*\n" +
+	"\aAnomaly\x18\v \x01(\v2\x10.pbrules.AnomalyR\aAnomaly\".\n" +
+	"\tCreateReq\x12!\n" +
+	"\x04Rule\x18\x01 \x01(\v2\r.pbrules.RuleR\x04Rule\"K\n" +
+	"\n" +
+	"CreateResp\x12'\n" +
+	"\aCreated\x18\x01 \x01(\v2\r.pbrules.RuleR\aCreated\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"E\n" +
+	"\bReadResp\x12#\n" +
+	"\x05Rules\x18\x01 \x03(\v2\r.pbrules.RuleR\x05Rules\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"x\n" +
+	"\tUpdateReq\x12!\n" +
+	"\x04Rule\x18\x01 \x01(\v2\r.pbrules.RuleR\x04Rule\x12\"\n" +
+	"\fRemoveWindow\x18\x02 \x01(\bR\fRemoveWindow\x12$\n" +
+	"\rRemoveAnomaly\x18\x03 \x01(\bR\rRemoveAnomaly\"\"\n" +
+	"\n" +
+	"UpdateResp\x12\x14\n" +
+	"\x05Error\x18\x01 \x01(\tR\x05Error\"\x1b\n" +
+	"\tDeleteReq\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\"\"\n" +
+	"\n" +
+	"DeleteResp\x12\x14\n" +
+	"\x05Error\x18\x01 \x01(\tR\x05Error\":\n" +
+	"\x06Season\x12\x16\n" +
+	"\x06Offset\x18\x01 \x01(\x01R\x06Offset\x12\x18\n" +
+	"\aSamples\x18\x02 \x01(\x03R\aSamples\"\xdb\x03\n" +
+	"\bBaseline\x12\x16\n" +
+	"\x06RuleID\x18\x01 \x01(\x05R\x06RuleID\x12\x1a\n" +
+	"\bDeviceID\x18\x02 \x01(\x05R\bDeviceID\x12*\n" +
+	"\aAnomaly\x18\x03 \x01(\v2\x10.pbrules.AnomalyR\aAnomaly\x12\x18\n" +
+	"\aSamples\x18\x04 \x01(\x03R\aSamples\x12\x14\n" +
+	"\x05Level\x18\x05 \x01(\x01R\x05Level\x12\x1a\n" +
+	"\bVariance\x18\x06 \x01(\x01R\bVariance\x12)\n" +
+	"\aSeasons\x18\a \x03(\v2\x0f.pbrules.SeasonR\aSeasons\x12\x1c\n" +
+	"\tLastValue\x18\b \x01(\x01R\tLastValue\x12\"\n" +
+	"\fLastExpected\x18\t \x01(\x01R\fLastExpected\x12\x1c\n" +
+	"\tLastScore\x18\n" +
+	" \x01(\x01R\tLastScore\x12\x1c\n" +
+	"\tAnomalous\x18\v \x01(\bR\tAnomalous\x12@\n" +
+	"\rLastAnomalyAt\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\rLastAnomalyAt\x128\n" +
+	"\tUpdatedAt\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tUpdatedAt\"F\n" +
+	"\x10ReadBaselinesReq\x12\x16\n" +
+	"\x06RuleID\x18\x01 \x01(\x05R\x06RuleID\x12\x1a\n" +
+	"\bDeviceID\x18\x02 \x01(\x05R\bDeviceID\"Z\n" +
+	"\x11ReadBaselinesResp\x12/\n" +
+	"\tBaselines\x18\x01 \x03(\v2\x11.pbrules.BaselineR\tBaselines\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05ErrorB\vZ\t.;pbrulesb\x06proto3"
+
+var (
+	file_rules_proto_rawDescOnce sync.Once
+	file_rules_proto_rawDescData []byte
+)
+
+func file_rules_proto_rawDescGZIP() []byte {
+	file_rules_proto_rawDescOnce.Do(func() {
+		file_rules_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rules_proto_rawDesc), len(file_rules_proto_rawDesc)))
+	})
+	return file_rules_proto_rawDescData
+}
+
+var file_rules_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_rules_proto_goTypes = []any{
+	(*Condition)(nil),             // 0: pbrules.Condition
+	(*Window)(nil),                // 1: pbrules.Window
+	(*Anomaly)(nil),               // 2: pbrules.Anomaly
+	(*Rule)(nil),                  // 3: pbrules.Rule
+	(*CreateReq)(nil),             // 4: pbrules.CreateReq
+	(*CreateResp)(nil),            // 5: pbrules.CreateResp
+	(*ReadResp)(nil),              // 6: pbrules.ReadResp
+	(*UpdateReq)(nil),             // 7: pbrules.UpdateReq
+	(*UpdateResp)(nil),            // 8: pbrules.UpdateResp
+	(*DeleteReq)(nil),             // 9: pbrules.DeleteReq
+	(*DeleteResp)(nil),            // 10: pbrules.DeleteResp
+	(*Season)(nil),                // 11: pbrules.Season
+	(*Baseline)(nil),              // 12: pbrules.Baseline
+	(*ReadBaselinesReq)(nil),      // 13: pbrules.ReadBaselinesReq
+	(*ReadBaselinesResp)(nil),     // 14: pbrules.ReadBaselinesResp
+	(*timestamppb.Timestamp)(nil), // 15: google.protobuf.Timestamp
+}
+var file_rules_proto_depIdxs = []int32{
+	0,  // 0: pbrules.Condition.Conditions:type_name -> pbrules.Condition
+	0,  // 1: pbrules.Rule.Conditions:type_name -> pbrules.Condition
+	15, // 2: pbrules.Rule.CreatedAt:type_name -> google.protobuf.Timestamp
+	15, // 3: pbrules.Rule.UpdatedAt:type_name -> google.protobuf.Timestamp
+	1,  // 4: pbrules.Rule.Window:type_name -> pbrules.Window
+	2,  // 5: pbrules.Rule.Anomaly:type_name -> pbrules.Anomaly
+	3,  // 6: pbrules.CreateReq.Rule:type_name -> pbrules.Rule
+	3,  // 7: pbrules.CreateResp.Created:type_name -> pbrules.Rule
+	3,  // 8: pbrules.ReadResp.Rules:type_name -> pbrules.Rule
+	3,  // 9: pbrules.UpdateReq.Rule:type_name -> pbrules.Rule
+	2,  // 10: pbrules.Baseline.Anomaly:type_name -> pbrules.Anomaly
+	11, // 11: pbrules.Baseline.Seasons:type_name -> pbrules.Season
+	15, // 12: pbrules.Baseline.LastAnomalyAt:type_name -> google.protobuf.Timestamp
+	15, // 13: pbrules.Baseline.UpdatedAt:type_name -> google.protobuf.Timestamp
+	12, // 14: pbrules.ReadBaselinesResp.Baselines:type_name -> pbrules.Baseline
+	15, // [15:15] is the sub-list for method output_type
+	15, // [15:15] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_rules_proto_init() }
+func file_rules_proto_init() {
+	if File_rules_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rules_proto_rawDesc), len(file_rules_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_rules_proto_goTypes,
+		DependencyIndexes: file_rules_proto_depIdxs,
+		MessageInfos:      file_rules_proto_msgTypes,
+	}.Build()
+	File_rules_proto = out.File
+	file_rules_proto_goTypes = nil
+	file_rules_proto_depIdxs = nil
+}
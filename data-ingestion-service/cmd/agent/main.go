@@ -0,0 +1,20 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"data-ingestion-service/config"
+	"data-ingestion-service/internal/agent"
+)
+
+func main() {
+	defer os.Exit(1)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT)
+	defer stop()
+
+	agent.Run(ctx, config.GetAgent())
+}
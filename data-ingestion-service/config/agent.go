@@ -0,0 +1,48 @@
+package config
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/caarlos0/env/v11"
+	"github.com/joho/godotenv"
+)
+
+// Agent is the configuration of cmd/agent, it runs on the monitored hosts
+// and shares nothing with the service but the logger settings.
+type Agent struct {
+	Logger Logger
+	Agent  AgentConfig
+}
+
+type AgentConfig struct {
+	// Base URL of the v1 API, e.g. http://host:13695/data-ingestion-service/v1.
+	IngestionURL string `env:"AGENT_INGESTION_URL,required"`
+	DeviceKey    string `env:"AGENT_DEVICE_KEY"`
+	// Address of the device the files belong to, a file may override it.
+	Address     string `env:"AGENT_ADDRESS"`
+	FilesConfig string `env:"AGENT_FILES_CONFIG,required"`
+	StateFile   string `env:"AGENT_STATE_FILE" envDefault:"/var/lib/data-ingestion-agent/offsets.json"`
+
+	PollInterval   time.Duration `env:"AGENT_POLL_INTERVAL" envDefault:"1s"`
+	BatchSize      int           `env:"AGENT_BATCH_SIZE" envDefault:"500"`
+	RequestTimeout time.Duration `env:"AGENT_REQUEST_TIMEOUT" envDefault:"10s"`
+	RetryMin       time.Duration `env:"AGENT_RETRY_MIN" envDefault:"1s"`
+	RetryMax       time.Duration `env:"AGENT_RETRY_MAX" envDefault:"1m"`
+}
+
+var (
+	agent     Agent
+	agentOnce sync.Once
+)
+
+func GetAgent() *Agent {
+	agentOnce.Do(func() {
+		_ = godotenv.Load()
+		if err := env.Parse(&agent); err != nil {
+			log.Fatal(err)
+		}
+	})
+	return &agent
+}
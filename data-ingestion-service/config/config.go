@@ -14,6 +14,13 @@ type Config struct {
 	App    AppConfig
 	Nats   NatsConfig
 	Server ServerConfig
+	Syslog SyslogConfig
+	MQTT   MQTTConfig
+	Spool  SpoolConfig
+
+	RateLimit RateLimitConfig
+
+	Quarantine QuarantineConfig
 }
 
 type Logger struct {
@@ -32,9 +39,61 @@ type NatsConfig struct {
 }
 
 type ServerConfig struct {
-	Addr              string `env:"SERVER_ADDR,required"`
-	DeviceCheckPeriod int    `env:"SERVER_DEVICE_CHECK_PERIOD"`
-	LogQuerys         bool   `env:"SERVER_LOG_QUERYS"`
+	Addr                  string `env:"SERVER_ADDR,required"`
+	DeviceCheckPeriod     int    `env:"SERVER_DEVICE_CHECK_PERIOD"`
+	DeviceCheckWorkers    int    `env:"SERVER_DEVICE_CHECK_WORKERS" envDefault:"16"`
+	DeviceCheckProbesFile string `env:"SERVER_DEVICE_CHECK_PROBES_FILE"`
+	// Consecutive rounds needed to mark a device DOWN/DEGRADED or UP again.
+	DeviceCheckFailureThreshold  int `env:"SERVER_DEVICE_CHECK_FAILURE_THRESHOLD" envDefault:"3"`
+	DeviceCheckRecoveryThreshold int `env:"SERVER_DEVICE_CHECK_RECOVERY_THRESHOLD" envDefault:"2"`
+	// HTTP ingestion must carry the X-Device-Key issued by device-management-service.
+	// Devices that were issued a key must send it either way. Turn it on only
+	// once every HTTP sender got a key through RotateKey, existing devices
+	// have none after the upgrade and would all be rejected.
+	RequireDeviceKey bool `env:"SERVER_REQUIRE_DEVICE_KEY" envDefault:"false"`
+	LogQuerys        bool `env:"SERVER_LOG_QUERYS"`
+}
+
+type SyslogConfig struct {
+	UDPAddr string `env:"SYSLOG_UDP_ADDR"`
+	TCPAddr string `env:"SYSLOG_TCP_ADDR"`
+}
+
+type MQTTConfig struct {
+	BrokerURL string   `env:"MQTT_BROKER_URL"`
+	ClientID  string   `env:"MQTT_CLIENT_ID" envDefault:"data-ingestion-service"`
+	Username  string   `env:"MQTT_USERNAME"`
+	Password  string   `env:"MQTT_PASSWORD"`
+	Topics    []string `env:"MQTT_TOPICS" envDefault:"devices/+/messages" envSeparator:","`
+	QoS       byte     `env:"MQTT_QOS" envDefault:"1"`
+}
+
+type SpoolConfig struct {
+	Dir            string        `env:"SPOOL_DIR"`
+	MaxBytes       int64         `env:"SPOOL_MAX_BYTES" envDefault:"1073741824"`
+	MaxAge         time.Duration `env:"SPOOL_MAX_AGE" envDefault:"24h"`
+	SegmentBytes   int64         `env:"SPOOL_SEGMENT_BYTES" envDefault:"67108864"`
+	ReplayInterval time.Duration `env:"SPOOL_REPLAY_INTERVAL" envDefault:"5s"`
+}
+
+type RateLimitConfig struct {
+	// Messages per second of a single device, zero disables the limit.
+	DeviceRate  float64 `env:"RATE_LIMIT_DEVICE_RATE"`
+	DeviceBurst int     `env:"RATE_LIMIT_DEVICE_BURST" envDefault:"100"`
+	// Limits shared by all devices of a type, e.g. "camera=50:200,sensor=10:20".
+	DeviceTypes map[string]string `env:"RATE_LIMIT_DEVICE_TYPES" envSeparator:"," envKeyValSeparator:"="`
+	// Let every Nth message over the limit through, zero drops all of them.
+	SampleN uint64 `env:"RATE_LIMIT_SAMPLE_N"`
+}
+
+type QuarantineConfig struct {
+	MaxAge                  time.Duration `env:"QUARANTINE_MAX_AGE" envDefault:"168h"`
+	MaxMessagesPerSource    int64         `env:"QUARANTINE_MAX_MESSAGES_PER_SOURCE" envDefault:"10000"`
+	MaxBytes                int64         `env:"QUARANTINE_MAX_BYTES" envDefault:"1073741824"`
+	MaxSources              int           `env:"QUARANTINE_MAX_SOURCES" envDefault:"1000"`
+	AutoRegisterCIDRs       []string      `env:"QUARANTINE_AUTO_REGISTER_CIDRS" envSeparator:","`
+	AutoRegisterDeviceType  string        `env:"QUARANTINE_AUTO_REGISTER_DEVICE_TYPE" envDefault:"auto"`
+	AutoRegisterResponsible []int32       `env:"QUARANTINE_AUTO_REGISTER_RESPONSIBLE" envSeparator:","`
 }
 
 var (
@@ -0,0 +1,113 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"data-ingestion-service/config"
+	"data-ingestion-service/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// Run tails the configured files and ships their lines until ctx is done. A
+// file position is saved only after its lines were delivered, so lines may
+// be sent twice after a crash but are never skipped.
+func Run(ctx context.Context, cfg *config.Agent) {
+	log := logger.New(logger.Config{
+		LogLevel:    cfg.Logger.LogLevel,
+		ServiceName: cfg.Logger.ServiceName,
+		LogPath:     cfg.Logger.LogPath,
+	})
+
+	files, err := LoadFilesConfig(cfg.Agent.FilesConfig)
+	if err != nil {
+		log.Fatal(fmt.Errorf("LoadFilesConfig: %w", err).Error())
+	}
+
+	state, err := loadOffsets(cfg.Agent.StateFile)
+	if err != nil {
+		log.Fatal(fmt.Errorf("loadOffsets: %w", err).Error())
+	}
+
+	tailers := make([]*tailer, 0, len(files.Files))
+	for _, file := range files.Files {
+		parser, err := newParser(file, cfg.Agent.Address)
+		if err != nil {
+			log.Fatal(fmt.Errorf("newParser: %w", err).Error())
+		}
+		tailers = append(tailers, newTailer(file, parser))
+	}
+	defer func() {
+		for _, t := range tailers {
+			t.close()
+		}
+	}()
+
+	shipper := newShipper(shipperConfig{
+		IngestionURL:   cfg.Agent.IngestionURL,
+		DeviceKey:      cfg.Agent.DeviceKey,
+		RequestTimeout: cfg.Agent.RequestTimeout,
+		RetryMin:       cfg.Agent.RetryMin,
+		RetryMax:       cfg.Agent.RetryMax,
+		Log:            log,
+	})
+
+	log.Info("Running agent", zap.Int("files", len(tailers)))
+
+	ticker := time.NewTicker(cfg.Agent.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		entries := make([]entry, 0, cfg.Agent.BatchSize)
+		for _, t := range tailers {
+			saved, known := state.Files[t.path]
+			if err = t.poll(saved, known); err != nil {
+				log.Warn("t.poll", zap.Error(err), zap.String("path", t.path))
+				continue
+			}
+
+			lines, err := t.read(cfg.Agent.BatchSize - len(entries))
+			if err != nil {
+				log.Warn("t.read", zap.Error(err), zap.String("path", t.path))
+			}
+			for _, l := range lines {
+				entries = append(entries, t.parser.parse(l))
+			}
+		}
+
+		if len(entries) > 0 {
+			if err = shipper.ship(ctx, entries); err != nil {
+				log.Info("Agent stopped before the batch was delivered")
+				return
+			}
+		}
+
+		changed := false
+		for _, t := range tailers {
+			position, ok := t.position()
+			if ok && position != state.Files[t.path] {
+				state.Files[t.path] = position
+				changed = true
+			}
+		}
+		if changed {
+			if err = state.save(); err != nil {
+				log.Error("state.save", zap.Error(err))
+			}
+		}
+
+		// A full batch means there is a backlog, read on without waiting.
+		if len(entries) >= cfg.Agent.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Info("Agent stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package agent
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// fileID identifies a file by device and inode, which survive a rename.
+func fileID(info os.FileInfo) string {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(stat.Dev), 10) + ":" + strconv.FormatUint(stat.Ino, 10) //nolint:unconvert
+}
@@ -0,0 +1,168 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+// Named groups of a parser that are mapped to the message, any other named
+// group is sent as an attribute.
+const (
+	groupMessage     = "message"
+	groupComponent   = "component"
+	groupMessageType = "message_type"
+	groupTime        = "time"
+
+	attrFile = "file"
+
+	defaultComponent   = "agent"
+	defaultMessageType = "info"
+
+	// Component column in data-processing-service is varchar(30).
+	maxComponentLen = 30
+)
+
+var errNoFiles = errors.New("no files configured")
+
+type FilesConfig struct {
+	Files []FileConfig `json:"files"`
+}
+
+type FileConfig struct {
+	Path string `json:"path"`
+	// Address overrides AGENT_ADDRESS for this file.
+	Address string `json:"address"`
+	// Parser is a regexp with named groups message, component, message_type
+	// and time. Lines it does not match are sent whole with the defaults.
+	Parser      string `json:"parser"`
+	TimeLayout  string `json:"time_layout"`
+	Component   string `json:"component"`
+	MessageType string `json:"message_type"`
+	// FromBeginning reads a file seen for the first time from the start
+	// instead of only the lines appended from now on.
+	FromBeginning bool `json:"from_beginning"`
+}
+
+func LoadFilesConfig(path string) (FilesConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return FilesConfig{}, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	var cfg FilesConfig
+	if err = jsoniter.Unmarshal(data, &cfg); err != nil {
+		return FilesConfig{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	if len(cfg.Files) == 0 {
+		return FilesConfig{}, errNoFiles
+	}
+
+	for _, file := range cfg.Files {
+		if file.Path == "" {
+			return FilesConfig{}, errors.New("file path is empty")
+		}
+		if _, err = newParser(file, ""); err != nil {
+			return FilesConfig{}, fmt.Errorf("newParser %s: %w", file.Path, err)
+		}
+	}
+
+	return cfg, nil
+}
+
+type parser struct {
+	re          *regexp.Regexp
+	timeLayout  string
+	path        string
+	address     string
+	component   string
+	messageType string
+}
+
+func newParser(cfg FileConfig, address string) (*parser, error) {
+	p := &parser{
+		timeLayout:  cfg.TimeLayout,
+		path:        cfg.Path,
+		address:     address,
+		component:   cfg.Component,
+		messageType: cfg.MessageType,
+	}
+
+	if cfg.Address != "" {
+		p.address = cfg.Address
+	}
+	if p.component == "" {
+		p.component = defaultComponent
+	}
+	if p.messageType == "" {
+		p.messageType = defaultMessageType
+	}
+	if p.timeLayout == "" {
+		p.timeLayout = time.RFC3339
+	}
+
+	if cfg.Parser != "" {
+		re, err := regexp.Compile(cfg.Parser)
+		if err != nil {
+			return nil, fmt.Errorf("regexp.Compile: %w", err)
+		}
+		p.re = re
+	}
+
+	return p, nil
+}
+
+// parse never fails, a line that does not match the parser is still shipped
+// so nothing is lost because of a wrong expression.
+func (p *parser) parse(line string) entry {
+	e := entry{
+		Message:     line,
+		MessageType: p.messageType,
+		Component:   p.component,
+		Address:     p.address,
+		Attributes:  map[string]string{attrFile: p.path},
+	}
+
+	if p.re == nil {
+		return e
+	}
+
+	match := p.re.FindStringSubmatch(line)
+	if match == nil {
+		return e
+	}
+
+	for i, name := range p.re.SubexpNames() {
+		value := match[i]
+		if name == "" || value == "" {
+			continue
+		}
+
+		switch name {
+		case groupMessage:
+			e.Message = value
+		case groupComponent:
+			e.Component = value
+		case groupMessageType:
+			e.MessageType = strings.ToLower(value)
+		case groupTime:
+			if eventTime, err := time.Parse(p.timeLayout, value); err == nil {
+				e.EventTime = &eventTime
+			}
+		default:
+			e.Attributes[name] = value
+		}
+	}
+
+	if len(e.Component) > maxComponentLen {
+		e.Component = e.Component[:maxComponentLen]
+	}
+
+	return e
+}
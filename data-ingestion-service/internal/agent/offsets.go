@@ -0,0 +1,73 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	dirPerm  = 0o755
+	filePerm = 0o644
+)
+
+// fileOffset is the position up to which a file has been delivered. FileID
+// tells whether the file at the path is still the same one after a restart.
+type fileOffset struct {
+	FileID string `json:"file_id"`
+	Offset int64  `json:"offset"`
+}
+
+type offsets struct {
+	path  string
+	Files map[string]fileOffset `json:"files"`
+}
+
+func loadOffsets(path string) (*offsets, error) {
+	o := &offsets{
+		path:  path,
+		Files: map[string]fileOffset{},
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return o, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	if err = jsoniter.Unmarshal(data, o); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return o, nil
+}
+
+// save replaces the state file atomically so a crash never leaves it half
+// written.
+func (o *offsets) save() error {
+	data, err := jsoniter.Marshal(o)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	if err = os.MkdirAll(filepath.Dir(o.path), dirPerm); err != nil {
+		return fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	tmp := o.path + ".tmp"
+	if err = os.WriteFile(tmp, data, filePerm); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+
+	if err = os.Rename(tmp, o.path); err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+)
+
+const (
+	sendBatchPath   = "/messages/send_batch"
+	deviceKeyHeader = "X-Device-Key"
+
+	contentTypeNDJSON = "application/x-ndjson"
+
+	// Error bodies are only read for the log.
+	maxErrorBodySize = 4096
+)
+
+// entry is a send_msg body.
+type entry struct {
+	Message     string            `json:"message"`
+	MessageType string            `json:"message_type"`
+	Component   string            `json:"component"`
+	Address     string            `json:"address"`
+	EventTime   *time.Time        `json:"event_time,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+}
+
+type (
+	batchItemResult struct {
+		Index      int    `json:"index"`
+		Status     string `json:"status"`
+		Reason     string `json:"reason"`
+		RetryAfter int    `json:"retry_after"`
+	}
+
+	sendBatchResp struct {
+		Accepted int               `json:"accepted"`
+		Rejected int               `json:"rejected"`
+		Results  []batchItemResult `json:"results"`
+	}
+)
+
+// errTooLarge is a batch over the body limit of the server, it is sent again
+// in halves.
+var errTooLarge = errors.New("batch too large")
+
+type shipper struct {
+	client    *http.Client
+	url       string
+	deviceKey string
+
+	retryMin time.Duration
+	retryMax time.Duration
+
+	log *zap.Logger
+}
+
+type shipperConfig struct {
+	IngestionURL   string
+	DeviceKey      string
+	RequestTimeout time.Duration
+	RetryMin       time.Duration
+	RetryMax       time.Duration
+
+	Log *zap.Logger
+}
+
+func newShipper(cfg shipperConfig) *shipper {
+	return &shipper{
+		client:    &http.Client{Timeout: cfg.RequestTimeout},
+		url:       strings.TrimRight(cfg.IngestionURL, "/") + sendBatchPath,
+		deviceKey: cfg.DeviceKey,
+		retryMin:  cfg.RetryMin,
+		retryMax:  cfg.RetryMax,
+		log:       cfg.Log,
+	}
+}
+
+// ship retries until every entry is accepted, rejected for good or ctx is
+// done. Only in the last case the entries must be read again, so only then an
+// error is returned. An entry is only given up when the server rejected that
+// entry, a refused batch, e.g. for a wrong device key, is retried until it is
+// fixed.
+func (s *shipper) ship(ctx context.Context, entries []entry) error {
+	backoff := s.retryMin
+
+	for len(entries) > 0 {
+		resp, retryAfter, err := s.send(ctx, entries)
+		switch {
+		case errors.Is(err, errTooLarge) && len(entries) > 1:
+			half := len(entries) / 2
+			if err = s.ship(ctx, entries[:half]); err != nil {
+				return err
+			}
+			entries = entries[half:]
+			continue
+
+		case errors.Is(err, errTooLarge):
+			s.log.Error("entry dropped", zap.Error(err), zap.String("message", entries[0].Message))
+			return nil
+
+		case err != nil:
+			if retryAfter == 0 {
+				retryAfter = backoff
+				backoff = min(backoff*2, s.retryMax)
+			}
+			s.log.Warn("s.send", zap.Error(err), zap.Duration("retry_after", retryAfter))
+
+		default:
+			backoff = s.retryMin
+			entries, retryAfter = s.retryable(entries, resp)
+			if len(entries) == 0 {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err() //nolint:wrapcheck
+		case <-time.After(retryAfter):
+		}
+	}
+
+	return nil
+}
+
+// retryable picks the entries refused by the rate limiter or not acknowledged
+// in time, both come with a RetryAfter. Other rejections are logged and
+// dropped since resending them can not succeed.
+func (s *shipper) retryable(entries []entry, resp sendBatchResp) ([]entry, time.Duration) {
+	var (
+		retry      []entry
+		retryAfter time.Duration
+	)
+
+	for _, result := range resp.Results {
+		if result.Status == "accepted" || result.Index >= len(entries) {
+			continue
+		}
+
+		if result.RetryAfter > 0 {
+			retry = append(retry, entries[result.Index])
+			retryAfter = max(retryAfter, time.Duration(result.RetryAfter)*time.Second)
+			continue
+		}
+
+		s.log.Warn("entry rejected", zap.String("reason", result.Reason), zap.String("message", entries[result.Index].Message))
+	}
+
+	return retry, retryAfter
+}
+
+func (s *shipper) send(ctx context.Context, entries []entry) (sendBatchResp, time.Duration, error) {
+	var body bytes.Buffer
+	encoder := jsoniter.NewEncoder(&body)
+	for _, e := range entries {
+		if err := encoder.Encode(e); err != nil {
+			return sendBatchResp{}, 0, fmt.Errorf("json.Encode: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, &body)
+	if err != nil {
+		return sendBatchResp{}, 0, fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	req.Header.Set("Content-Type", contentTypeNDJSON)
+	if s.deviceKey != "" {
+		req.Header.Set(deviceKeyHeader, s.deviceKey)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return sendBatchResp{}, 0, fmt.Errorf("s.client.Do: %w", err)
+	}
+	defer res.Body.Close()
+
+	retryAfter := parseRetryAfter(res.Header.Get("Retry-After"))
+
+	// 429 comes with the per item results too, but then nothing was accepted.
+	switch {
+	case res.StatusCode == http.StatusRequestEntityTooLarge:
+		return sendBatchResp{}, 0, fmt.Errorf("%w: %d entries", errTooLarge, len(entries))
+
+	case res.StatusCode != http.StatusOK:
+		data, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+		return sendBatchResp{}, retryAfter, fmt.Errorf("status %d: %s", res.StatusCode, data)
+	}
+
+	var resp sendBatchResp
+	if err = jsoniter.NewDecoder(res.Body).Decode(&resp); err != nil {
+		return sendBatchResp{}, 0, fmt.Errorf("json.Decode: %w", err)
+	}
+
+	return resp, retryAfter, nil
+}
+
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
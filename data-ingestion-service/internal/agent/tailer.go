@@ -0,0 +1,182 @@
+package agent
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// Longer lines are cut, the rest up to the newline is skipped.
+const maxLineSize = 64 * 1024
+
+// tailer follows a file by path like tail -F. A rotated file is read to the
+// end before the new one is opened, a truncated file is read again from the
+// start.
+type tailer struct {
+	path          string
+	fromBeginning bool
+	parser        *parser
+
+	file   *os.File
+	info   os.FileInfo
+	reader *bufio.Reader
+	offset int64
+
+	// next is the file that replaced the open one at the path.
+	next os.FileInfo
+}
+
+func newTailer(cfg FileConfig, parser *parser) *tailer {
+	return &tailer{
+		path:          cfg.Path,
+		fromBeginning: cfg.FromBeginning,
+		parser:        parser,
+	}
+}
+
+// poll notices rotation and truncation, saved is the delivered position from
+// the previous run and is only used when the file is opened the first time.
+func (t *tailer) poll(saved fileOffset, known bool) error {
+	info, err := os.Stat(t.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// Moved away and not recreated yet, the open file is still drained.
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("os.Stat: %w", err)
+	}
+
+	switch {
+	case t.file == nil:
+		offset := info.Size()
+		if t.fromBeginning {
+			offset = 0
+		}
+		if known && (saved.FileID == "" || saved.FileID == fileID(info)) && saved.Offset <= info.Size() {
+			offset = saved.Offset
+		}
+		return t.open(info, offset)
+
+	case !os.SameFile(t.info, info):
+		t.next = info
+
+	case info.Size() < t.offset:
+		return t.seek(0)
+	}
+
+	return nil
+}
+
+// read returns up to limit complete lines. A line without its newline yet is
+// left for the next call.
+func (t *tailer) read(limit int) ([]string, error) {
+	if t.file == nil || limit <= 0 {
+		return nil, nil
+	}
+
+	lines := make([]string, 0)
+	for len(lines) < limit {
+		data, n, err := t.readLine()
+		// A rotated file gets no more writes, so its last line is complete
+		// even without the newline.
+		if errors.Is(err, io.EOF) && (n == 0 || t.next == nil) {
+			if n > 0 {
+				if err = t.seek(t.offset); err != nil {
+					return lines, err
+				}
+			}
+			break
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return lines, fmt.Errorf("t.readLine: %w", err)
+		}
+
+		t.offset += int64(n)
+
+		text := string(bytes.TrimRight(data, "\r\n"))
+		if text == "" {
+			continue
+		}
+
+		lines = append(lines, text)
+	}
+
+	// Switch over only once the rotated file has nothing left.
+	if len(lines) == 0 && t.next != nil {
+		next := t.next
+		t.close()
+		if err := t.open(next, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return lines, nil
+}
+
+// readLine returns the line cut to maxLineSize and the number of bytes it
+// took in the file, the bytes past the cut are read and discarded.
+func (t *tailer) readLine() ([]byte, int, error) {
+	data, err := t.reader.ReadSlice('\n')
+	res := bytes.Clone(data)
+	n := len(data)
+
+	for errors.Is(err, bufio.ErrBufferFull) {
+		data, err = t.reader.ReadSlice('\n')
+		n += len(data)
+	}
+
+	return res, n, err //nolint:wrapcheck
+}
+
+func (t *tailer) open(info os.FileInfo, offset int64) error {
+	file, err := os.Open(t.path)
+	if err != nil {
+		return fmt.Errorf("os.Open: %w", err)
+	}
+
+	// The path may have been replaced again since the stat.
+	if openedInfo, err := file.Stat(); err == nil {
+		info = openedInfo
+	}
+
+	t.file = file
+	t.info = info
+	t.next = nil
+	t.reader = bufio.NewReaderSize(file, maxLineSize)
+
+	return t.seek(offset)
+}
+
+func (t *tailer) seek(offset int64) error {
+	if _, err := t.file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("t.file.Seek: %w", err)
+	}
+
+	t.offset = offset
+	t.reader.Reset(t.file)
+
+	return nil
+}
+
+// position is what gets saved once everything read so far is delivered.
+func (t *tailer) position() (fileOffset, bool) {
+	if t.file == nil {
+		return fileOffset{}, false
+	}
+
+	return fileOffset{FileID: fileID(t.info), Offset: t.offset}, true
+}
+
+func (t *tailer) close() {
+	if t.file != nil {
+		_ = t.file.Close()
+	}
+
+	t.file = nil
+	t.info = nil
+	t.reader = nil
+}
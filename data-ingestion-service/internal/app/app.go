@@ -3,15 +3,21 @@ package app
 import (
 	"context"
 	"fmt"
+	"net"
 	httpbase "net/http"
 
 	"data-ingestion-service/config"
 	"data-ingestion-service/internal/services"
 	"data-ingestion-service/internal/transport/http"
+	"data-ingestion-service/internal/transport/http/v1/devicechecker"
+	"data-ingestion-service/internal/transport/mqtt"
 	natslisteners "data-ingestion-service/internal/transport/natslistener"
+	"data-ingestion-service/internal/transport/syslog"
 	"data-ingestion-service/pkg/closer"
 	"data-ingestion-service/pkg/logger"
 	"data-ingestion-service/pkg/nats"
+	"data-ingestion-service/pkg/ratelimit"
+	"data-ingestion-service/pkg/spool"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
@@ -31,14 +37,66 @@ func Run(ctx context.Context, cfg *config.Config, stop context.CancelFunc) {
 
 	devicesService := services.NewDeviceService(services.Config{})
 
-	listeners := natslisteners.NewListener(natslisteners.Config{
-		NatsConn:       nats.NatsConn,
-		Js:             nats.Js,
-		Log:            log,
-		Timeout:        cfg.Nats.Timeout,
+	var messagesSpool *spool.Spool
+	if cfg.Spool.Dir != "" {
+		messagesSpool, err = spool.Open(spool.Config{
+			Dir:          cfg.Spool.Dir,
+			MaxBytes:     cfg.Spool.MaxBytes,
+			MaxAge:       cfg.Spool.MaxAge,
+			SegmentBytes: cfg.Spool.SegmentBytes,
+		})
+		if err != nil {
+			log.Fatal(fmt.Errorf("spool.Open: %w", err).Error())
+		}
+	}
+
+	autoRegisterNets := make([]*net.IPNet, 0, len(cfg.Quarantine.AutoRegisterCIDRs))
+	for _, cidr := range cfg.Quarantine.AutoRegisterCIDRs {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Fatal(fmt.Errorf("net.ParseCIDR: %w", err).Error())
+		}
+		autoRegisterNets = append(autoRegisterNets, ipNet)
+	}
+
+	rateLimitDeviceTypes := make(map[string]ratelimit.Limit, len(cfg.RateLimit.DeviceTypes))
+	for deviceType, value := range cfg.RateLimit.DeviceTypes {
+		limit, err := ratelimit.ParseLimit(value)
+		if err != nil {
+			log.Fatal(fmt.Errorf("ratelimit.ParseLimit: %w", err).Error())
+		}
+		rateLimitDeviceTypes[deviceType] = limit
+	}
+
+	rateLimiter := services.NewRateLimiter(services.RateLimitConfig{
+		Device: ratelimit.Limit{
+			Rate:  cfg.RateLimit.DeviceRate,
+			Burst: cfg.RateLimit.DeviceBurst,
+		},
+		DeviceTypes:    rateLimitDeviceTypes,
+		SampleN:        cfg.RateLimit.SampleN,
 		DevicesService: devicesService,
 	})
 
+	listeners := natslisteners.NewListener(natslisteners.Config{
+		NatsConn:            nats.NatsConn,
+		Js:                  nats.Js,
+		Log:                 log,
+		Timeout:             cfg.Nats.Timeout,
+		DevicesService:      devicesService,
+		Spool:               messagesSpool,
+		SpoolReplayInterval: cfg.Spool.ReplayInterval,
+		Quarantine: natslisteners.QuarantineConfig{
+			MaxAge:                  cfg.Quarantine.MaxAge,
+			MaxMessagesPerSource:    cfg.Quarantine.MaxMessagesPerSource,
+			MaxBytes:                cfg.Quarantine.MaxBytes,
+			MaxSources:              cfg.Quarantine.MaxSources,
+			AutoRegisterNets:        autoRegisterNets,
+			AutoRegisterDeviceType:  cfg.Quarantine.AutoRegisterDeviceType,
+			AutoRegisterResponsible: cfg.Quarantine.AutoRegisterResponsible,
+		},
+	})
+
 	go func() {
 		if err = listeners.Run(); err != nil {
 			log.Error(fmt.Errorf("error occurred while running NATs listeners: %w", err).Error())
@@ -48,13 +106,25 @@ func Run(ctx context.Context, cfg *config.Config, stop context.CancelFunc) {
 
 	log.Info("Running NATs listener")
 
+	deviceCheckProbes, err := devicechecker.LoadProbesConfig(cfg.Server.DeviceCheckProbesFile)
+	if err != nil {
+		log.Fatal(fmt.Errorf("devicechecker.LoadProbesConfig: %w", err).Error())
+	}
+
 	httpServer := http.NewServer(http.Config{
-		Log:               log,
-		Addr:              cfg.Server.Addr,
-		LogQuerys:         cfg.Server.LogQuerys,
-		MessageHandler:    listeners,
-		DevicesService:    devicesService,
-		DeviceCheckPeriod: cfg.Server.DeviceCheckPeriod,
+		Log:                log,
+		Addr:               cfg.Server.Addr,
+		LogQuerys:          cfg.Server.LogQuerys,
+		MessageHandler:     listeners,
+		DevicesService:     devicesService,
+		DeviceCheckPeriod:  cfg.Server.DeviceCheckPeriod,
+		DeviceCheckWorkers: cfg.Server.DeviceCheckWorkers,
+		DeviceCheckProbes:  deviceCheckProbes,
+
+		DeviceCheckFailureThreshold:  cfg.Server.DeviceCheckFailureThreshold,
+		DeviceCheckRecoveryThreshold: cfg.Server.DeviceCheckRecoveryThreshold,
+		RequireDeviceKey:             cfg.Server.RequireDeviceKey,
+		RateLimiter:                  rateLimiter,
 	})
 
 	go func() {
@@ -74,6 +144,42 @@ func Run(ctx context.Context, cfg *config.Config, stop context.CancelFunc) {
 
 	log.Info("Running HTTP server")
 
+	syslogServer := syslog.NewServer(syslog.Config{
+		UDPAddr:      cfg.Syslog.UDPAddr,
+		TCPAddr:      cfg.Syslog.TCPAddr,
+		NatsHandlers: listeners,
+		RateLimiter:  rateLimiter,
+		Log:          log,
+	})
+
+	go func() {
+		if err = syslogServer.Run(); err != nil {
+			log.Error(fmt.Errorf("error occurred while running syslog server: %w", err).Error())
+			stop()
+		}
+	}()
+
+	log.Info("Running syslog server")
+
+	mqttSubscriber := mqtt.NewSubscriber(mqtt.Config{
+		BrokerURL:    cfg.MQTT.BrokerURL,
+		ClientID:     cfg.MQTT.ClientID,
+		Username:     cfg.MQTT.Username,
+		Password:     cfg.MQTT.Password,
+		Topics:       cfg.MQTT.Topics,
+		QoS:          cfg.MQTT.QoS,
+		NatsHandlers: listeners,
+		RateLimiter:  rateLimiter,
+		Log:          log,
+	})
+
+	if err = mqttSubscriber.Run(); err != nil {
+		log.Error(fmt.Errorf("error occurred while running mqtt subscriber: %w", err).Error())
+		stop()
+	}
+
+	log.Info("Running MQTT subscriber")
+
 	// Shutdown
 	<-ctx.Done()
 
@@ -81,8 +187,11 @@ func Run(ctx context.Context, cfg *config.Config, stop context.CancelFunc) {
 
 	closer := closer.Closer{}
 
-	closer.Add(nats.Close)
 	closer.Add(httpServer.Stop)
+	closer.Add(syslogServer.Stop)
+	closer.Add(mqttSubscriber.Stop)
+	closer.Add(listeners.Stop)
+	closer.Add(nats.Close)
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.App.ShutdownTimeout)
 	defer cancel()
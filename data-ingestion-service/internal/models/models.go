@@ -19,4 +19,16 @@ type Message struct {
 	MessageType string `db:"message_type"`
 	Component   string `db:"component"`
 	DeviceIP    string `db:"device_ip"`
+
+	// EventTime is zero if the device did not report one, a zero ReceivedAt
+	// is set when the message is published.
+	EventTime  time.Time         `db:"event_time"`
+	ReceivedAt time.Time         `db:"received_at"`
+	Attributes map[string]string `db:"attributes"`
+	// MessageID is an optional client ID used to drop retried duplicates.
+	MessageID string `db:"message_id"`
+	// PeerIP is the address the message was received from, DeviceIP is the
+	// one the sender claims. It is empty when the sender is not the device,
+	// e.g. an MQTT broker or a replay, and is not published.
+	PeerIP string `db:"-"`
 }
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"crypto/sha256"
 	"data-ingestion-service/internal/models"
+	"encoding/hex"
 	"sync"
 )
 
@@ -17,28 +19,113 @@ func NewDeviceService(cfg Config) DeviceService {
 var (
 	devicesMutex sync.Mutex
 	devicesByIp  = map[string]models.Device{}
+	// Device id -> address, to find the entry to drop when a device is
+	// deleted or moved to another address.
+	addressByID = map[int32]string{}
+
+	// Hex encoded sha256 of the device ingestion key -> device id.
+	devicesByKeyHash = map[string]int32{}
+	// Devices that were issued a key, they must always send it.
+	devicesWithKey = map[int32]struct{}{}
 )
 
-func (ds *DeviceService) UpdateDevices(devices []models.Device) {
+func (ds *DeviceService) UpsertDevice(device models.Device) {
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	if address, ok := addressByID[device.ID]; ok && address != device.Address {
+		delete(devicesByIp, address)
+	}
+
+	addressByID[device.ID] = device.Address
+	devicesByIp[device.Address] = device
+}
+
+func (ds *DeviceService) DeleteDevice(deviceID int32) {
 	devicesMutex.Lock()
 	defer devicesMutex.Unlock()
 
-	devicesByIp = make(map[string]models.Device, len(devices))
-	for _, d := range devices {
-		devicesByIp[d.Address] = d
+	address, ok := addressByID[deviceID]
+	if !ok {
+		return
+	}
+
+	delete(addressByID, deviceID)
+	if devicesByIp[address].ID == deviceID {
+		delete(devicesByIp, address)
 	}
 }
 
 func (ds *DeviceService) GetDeviceIDByIp(address string) (int32, bool) {
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
 	device, ok := devicesByIp[address]
 	return device.ID, ok
 }
 
-func (ds *DeviceService) GetDevicesIPs() []string {
-	res := make([]string, 0, len(devicesByIp))
-	for ip := range devicesByIp {
-		res = append(res, ip)
+func (ds *DeviceService) GetDeviceByIp(address string) (models.Device, bool) {
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	device, ok := devicesByIp[address]
+	return device, ok
+}
+
+func (ds *DeviceService) GetDeviceAddressByName(name string) (string, bool) {
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	for _, device := range devicesByIp {
+		if device.Name == name {
+			return device.Address, true
+		}
+	}
+
+	return "", false
+}
+
+func (ds *DeviceService) GetDevices() []models.Device {
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	res := make([]models.Device, 0, len(devicesByIp))
+	for _, device := range devicesByIp {
+		res = append(res, device)
 	}
 
 	return res
 }
+
+func (ds *DeviceService) UpdateKeyHashes(keyHashes map[string]int32) {
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	devicesByKeyHash = keyHashes
+
+	devicesWithKey = make(map[int32]struct{}, len(keyHashes))
+	for _, deviceID := range keyHashes {
+		devicesWithKey[deviceID] = struct{}{}
+	}
+}
+
+// HasKey reports whether the device was issued an ingestion key.
+func (ds *DeviceService) HasKey(deviceID int32) bool {
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	_, ok := devicesWithKey[deviceID]
+	return ok
+}
+
+// GetDeviceIDByKey returns the device the ingestion key was issued to.
+func (ds *DeviceService) GetDeviceIDByKey(apiKey string) (int32, bool) {
+	sum := sha256.Sum256([]byte(apiKey))
+	keyHash := hex.EncodeToString(sum[:])
+
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	deviceID, ok := devicesByKeyHash[keyHash]
+	return deviceID, ok
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"data-ingestion-service/pkg/ratelimit"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// RateLimitThrottled is a message refused with a request to retry later.
+	RateLimitThrottled = "throttled"
+	// RateLimitDropped is a message lost because the transport can not retry.
+	RateLimitDropped = "dropped"
+
+	rateLimitSampled = "sampled"
+
+	unregisteredDevice = "unregistered"
+)
+
+type RateLimitConfig struct {
+	// Device is the limit of every single device (or unregistered address).
+	Device ratelimit.Limit
+	// DeviceTypes are shared by all registered devices of the type.
+	DeviceTypes map[string]ratelimit.Limit
+	// SampleN lets every Nth message over the limit through so a flooding
+	// device does not go completely dark, zero disables sampling.
+	SampleN uint64
+
+	DevicesService DeviceService
+}
+
+// RateLimiter applies per device and per device type token buckets to the
+// ingested messages. A nil RateLimiter allows everything.
+type RateLimiter struct {
+	limiter        *ratelimit.Limiter
+	device         ratelimit.Limit
+	deviceTypes    map[string]ratelimit.Limit
+	sampleN        uint64
+	devicesService DeviceService
+
+	mu      sync.Mutex
+	limited map[string]uint64
+
+	metrics *prometheus.CounterVec
+}
+
+func NewRateLimiter(cfg RateLimitConfig) *RateLimiter {
+	metrics := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ingress_rate_limited_total",
+			Help: "Messages over the ingestion rate limit by device and result",
+		},
+		[]string{"device", "result"},
+	)
+
+	prometheus.MustRegister(metrics)
+
+	return &RateLimiter{
+		limiter:        ratelimit.New(ratelimit.Config{}),
+		device:         cfg.Device,
+		deviceTypes:    cfg.DeviceTypes,
+		sampleN:        cfg.SampleN,
+		devicesService: cfg.DevicesService,
+		limited:        map[string]uint64{},
+		metrics:        metrics,
+	}
+}
+
+// Allow reports whether a message from the address may be published and, if
+// not, how long the sender should wait. Rejected messages are counted as
+// onReject, either RateLimitThrottled or RateLimitDropped.
+func (r *RateLimiter) Allow(address string, onReject string) (bool, time.Duration) {
+	if r == nil {
+		return true, 0
+	}
+
+	label := unregisteredDevice
+	keys := []ratelimit.Key{{Name: "device:" + address, Limit: r.device}}
+
+	if device, ok := r.devicesService.GetDeviceByIp(address); ok {
+		label = strconv.Itoa(int(device.ID))
+		if limit, ok := r.deviceTypes[device.DeviceType]; ok {
+			keys = append(keys, ratelimit.Key{Name: "type:" + device.DeviceType, Limit: limit})
+		}
+	}
+
+	ok, retryAfter := r.limiter.Allow(time.Now(), keys...)
+	if ok {
+		return true, 0
+	}
+
+	if r.sample(address) {
+		r.metrics.WithLabelValues(label, rateLimitSampled).Inc()
+		return true, 0
+	}
+
+	r.metrics.WithLabelValues(label, onReject).Inc()
+
+	return false, retryAfter
+}
+
+func (r *RateLimiter) sample(address string) bool {
+	if r.sampleN == 0 {
+		return false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.limited[address]++
+	if r.limited[address] < r.sampleN {
+		return false
+	}
+
+	delete(r.limited, address)
+
+	return true
+}
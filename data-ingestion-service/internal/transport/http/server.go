@@ -8,6 +8,7 @@ import (
 
 	"data-ingestion-service/internal/services"
 	v1 "data-ingestion-service/internal/transport/http/v1"
+	"data-ingestion-service/internal/transport/http/v1/devicechecker"
 	"data-ingestion-service/internal/transport/natslistener"
 
 	"github.com/gofiber/fiber/v3"
@@ -22,7 +23,15 @@ type Server struct {
 	messageHandler *natslistener.NatsListeners
 	devicesService services.DeviceService
 
-	deviceCheckPeriod int
+	deviceCheckPeriod  int
+	deviceCheckWorkers int
+	deviceCheckProbes  devicechecker.ProbesConfig
+
+	deviceCheckFailureThreshold  int
+	deviceCheckRecoveryThreshold int
+
+	requireDeviceKey bool
+	rateLimiter      *services.RateLimiter
 }
 
 type Config struct {
@@ -32,19 +41,37 @@ type Config struct {
 	MessageHandler *natslistener.NatsListeners
 	DevicesService services.DeviceService
 
-	DeviceCheckPeriod int
+	DeviceCheckPeriod  int
+	DeviceCheckWorkers int
+	DeviceCheckProbes  devicechecker.ProbesConfig
+
+	DeviceCheckFailureThreshold  int
+	DeviceCheckRecoveryThreshold int
+
+	RequireDeviceKey bool
+	RateLimiter      *services.RateLimiter
 }
 
 func NewServer(cfg Config) *Server {
 	server := &Server{
-		log:               cfg.Log,
-		addr:              cfg.Addr,
-		messageHandler:    cfg.MessageHandler,
-		devicesService:    cfg.DevicesService,
-		deviceCheckPeriod: cfg.DeviceCheckPeriod,
-		app:               nil,
+		log:                cfg.Log,
+		addr:               cfg.Addr,
+		messageHandler:     cfg.MessageHandler,
+		devicesService:     cfg.DevicesService,
+		deviceCheckPeriod:  cfg.DeviceCheckPeriod,
+		deviceCheckWorkers: cfg.DeviceCheckWorkers,
+		deviceCheckProbes:  cfg.DeviceCheckProbes,
+
+		deviceCheckFailureThreshold:  cfg.DeviceCheckFailureThreshold,
+		deviceCheckRecoveryThreshold: cfg.DeviceCheckRecoveryThreshold,
+		requireDeviceKey:             cfg.RequireDeviceKey,
+		rateLimiter:                  cfg.RateLimiter,
+		app:                          nil,
 	}
 
+	// StreamRequestBody is server wide in fasthttp but only send_batch reads
+	// the body as a stream, the other routes buffer it within BodyLimit in
+	// bufferBodyMW.
 	server.app = fiber.New(
 		fiber.Config{
 			ServerHeader:                 "",
@@ -71,7 +98,7 @@ func NewServer(cfg Config) *Server {
 			DisableDefaultContentType:    false,
 			DisableHeaderNormalizing:     false,
 			AppName:                      "",
-			StreamRequestBody:            false,
+			StreamRequestBody:            true,
 			DisablePreParseMultipartForm: false,
 			ReduceMemoryUsage:            false,
 			JSONEncoder:                  nil,
@@ -156,10 +183,17 @@ func (s *Server) setHandlers() {
 	rootRoute := s.app.Group("/data-ingestion-service")
 
 	handlerV1 := v1.NewHandler(v1.Config{
-		MessageHandler:    s.messageHandler,
-		DevicesService:    s.devicesService,
-		Log:               s.log,
-		DeviceCheckPeriod: s.deviceCheckPeriod,
+		MessageHandler:     s.messageHandler,
+		DevicesService:     s.devicesService,
+		Log:                s.log,
+		DeviceCheckPeriod:  s.deviceCheckPeriod,
+		DeviceCheckWorkers: s.deviceCheckWorkers,
+		DeviceCheckProbes:  s.deviceCheckProbes,
+
+		DeviceCheckFailureThreshold:  s.deviceCheckFailureThreshold,
+		DeviceCheckRecoveryThreshold: s.deviceCheckRecoveryThreshold,
+		RequireDeviceKey:             s.requireDeviceKey,
+		RateLimiter:                  s.rateLimiter,
 	})
 	{
 		apiV1 := rootRoute.Group("/v1")
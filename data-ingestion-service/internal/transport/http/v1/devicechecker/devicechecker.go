@@ -1,23 +1,42 @@
 package devicechecker
 
 import (
+	"context"
 	"data-ingestion-service/internal/models"
 	"data-ingestion-service/internal/services"
 	"data-ingestion-service/internal/transport/natslistener"
 	"fmt"
-	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 )
 
+const (
+	defaultWorkers = 16
+
+	resultUp   = "up"
+	resultDown = "down"
+)
+
 type devicechecekrHandler struct {
 	deviceService services.DeviceService
 	natsHandlers  *natslistener.NatsListeners
 
 	deviceCheckPeriod int
+	workers           int
+	probesConfig      ProbesConfig
+	probes            probeSet
+	states            *stateTracker
 	cron              *cron.Cron
 
+	probeDuration *prometheus.HistogramVec
+	transitions   *prometheus.CounterVec
+	devicesState  *prometheus.GaugeVec
+
 	log *zap.Logger
 }
 
@@ -26,50 +45,189 @@ type Config struct {
 	NatsHandlers  *natslistener.NatsListeners
 
 	DeviceCheckPeriod int
+	Workers           int
+	Probes            ProbesConfig
+	FailureThreshold  int
+	RecoveryThreshold int
 	Logger            *zap.Logger
 }
 
 func NewDeviceCheckerHandler(cfg *Config) *devicechecekrHandler {
-	cron := cron.New(cron.WithSeconds())
+	probeDuration := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "device_probe_duration_seconds",
+			Help:    "Device health-check probe latency",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"probe", "result"},
+	)
+
+	transitions := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "device_state_transitions_total",
+			Help: "Device reachability state transitions",
+		},
+		[]string{"from", "to"},
+	)
+
+	devicesState := prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "devices_state",
+			Help: "Number of devices in each reachability state",
+		},
+		[]string{"state"},
+	)
+
+	prometheus.MustRegister(probeDuration, transitions, devicesState)
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+
+	// A round that takes longer than the period is not started twice.
+	cron := cron.New(
+		cron.WithSeconds(),
+		cron.WithChain(cron.SkipIfStillRunning(cron.DiscardLogger)),
+	)
 	return &devicechecekrHandler{
 		deviceService:     cfg.DeviceService,
 		deviceCheckPeriod: cfg.DeviceCheckPeriod,
+		workers:           workers,
+		probesConfig:      cfg.Probes,
+		states:            newStateTracker(cfg.FailureThreshold, cfg.RecoveryThreshold),
 		natsHandlers:      cfg.NatsHandlers,
 		cron:              cron,
+		probeDuration:     probeDuration,
+		transitions:       transitions,
+		devicesState:      devicesState,
 		log:               cfg.Logger,
 	}
 }
 
 func (dch *devicechecekrHandler) Start() {
-	_, err := dch.cron.AddFunc(fmt.Sprintf("*/%d * * * * *", dch.deviceCheckPeriod),
-		func() {
-			ips := dch.deviceService.GetDevicesIPs()
-			for _, ip := range ips {
-				resp, err := http.Get(fmt.Sprintf("http://%s/healthcheck", ip))
-				if err != nil {
-					dch.natsHandlers.PublishSaveMessage(models.Message{
-						DeviceIP:    ip,
-						Message:     fmt.Sprintf("unable to connect to device %s", ip),
-						MessageType: "error",
-						Component:   "General",
-					})
-					return
-				}
-
-				if resp.StatusCode != http.StatusOK {
-					dch.natsHandlers.PublishSaveMessage(models.Message{
-						DeviceIP:    ip,
-						Message:     fmt.Sprintf("device %s status is not OK", ip),
-						MessageType: "error",
-						Component:   "General",
-					})
-				}
-			}
-		},
-	)
+	probes, err := newProbeSet(dch.probesConfig)
+	if err != nil {
+		dch.log.Error("error building device checker probes", zap.Error(err))
+		return
+	}
+	dch.probes = probes
+
+	_, err = dch.cron.AddFunc(fmt.Sprintf("*/%d * * * * *", dch.deviceCheckPeriod), dch.checkDevices)
 	if err != nil {
 		dch.log.Error("error adding device checker to cron", zap.Error(err))
 	}
 
 	dch.cron.Start()
 }
+
+func (dch *devicechecekrHandler) checkDevices() {
+	devices := dch.deviceService.GetDevices()
+	dch.states.retain(devices)
+
+	jobs := make(chan models.Device)
+	wg := sync.WaitGroup{}
+
+	for range min(dch.workers, len(devices)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for device := range jobs {
+				dch.checkDevice(device)
+			}
+		}()
+	}
+
+	for _, device := range devices {
+		jobs <- device
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	counts := dch.states.counts()
+	for _, state := range deviceStates {
+		dch.devicesState.WithLabelValues(string(state)).Set(float64(counts[state]))
+	}
+}
+
+type probeResult struct {
+	name    string
+	latency time.Duration
+	err     error
+}
+
+// checkDevice runs every probe configured for the device type. The device
+// is observed UP if all of them pass, DOWN if all fail and DEGRADED
+// otherwise; a message is published only when the tracked state changes.
+func (dch *devicechecekrHandler) checkDevice(device models.Device) {
+	probes := dch.probes.forDeviceType(device.DeviceType)
+	results := make([]probeResult, 0, len(probes))
+	failed := 0
+
+	for _, p := range probes {
+		ctx, cancel := context.WithTimeout(context.Background(), p.timeout())
+		start := time.Now()
+		err := p.check(ctx, device.Address)
+		latency := time.Since(start)
+		cancel()
+
+		result := resultUp
+		if err != nil {
+			result = resultDown
+			failed++
+		}
+		dch.probeDuration.WithLabelValues(p.name(), result).Observe(latency.Seconds())
+
+		results = append(results, probeResult{name: p.name(), latency: latency, err: err})
+	}
+
+	observed := stateUp
+	switch {
+	case failed == len(probes) && failed > 0:
+		observed = stateDown
+	case failed > 0:
+		observed = stateDegraded
+	}
+
+	changed, ok := dch.states.observe(device.ID, observed, time.Now())
+	if !ok {
+		return
+	}
+	dch.transitions.WithLabelValues(string(changed.from), string(changed.to)).Inc()
+
+	message := models.Message{
+		DeviceIP: device.Address,
+		Message: fmt.Sprintf("device %s is %s (was %s for %s): %s",
+			device.Address, changed.to, changed.from, changed.inPrevious.Round(time.Second), formatResults(results)),
+		MessageType: messageType(changed.to),
+		Component:   "General",
+	}
+
+	if err := dch.natsHandlers.PublishSaveMessage(message); err != nil {
+		dch.log.Error("dch.natsHandlers.PublishSaveMessage", zap.Error(err), zap.String("ip", device.Address))
+	}
+}
+
+func messageType(state deviceState) string {
+	switch state {
+	case stateDown:
+		return "error"
+	case stateDegraded:
+		return "warning"
+	}
+	return "info"
+}
+
+func formatResults(results []probeResult) string {
+	parts := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.err != nil {
+			parts = append(parts, fmt.Sprintf("%s failed after %s (%s)", result.name, result.latency.Round(time.Millisecond), result.err))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s ok in %s", result.name, result.latency.Round(time.Millisecond)))
+	}
+
+	return strings.Join(parts, ", ")
+}
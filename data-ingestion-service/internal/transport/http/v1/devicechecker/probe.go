@@ -0,0 +1,347 @@
+package devicechecker
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	probeTCP   = "tcp"
+	probeHTTP  = "http"
+	probeHTTPS = "https"
+	probeDNS   = "dns"
+
+	defaultProbeTimeout = 3 * time.Second
+	defaultDNSPort      = 53
+
+	// Only this much of a response body is searched for body_match.
+	maxBodyMatchSize = 64 * 1024
+)
+
+// ProbesConfig is read from a JSON file, probes for a device type replace
+// the default ones:
+//
+//	{
+//	  "default": [{"type": "http", "path": "/healthcheck", "expected_status": 200}],
+//	  "device_types": {
+//	    "router": [{"type": "tcp", "port": 22, "timeout": "2s"}],
+//	    "dns": [{"type": "dns", "query": "example.com"}]
+//	  }
+//	}
+type ProbesConfig struct {
+	Default     []ProbeConfig            `json:"default"`
+	DeviceTypes map[string][]ProbeConfig `json:"device_types"`
+}
+
+type ProbeConfig struct {
+	Type    string   `json:"type"`
+	Port    int      `json:"port"`
+	Timeout Duration `json:"timeout"`
+
+	// http and https
+	Path               string `json:"path"`
+	ExpectedStatus     int    `json:"expected_status"`
+	BodyMatch          string `json:"body_match"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+
+	// dns, the device itself is asked to resolve the name.
+	Query string `json:"query"`
+}
+
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := jsoniter.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("time.ParseDuration: %w", err)
+	}
+
+	*d = Duration(duration)
+	return nil
+}
+
+// DefaultProbesConfig keeps the original behaviour: GET /healthcheck over
+// plain HTTP must answer 200.
+func DefaultProbesConfig() ProbesConfig {
+	return ProbesConfig{
+		Default: []ProbeConfig{
+			{
+				Type:           probeHTTP,
+				Path:           "/healthcheck",
+				ExpectedStatus: http.StatusOK,
+			},
+		},
+	}
+}
+
+func LoadProbesConfig(path string) (ProbesConfig, error) {
+	if path == "" {
+		return DefaultProbesConfig(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ProbesConfig{}, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	var cfg ProbesConfig
+	if err = jsoniter.Unmarshal(data, &cfg); err != nil {
+		return ProbesConfig{}, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	if len(cfg.Default) == 0 {
+		cfg.Default = DefaultProbesConfig().Default
+	}
+
+	if _, err = newProbeSet(cfg); err != nil {
+		return ProbesConfig{}, fmt.Errorf("newProbeSet: %w", err)
+	}
+
+	return cfg, nil
+}
+
+type probe interface {
+	name() string
+	timeout() time.Duration
+	check(ctx context.Context, address string) error
+}
+
+type probeSet struct {
+	defaults    []probe
+	deviceTypes map[string][]probe
+}
+
+func newProbeSet(cfg ProbesConfig) (probeSet, error) {
+	set := probeSet{
+		deviceTypes: make(map[string][]probe, len(cfg.DeviceTypes)),
+	}
+
+	var err error
+	set.defaults, err = newProbes(cfg.Default)
+	if err != nil {
+		return probeSet{}, fmt.Errorf("default: %w", err)
+	}
+
+	for deviceType, probesCfg := range cfg.DeviceTypes {
+		set.deviceTypes[deviceType], err = newProbes(probesCfg)
+		if err != nil {
+			return probeSet{}, fmt.Errorf("%s: %w", deviceType, err)
+		}
+	}
+
+	return set, nil
+}
+
+func (s probeSet) forDeviceType(deviceType string) []probe {
+	if probes, ok := s.deviceTypes[deviceType]; ok {
+		return probes
+	}
+	return s.defaults
+}
+
+func newProbes(cfgs []ProbeConfig) ([]probe, error) {
+	probes := make([]probe, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		p, err := newProbe(cfg)
+		if err != nil {
+			return nil, err
+		}
+		probes = append(probes, p)
+	}
+
+	return probes, nil
+}
+
+func newProbe(cfg ProbeConfig) (probe, error) {
+	timeout := time.Duration(cfg.Timeout)
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+
+	switch cfg.Type {
+	case probeTCP:
+		if cfg.Port <= 0 {
+			return nil, errors.New("tcp probe requires a port")
+		}
+		return &tcpProbe{port: cfg.Port, probeTimeout: timeout}, nil
+
+	case probeHTTP, probeHTTPS:
+		var bodyMatch *regexp.Regexp
+		if cfg.BodyMatch != "" {
+			var err error
+			bodyMatch, err = regexp.Compile(cfg.BodyMatch)
+			if err != nil {
+				return nil, fmt.Errorf("regexp.Compile: %w", err)
+			}
+		}
+
+		expectedStatus := cfg.ExpectedStatus
+		if expectedStatus == 0 {
+			expectedStatus = http.StatusOK
+		}
+
+		return &httpProbe{
+			scheme:         cfg.Type,
+			port:           cfg.Port,
+			path:           cfg.Path,
+			expectedStatus: expectedStatus,
+			bodyMatch:      bodyMatch,
+			probeTimeout:   timeout,
+			client: &http.Client{
+				Transport: &http.Transport{
+					TLSClientConfig: &tls.Config{
+						InsecureSkipVerify: cfg.InsecureSkipVerify, //nolint:gosec
+					},
+					DisableKeepAlives: true,
+				},
+				CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			},
+		}, nil
+
+	case probeDNS:
+		port := cfg.Port
+		if port <= 0 {
+			port = defaultDNSPort
+		}
+		if cfg.Query == "" {
+			return nil, errors.New("dns probe requires a query")
+		}
+		return &dnsProbe{port: port, query: cfg.Query, probeTimeout: timeout}, nil
+	}
+
+	return nil, fmt.Errorf("unknown probe type %q", cfg.Type)
+}
+
+type tcpProbe struct {
+	port         int
+	probeTimeout time.Duration
+}
+
+func (p *tcpProbe) name() string {
+	return probeTCP + ":" + strconv.Itoa(p.port)
+}
+
+func (p *tcpProbe) timeout() time.Duration {
+	return p.probeTimeout
+}
+
+func (p *tcpProbe) check(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, probeTCP, net.JoinHostPort(address, strconv.Itoa(p.port)))
+	if err != nil {
+		return fmt.Errorf("dialer.DialContext: %w", err)
+	}
+
+	return conn.Close() //nolint:wrapcheck
+}
+
+type httpProbe struct {
+	scheme         string
+	port           int
+	path           string
+	expectedStatus int
+	bodyMatch      *regexp.Regexp
+	probeTimeout   time.Duration
+	client         *http.Client
+}
+
+func (p *httpProbe) name() string {
+	return p.scheme + ":" + p.path
+}
+
+func (p *httpProbe) timeout() time.Duration {
+	return p.probeTimeout
+}
+
+func (p *httpProbe) check(ctx context.Context, address string) error {
+	host := address
+	if p.port > 0 {
+		host = net.JoinHostPort(address, strconv.Itoa(p.port))
+	} else if net.ParseIP(address).To4() == nil {
+		host = "[" + address + "]"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.scheme+"://"+host+p.path, nil)
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("client.Do: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != p.expectedStatus {
+		return fmt.Errorf("status is %d, expected %d", resp.StatusCode, p.expectedStatus)
+	}
+
+	if p.bodyMatch == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyMatchSize))
+	if err != nil {
+		return fmt.Errorf("io.ReadAll: %w", err)
+	}
+
+	if !p.bodyMatch.Match(body) {
+		return fmt.Errorf("body does not match %q", p.bodyMatch.String())
+	}
+
+	return nil
+}
+
+type dnsProbe struct {
+	port         int
+	query        string
+	probeTimeout time.Duration
+}
+
+func (p *dnsProbe) name() string {
+	return probeDNS + ":" + p.query
+}
+
+func (p *dnsProbe) timeout() time.Duration {
+	return p.probeTimeout
+}
+
+func (p *dnsProbe) check(ctx context.Context, address string) error {
+	server := net.JoinHostPort(address, strconv.Itoa(p.port))
+	resolver := &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network string, _ string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+
+	addrs, err := resolver.LookupHost(ctx, p.query)
+	if err != nil {
+		return fmt.Errorf("resolver.LookupHost: %w", err)
+	}
+
+	if len(addrs) == 0 {
+		return fmt.Errorf("no addresses for %q", p.query)
+	}
+
+	return nil
+}
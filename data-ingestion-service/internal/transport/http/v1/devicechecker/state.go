@@ -0,0 +1,136 @@
+package devicechecker
+
+import (
+	"sync"
+	"time"
+
+	"data-ingestion-service/internal/models"
+)
+
+type deviceState string
+
+const (
+	stateUnknown  deviceState = "UNKNOWN"
+	stateUp       deviceState = "UP"
+	stateDown     deviceState = "DOWN"
+	stateDegraded deviceState = "DEGRADED"
+
+	defaultFailureThreshold  = 3
+	defaultRecoveryThreshold = 2
+)
+
+var deviceStates = []deviceState{stateUnknown, stateUp, stateDown, stateDegraded}
+
+type reachability struct {
+	state deviceState
+	since time.Time
+
+	// Consecutive observations of a state other than the current one.
+	candidate deviceState
+	streak    int
+}
+
+type transition struct {
+	from       deviceState
+	to         deviceState
+	inPrevious time.Duration
+}
+
+// stateTracker moves a device to DOWN or DEGRADED after failureThreshold
+// consecutive observations of that state and back to UP after
+// recoveryThreshold consecutive successful rounds.
+type stateTracker struct {
+	mu      sync.Mutex
+	devices map[int32]*reachability
+
+	failureThreshold  int
+	recoveryThreshold int
+}
+
+func newStateTracker(failureThreshold int, recoveryThreshold int) *stateTracker {
+	if failureThreshold <= 0 {
+		failureThreshold = defaultFailureThreshold
+	}
+	if recoveryThreshold <= 0 {
+		recoveryThreshold = defaultRecoveryThreshold
+	}
+
+	return &stateTracker{
+		devices:           make(map[int32]*reachability),
+		failureThreshold:  failureThreshold,
+		recoveryThreshold: recoveryThreshold,
+	}
+}
+
+func (t *stateTracker) observe(deviceID int32, observed deviceState, now time.Time) (transition, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	device, ok := t.devices[deviceID]
+	if !ok {
+		device = &reachability{state: stateUnknown, since: now}
+		t.devices[deviceID] = device
+	}
+
+	if observed == device.state {
+		device.candidate = ""
+		device.streak = 0
+		return transition{}, false
+	}
+
+	if observed != device.candidate {
+		device.candidate = observed
+		device.streak = 0
+	}
+	device.streak++
+
+	threshold := t.failureThreshold
+	if observed == stateUp {
+		threshold = t.recoveryThreshold
+	}
+	if device.streak < threshold {
+		return transition{}, false
+	}
+
+	changed := transition{
+		from:       device.state,
+		to:         observed,
+		inPrevious: now.Sub(device.since),
+	}
+
+	device.state = observed
+	device.since = now
+	device.candidate = ""
+	device.streak = 0
+
+	return changed, true
+}
+
+// retain forgets devices that were deleted since the last round.
+func (t *stateTracker) retain(devices []models.Device) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	known := make(map[int32]struct{}, len(devices))
+	for _, device := range devices {
+		known[device.ID] = struct{}{}
+	}
+
+	for deviceID := range t.devices {
+		if _, ok := known[deviceID]; !ok {
+			delete(t.devices, deviceID)
+		}
+	}
+}
+
+func (t *stateTracker) counts() map[deviceState]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	counts := make(map[deviceState]int, len(deviceStates))
+	for _, device := range t.devices {
+		counts[device.state]++
+	}
+
+	return counts
+}
@@ -14,31 +14,62 @@ type Handler struct {
 	messageHandlers *natslistener.NatsListeners
 	devicesService  services.DeviceService
 
-	deviceCheckPeriod int
-	log               *zap.Logger
+	deviceCheckPeriod  int
+	deviceCheckWorkers int
+	deviceCheckProbes  devicechecker.ProbesConfig
+
+	deviceCheckFailureThreshold  int
+	deviceCheckRecoveryThreshold int
+
+	requireDeviceKey bool
+	rateLimiter      *services.RateLimiter
+
+	log *zap.Logger
 }
 
 type Config struct {
 	MessageHandler *natslistener.NatsListeners
 	DevicesService services.DeviceService
 
-	DeviceCheckPeriod int
-	Log               *zap.Logger
+	DeviceCheckPeriod  int
+	DeviceCheckWorkers int
+	DeviceCheckProbes  devicechecker.ProbesConfig
+
+	DeviceCheckFailureThreshold  int
+	DeviceCheckRecoveryThreshold int
+
+	RequireDeviceKey bool
+	RateLimiter      *services.RateLimiter
+
+	Log *zap.Logger
 }
 
 func NewHandler(cfg Config) *Handler {
 	return &Handler{
-		messageHandlers:   cfg.MessageHandler,
-		devicesService:    cfg.DevicesService,
-		deviceCheckPeriod: cfg.DeviceCheckPeriod,
-		log:               cfg.Log,
+		messageHandlers:    cfg.MessageHandler,
+		devicesService:     cfg.DevicesService,
+		deviceCheckPeriod:  cfg.DeviceCheckPeriod,
+		deviceCheckWorkers: cfg.DeviceCheckWorkers,
+		deviceCheckProbes:  cfg.DeviceCheckProbes,
+
+		deviceCheckFailureThreshold:  cfg.DeviceCheckFailureThreshold,
+		deviceCheckRecoveryThreshold: cfg.DeviceCheckRecoveryThreshold,
+
+		requireDeviceKey: cfg.RequireDeviceKey,
+		rateLimiter:      cfg.RateLimiter,
+
+		log: cfg.Log,
 	}
 }
 
 func (h *Handler) InitRouter(routeV1 fiber.Router) {
 	messages.NewMessagesHandler(
 		&messages.Config{
-			NatsHandlers: h.messageHandlers,
+			NatsHandlers:     h.messageHandlers,
+			DevicesService:   h.devicesService,
+			RequireDeviceKey: h.requireDeviceKey,
+			RateLimiter:      h.rateLimiter,
+			Log:              h.log,
 		},
 	).InitMessagesRoutes(routeV1)
 
@@ -49,6 +80,10 @@ func (h *Handler) InitRouter(routeV1 fiber.Router) {
 
 			Logger:            h.log,
 			DeviceCheckPeriod: h.deviceCheckPeriod,
+			Workers:           h.deviceCheckWorkers,
+			Probes:            h.deviceCheckProbes,
+			FailureThreshold:  h.deviceCheckFailureThreshold,
+			RecoveryThreshold: h.deviceCheckRecoveryThreshold,
 		},
 	).Start()
 }
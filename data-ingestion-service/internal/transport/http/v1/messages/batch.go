@@ -0,0 +1,311 @@
+package messages
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"data-ingestion-service/internal/models"
+	"data-ingestion-service/internal/transport/natslistener"
+
+	"github.com/gofiber/fiber/v3"
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	// Valid items are published in chunks so a long NDJSON stream is not
+	// buffered whole before anything reaches JetStream.
+	batchChunkSize = 500
+	maxBatchSize   = 100000
+	maxLineSize    = 1024 * 1024
+
+	statusAccepted = "accepted"
+	statusRejected = "rejected"
+	// statusQuarantined is an item of an unknown source, it is stored once
+	// the source is registered as a device.
+	statusQuarantined = "quarantined"
+	// statusTimeout is an item whose publish was not acknowledged in time,
+	// it may be resent after RetryAfter.
+	statusTimeout = "timeout"
+
+	ackTimeoutRetryAfter = time.Second
+)
+
+var errBatchTooLarge = fmt.Errorf("batch is limited to %d items", maxBatchSize)
+
+type (
+	batchItemResult struct {
+		Index  int    `json:"index"`
+		Status string `json:"status"`
+		Reason string `json:"reason,omitempty"`
+		// RetryAfter is set in seconds when the item was rate limited or
+		// timed out.
+		RetryAfter int `json:"retry_after,omitempty"`
+	}
+
+	sendBatchResp struct {
+		Accepted    int               `json:"accepted"`
+		Rejected    int               `json:"rejected"`
+		TimedOut    int               `json:"timed_out,omitempty"`
+		Quarantined int               `json:"quarantined,omitempty"`
+		Results     []batchItemResult `json:"results"`
+	}
+)
+
+type batch struct {
+	handler   *messagesHandler
+	ctx       fiber.Ctx
+	validator fiber.StructValidator
+
+	pending        []models.Message
+	pendingIndexes []int
+
+	resp sendBatchResp
+
+	// Longest wait of the items refused by the rate limiter.
+	retryAfter time.Duration
+}
+
+// sendBatch accepts either a JSON array of send_msg bodies or NDJSON (one
+// body per line). The format is detected by the first non-space byte.
+func (h *messagesHandler) sendBatch(ctx fiber.Ctx) error {
+	var body io.Reader = ctx.Request().BodyStream()
+	if body == nil {
+		body = bytes.NewReader(ctx.Body())
+	}
+
+	reader := bufio.NewReader(body)
+	first, err := peekNonSpace(reader)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			fmt.Errorf("peekNonSpace: %w", err).Error(),
+		)
+	}
+
+	b := &batch{
+		handler:   h,
+		ctx:       ctx,
+		validator: ctx.App().Config().StructValidator,
+		resp: sendBatchResp{
+			Results: make([]batchItemResult, 0),
+		},
+	}
+
+	if first == '[' {
+		err = b.readArray(reader)
+	} else {
+		err = b.readNDJSON(reader)
+	}
+	b.flush()
+
+	sort.Slice(b.resp.Results, func(i, j int) bool {
+		return b.resp.Results[i].Index < b.resp.Results[j].Index
+	})
+
+	if err != nil && len(b.resp.Results) == 0 {
+		return fiber.NewError(
+			fiber.StatusUnprocessableEntity,
+			err.Error(),
+		)
+	}
+	if err != nil {
+		b.reject(len(b.resp.Results), err)
+	}
+
+	jsonResponse, err := jsoniter.Marshal(b.resp)
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("json.Marshal: %w", err).Error(),
+		)
+	}
+
+	h.metrics.Inc()
+
+	// The batch is only refused as a whole when the rate limiter let nothing
+	// through, otherwise the per item results tell what to resend.
+	status := fiber.StatusOK
+	if b.retryAfter > 0 {
+		setRetryAfter(ctx, b.retryAfter)
+		if b.resp.Accepted == 0 {
+			status = fiber.StatusTooManyRequests
+		}
+	}
+
+	if err = ctx.Status(status).Send(jsonResponse); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+func (b *batch) readArray(reader io.Reader) error {
+	decoder := json.NewDecoder(reader)
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("decoder.Token: %w", err)
+	}
+
+	for index := 0; decoder.More(); index++ {
+		if index >= maxBatchSize {
+			return errBatchTooLarge
+		}
+
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			return fmt.Errorf("decoder.Decode: %w", err)
+		}
+
+		b.add(index, raw)
+	}
+
+	return nil
+}
+
+func (b *batch) readNDJSON(reader io.Reader) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	index := 0
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		if index >= maxBatchSize {
+			return errBatchTooLarge
+		}
+
+		b.add(index, line)
+		index++
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("scanner.Scan: %w", err)
+	}
+
+	return nil
+}
+
+func (b *batch) add(index int, raw []byte) {
+	item := sendMsgReq{
+		Message:     "",
+		MessageType: "",
+		Component:   "",
+		Address:     "",
+	}
+
+	if err := jsoniter.Unmarshal(raw, &item); err != nil {
+		b.reject(index, fmt.Errorf("json.Unmarshal: %w", err))
+		return
+	}
+
+	if err := b.validator.Validate(&item); err != nil {
+		b.reject(index, err)
+		return
+	}
+
+	if err := b.handler.checkAddress(b.ctx, item.Address); err != nil {
+		b.reject(index, err)
+		return
+	}
+
+	if err := b.handler.rateLimit(item.Address); err != nil {
+		b.reject(index, err)
+
+		var limited *rateLimitedError
+		if errors.As(err, &limited) {
+			b.retryAfter = max(b.retryAfter, limited.retryAfter)
+			b.resp.Results[len(b.resp.Results)-1].RetryAfter = retryAfterSeconds(limited.retryAfter)
+		}
+		return
+	}
+
+	b.pending = append(b.pending, item.toMessage(b.ctx.IP()))
+	b.pendingIndexes = append(b.pendingIndexes, index)
+
+	if len(b.pending) >= batchChunkSize {
+		b.flush()
+	}
+}
+
+func (b *batch) flush() {
+	if len(b.pending) == 0 {
+		return
+	}
+
+	errs := b.handler.natsHandlers.PublishSaveMessagesAsync(b.pending)
+	for i, err := range errs {
+		if errors.Is(err, natslistener.ErrAckTimeout) {
+			b.timeout(b.pendingIndexes[i], err)
+			continue
+		}
+		if errors.Is(err, natslistener.ErrQuarantined) {
+			b.resp.Quarantined++
+			b.resp.Results = append(b.resp.Results, batchItemResult{
+				Index:  b.pendingIndexes[i],
+				Status: statusQuarantined,
+				Reason: "",
+			})
+			continue
+		}
+		if err != nil {
+			b.reject(b.pendingIndexes[i], err)
+			continue
+		}
+
+		b.resp.Accepted++
+		b.resp.Results = append(b.resp.Results, batchItemResult{
+			Index:  b.pendingIndexes[i],
+			Status: statusAccepted,
+			Reason: "",
+		})
+	}
+
+	b.pending = b.pending[:0]
+	b.pendingIndexes = b.pendingIndexes[:0]
+}
+
+func (b *batch) reject(index int, err error) {
+	b.resp.Rejected++
+	b.resp.Results = append(b.resp.Results, batchItemResult{
+		Index:  index,
+		Status: statusRejected,
+		Reason: err.Error(),
+	})
+}
+
+func (b *batch) timeout(index int, err error) {
+	b.resp.TimedOut++
+	b.resp.Results = append(b.resp.Results, batchItemResult{
+		Index:      index,
+		Status:     statusTimeout,
+		Reason:     err.Error(),
+		RetryAfter: retryAfterSeconds(ackTimeoutRetryAfter),
+	})
+}
+
+func peekNonSpace(reader *bufio.Reader) (byte, error) {
+	for {
+		c, err := reader.ReadByte()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return 0, errors.New("empty body")
+			}
+			return 0, fmt.Errorf("reader.ReadByte: %w", err)
+		}
+
+		if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+			return c, reader.UnreadByte() //nolint:wrapcheck
+		}
+	}
+}
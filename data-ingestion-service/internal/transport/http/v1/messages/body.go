@@ -0,0 +1,40 @@
+package messages
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+// bufferBodyMW reads the body of the routes that need it whole. The server
+// streams request bodies for send_batch, fasthttp can not do that per route,
+// and a streamed body is not held to BodyLimit, so the limit is enforced here.
+func bufferBodyMW(ctx fiber.Ctx) error {
+	stream := ctx.Request().BodyStream()
+	if stream == nil {
+		return ctx.Next() //nolint:wrapcheck
+	}
+
+	limit := ctx.App().Config().BodyLimit
+	body, err := io.ReadAll(io.LimitReader(stream, int64(limit)+1))
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusBadRequest,
+			fmt.Errorf("io.ReadAll: %w", err).Error(),
+		)
+	}
+	if len(body) > limit {
+		// The rest of the body is not read, it must not be taken for the
+		// next request on the connection.
+		ctx.Response().SetConnectionClose()
+		return fiber.NewError(
+			fiber.StatusRequestEntityTooLarge,
+			fmt.Sprintf("body is limited to %d bytes", limit),
+		)
+	}
+
+	ctx.Request().SetBody(body)
+
+	return ctx.Next() //nolint:wrapcheck
+}
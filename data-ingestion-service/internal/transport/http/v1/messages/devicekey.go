@@ -0,0 +1,96 @@
+package messages
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	deviceKeyHeader = "X-Device-Key"
+	localDeviceID   = "localDeviceID"
+
+	rejectMissingKey      = "missing_key"
+	rejectInvalidKey      = "invalid_key"
+	rejectAddressMismatch = "address_mismatch"
+)
+
+var (
+	errAddressMismatch = errors.New("address does not belong to the device of " + deviceKeyHeader)
+	errKeyRequired     = errors.New("device was issued a key, " + deviceKeyHeader + " is empty")
+)
+
+func newKeyRejections() *prometheus.CounterVec {
+	keyRejections := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "ingress_device_key_rejected_total",
+			Help: "Messages rejected by the device key check",
+		},
+		[]string{"reason"},
+	)
+
+	prometheus.MustRegister(keyRejections)
+
+	return keyRejections
+}
+
+// deviceKeyMW resolves the X-Device-Key header to a device. Without
+// requireDeviceKey a request may omit the header, unless it writes as a
+// device that was issued a key, but a wrong key is always rejected.
+func (h *messagesHandler) deviceKeyMW(ctx fiber.Ctx) error {
+	apiKey := ctx.Get(deviceKeyHeader)
+	if apiKey == "" {
+		if !h.requireDeviceKey {
+			return ctx.Next() //nolint:wrapcheck
+		}
+
+		h.keyRejections.WithLabelValues(rejectMissingKey).Inc()
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New(deviceKeyHeader+" is empty").Error(),
+		)
+	}
+
+	deviceID, ok := h.devicesService.GetDeviceIDByKey(apiKey)
+	if !ok {
+		h.keyRejections.WithLabelValues(rejectInvalidKey).Inc()
+		return fiber.NewError(
+			fiber.StatusUnauthorized,
+			errors.New(deviceKeyHeader+" is invalid").Error(),
+		)
+	}
+
+	ctx.Locals(localDeviceID, deviceID)
+
+	return ctx.Next() //nolint:wrapcheck
+}
+
+// checkAddress makes sure the claimed address belongs to the authenticated
+// device, so a key of one device can not be used to write as another.
+func (h *messagesHandler) checkAddress(ctx fiber.Ctx, address string) error {
+	deviceID, ok := ctx.Locals(localDeviceID).(int32)
+	return h.checkDeviceAddress(deviceID, ok, address)
+}
+
+// checkDeviceAddress is checkAddress for callers that outlive the request
+// context, authenticated is false when the request carried no key. Without a
+// key only devices that were not issued one may be written as.
+func (h *messagesHandler) checkDeviceAddress(deviceID int32, authenticated bool, address string) error {
+	if !authenticated {
+		claimedID, ok := h.devicesService.GetDeviceIDByIp(address)
+		if ok && h.devicesService.HasKey(claimedID) {
+			h.keyRejections.WithLabelValues(rejectMissingKey).Inc()
+			return errKeyRequired
+		}
+		return nil
+	}
+
+	claimedID, ok := h.devicesService.GetDeviceIDByIp(address)
+	if !ok || claimedID != deviceID {
+		h.keyRejections.WithLabelValues(rejectAddressMismatch).Inc()
+		return errAddressMismatch
+	}
+
+	return nil
+}
@@ -2,11 +2,15 @@ package messages
 
 import (
 	"data-ingestion-service/internal/models"
+	"data-ingestion-service/internal/services"
 	"data-ingestion-service/internal/transport/natslistener"
+	"errors"
 	"fmt"
+	"time"
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
 
 	"github.com/gofiber/fiber/v3"
 )
@@ -16,12 +20,30 @@ const (
 )
 
 type messagesHandler struct {
-	natsHandlers *natslistener.NatsListeners
-	metrics      prometheus.Counter
+	natsHandlers   *natslistener.NatsListeners
+	devicesService services.DeviceService
+	metrics        prometheus.Counter
+
+	requireDeviceKey bool
+	keyRejections    *prometheus.CounterVec
+
+	rateLimiter *services.RateLimiter
+
+	streamConnections prometheus.Gauge
+
+	log *zap.Logger
 }
 
 type Config struct {
-	NatsHandlers *natslistener.NatsListeners
+	NatsHandlers   *natslistener.NatsListeners
+	DevicesService services.DeviceService
+
+	// RequireDeviceKey rejects messages sent without X-Device-Key.
+	RequireDeviceKey bool
+	// RateLimiter answers 429 to devices over their limit, nil disables it.
+	RateLimiter *services.RateLimiter
+
+	Log *zap.Logger
 }
 
 func NewMessagesHandler(cfg *Config) *messagesHandler {
@@ -35,25 +57,58 @@ func NewMessagesHandler(cfg *Config) *messagesHandler {
 	prometheus.MustRegister(ingressRequests)
 
 	return &messagesHandler{
-		natsHandlers: cfg.NatsHandlers,
-		metrics:      ingressRequests,
+		natsHandlers:     cfg.NatsHandlers,
+		devicesService:   cfg.DevicesService,
+		metrics:          ingressRequests,
+		requireDeviceKey: cfg.RequireDeviceKey,
+		keyRejections:    newKeyRejections(),
+		rateLimiter:      cfg.RateLimiter,
+
+		streamConnections: newStreamConnections(),
+
+		log: cfg.Log,
 	}
 }
 
 func (h *messagesHandler) InitMessagesRoutes(api fiber.Router) {
-	servicesRoute := api.Group("/messages")
-	servicesRoute.Post("/send_msg", h.sendMsg)
+	servicesRoute := api.Group("/messages", h.deviceKeyMW)
+	servicesRoute.Post("/send_msg", bufferBodyMW, h.sendMsg)
+	servicesRoute.Post("/send_batch", h.sendBatch)
+	servicesRoute.Post("/otlp/v1/logs", bufferBodyMW, h.exportLogs)
+	servicesRoute.Get("/stream", h.stream)
 }
 
 type (
 	sendMsgReq struct {
-		Message     string `form:"message" 		json:"message" 		validate:"required" 	xml:"message"`
-		MessageType string `form:"message_type" json:"message_type" validate:"required" 	xml:"message_type"`
-		Component   string `form:"component" 	json:"component" 	validate:"required" 	xml:"component"`
-		Address     string `form:"address" 		json:"address" 		validate:"required,ip" 	xml:"address"`
+		Message     string `form:"message"      json:"message"      validate:"required"    xml:"message"`
+		MessageType string `form:"message_type" json:"message_type" validate:"required"    xml:"message_type"`
+		Component   string `form:"component"    json:"component"    validate:"required"    xml:"component"`
+		Address     string `form:"address"      json:"address"      validate:"required,ip" xml:"address"`
+
+		EventTime  *time.Time        `form:"event_time" json:"event_time" validate:"omitempty"        xml:"event_time"`
+		Attributes map[string]string `form:"attributes" json:"attributes" validate:"omitempty,max=64" xml:"attributes"`
+		// MessageID lets a sender retry without storing the message twice.
+		MessageID string `form:"message_id" json:"message_id" validate:"omitempty,max=128" xml:"message_id"`
 	}
 )
 
+func (r *sendMsgReq) toMessage(peerIP string) models.Message {
+	message := models.Message{
+		Message:     r.Message,
+		MessageType: r.MessageType,
+		Component:   r.Component,
+		DeviceIP:    r.Address,
+		PeerIP:      peerIP,
+		Attributes:  r.Attributes,
+		MessageID:   r.MessageID,
+	}
+	if r.EventTime != nil {
+		message.EventTime = *r.EventTime
+	}
+
+	return message
+}
+
 func (h *messagesHandler) sendMsg(ctx fiber.Ctx) error {
 	body := sendMsgReq{
 		Message:     "",
@@ -69,14 +124,29 @@ func (h *messagesHandler) sendMsg(ctx fiber.Ctx) error {
 		)
 	}
 
-	err := h.natsHandlers.PublishSaveMessage(
-		models.Message{
-			Message:     body.Message,
-			MessageType: body.MessageType,
-			Component:   body.Component,
-			DeviceIP:    body.Address,
-		},
-	)
+	if err := h.checkAddress(ctx, body.Address); err != nil {
+		status := fiber.StatusForbidden
+		if errors.Is(err, errKeyRequired) {
+			status = fiber.StatusUnauthorized
+		}
+		return fiber.NewError(
+			status,
+			fmt.Errorf("h.checkAddress: %w", err).Error(),
+		)
+	}
+
+	if err := h.rateLimit(body.Address); err != nil {
+		var limited *rateLimitedError
+		if errors.As(err, &limited) {
+			setRetryAfter(ctx, limited.retryAfter)
+		}
+		return fiber.NewError(
+			fiber.StatusTooManyRequests,
+			fmt.Errorf("h.rateLimit: %w", err).Error(),
+		)
+	}
+
+	err := h.natsHandlers.PublishSaveMessage(body.toMessage(ctx.IP()))
 	if err != nil {
 		return fiber.NewError(
 			fiber.StatusInternalServerError,
@@ -0,0 +1,335 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"data-ingestion-service/internal/models"
+	"data-ingestion-service/internal/transport/natslistener"
+
+	"github.com/gofiber/fiber/v3"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	contentTypeProtobuf = "application/x-protobuf"
+	contentTypeJSON     = "application/json"
+
+	attrHostIP      = "host.ip"
+	attrServiceName = "service.name"
+
+	defaultOTLPComponent = "General"
+	defaultOTLPType      = "info"
+)
+
+var (
+	errNoDevice  = errors.New("resource has neither host.ip nor a registered service.name")
+	errEmptyBody = errors.New("log record body is empty")
+)
+
+// OTLP severity numbers come in groups of four: TRACE, DEBUG, INFO, WARN,
+// ERROR and FATAL. They are mapped to the names used by the syslog receiver.
+var otlpSeverityNames = [...]string{
+	"debug",
+	"debug",
+	"info",
+	"warning",
+	"error",
+	"critical",
+}
+
+// exportLogs implements the OTLP/HTTP logs endpoint, exporters are pointed at
+// .../v1/messages/otlp and append /v1/logs themselves. The response is encoded
+// the same way as the request.
+func (h *messagesHandler) exportLogs(ctx fiber.Ctx) error {
+	contentType := strings.TrimSpace(strings.Split(ctx.Get(fiber.HeaderContentType), ";")[0])
+
+	var request collogspb.ExportLogsServiceRequest
+	switch contentType {
+	case contentTypeProtobuf:
+		if err := proto.Unmarshal(ctx.Body(), &request); err != nil {
+			return fiber.NewError(
+				fiber.StatusBadRequest,
+				fmt.Errorf("proto.Unmarshal: %w", err).Error(),
+			)
+		}
+
+	case contentTypeJSON:
+		if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(ctx.Body(), &request); err != nil {
+			return fiber.NewError(
+				fiber.StatusBadRequest,
+				fmt.Errorf("protojson.Unmarshal: %w", err).Error(),
+			)
+		}
+
+	default:
+		return fiber.NewError(
+			fiber.StatusUnsupportedMediaType,
+			fmt.Sprintf("content type %q is not supported", contentType),
+		)
+	}
+
+	var (
+		rejected     int64
+		errorMessage string
+		retryAfter   time.Duration
+	)
+	reject := func(err error) {
+		rejected++
+		if errorMessage == "" {
+			errorMessage = err.Error()
+		}
+	}
+
+	messages := make([]models.Message, 0)
+	for _, resourceLogs := range request.GetResourceLogs() {
+		attributes := resourceLogs.GetResource().GetAttributes()
+
+		address, err := h.resolveAddress(attributes)
+		if err == nil {
+			err = h.checkAddress(ctx, address)
+		}
+
+		serviceName := stringAttribute(attributes, attrServiceName)
+
+		for _, scopeLogs := range resourceLogs.GetScopeLogs() {
+			component := scopeLogs.GetScope().GetName()
+			if component == "" {
+				component = serviceName
+			}
+			if component == "" {
+				component = defaultOTLPComponent
+			}
+
+			for _, record := range scopeLogs.GetLogRecords() {
+				if err != nil {
+					reject(err)
+					continue
+				}
+
+				body := anyValueString(record.GetBody())
+				if body == "" {
+					reject(errEmptyBody)
+					continue
+				}
+
+				if limitErr := h.rateLimit(address); limitErr != nil {
+					var limited *rateLimitedError
+					if errors.As(limitErr, &limited) {
+						retryAfter = max(retryAfter, limited.retryAfter)
+					}
+					reject(limitErr)
+					continue
+				}
+
+				messages = append(messages, models.Message{
+					Message:     body,
+					MessageType: severityName(record),
+					Component:   component,
+					DeviceIP:    address,
+					PeerIP:      ctx.IP(),
+					EventTime:   eventTime(record),
+					Attributes:  attributesMap(record.GetAttributes()),
+				})
+			}
+		}
+	}
+
+	for start := 0; start < len(messages); start += batchChunkSize {
+		end := min(start+batchChunkSize, len(messages))
+		for _, err := range h.natsHandlers.PublishSaveMessagesAsync(messages[start:end]) {
+			// A quarantined record is held, not rejected.
+			if err != nil && !errors.Is(err, natslistener.ErrQuarantined) {
+				reject(err)
+			}
+		}
+	}
+
+	h.metrics.Inc()
+
+	// Exporters retry the whole request on 429, so it is only used when no
+	// record got through. Partially throttled requests are a partial success.
+	if len(messages) == 0 && retryAfter > 0 {
+		setRetryAfter(ctx, retryAfter)
+		return fiber.NewError(
+			fiber.StatusTooManyRequests,
+			errorMessage,
+		)
+	}
+
+	resp := &collogspb.ExportLogsServiceResponse{}
+	if rejected > 0 {
+		resp.PartialSuccess = &collogspb.ExportLogsPartialSuccess{
+			RejectedLogRecords: rejected,
+			ErrorMessage:       errorMessage,
+		}
+	}
+
+	var (
+		binaryResp []byte
+		err        error
+	)
+	if contentType == contentTypeJSON {
+		binaryResp, err = protojson.Marshal(resp)
+	} else {
+		binaryResp, err = proto.Marshal(resp)
+	}
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("marshal response: %w", err).Error(),
+		)
+	}
+
+	ctx.Set(fiber.HeaderContentType, contentType)
+	if err = ctx.Status(fiber.StatusOK).Send(binaryResp); err != nil {
+		return fiber.NewError(
+			fiber.StatusInternalServerError,
+			fmt.Errorf("ctx.Send: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+// resolveAddress prefers a host.ip of a registered device, then the device
+// named after service.name. An unregistered host.ip is still returned, so its
+// records end up in the quarantine.
+func (h *messagesHandler) resolveAddress(attributes []*commonpb.KeyValue) (string, error) {
+	var unregistered string
+	for _, ip := range stringsAttribute(attributes, attrHostIP) {
+		if net.ParseIP(ip) == nil {
+			continue
+		}
+
+		if _, ok := h.devicesService.GetDeviceIDByIp(ip); ok {
+			return ip, nil
+		}
+
+		if unregistered == "" {
+			unregistered = ip
+		}
+	}
+
+	if serviceName := stringAttribute(attributes, attrServiceName); serviceName != "" {
+		if address, ok := h.devicesService.GetDeviceAddressByName(serviceName); ok {
+			return address, nil
+		}
+	}
+
+	if unregistered != "" {
+		return unregistered, nil
+	}
+
+	return "", errNoDevice
+}
+
+func severityName(record *logspb.LogRecord) string {
+	number := int(record.GetSeverityNumber())
+	if number > 0 {
+		return otlpSeverityNames[min((number-1)/4, len(otlpSeverityNames)-1)]
+	}
+
+	switch text := strings.ToLower(record.GetSeverityText()); {
+	case strings.HasPrefix(text, "warn"):
+		return "warning"
+	case strings.HasPrefix(text, "fatal"), strings.HasPrefix(text, "crit"):
+		return "critical"
+	case strings.HasPrefix(text, "err"):
+		return "error"
+	case strings.HasPrefix(text, "debug"), strings.HasPrefix(text, "trace"):
+		return "debug"
+	}
+
+	return defaultOTLPType
+}
+
+// eventTime falls back to the time the record was observed by the collector.
+func eventTime(record *logspb.LogRecord) time.Time {
+	switch {
+	case record.GetTimeUnixNano() > 0:
+		return time.Unix(0, int64(record.GetTimeUnixNano()))
+	case record.GetObservedTimeUnixNano() > 0:
+		return time.Unix(0, int64(record.GetObservedTimeUnixNano()))
+	}
+
+	return time.Time{}
+}
+
+func attributesMap(attributes []*commonpb.KeyValue) map[string]string {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	res := make(map[string]string, len(attributes))
+	for _, attribute := range attributes {
+		res[attribute.GetKey()] = anyValueString(attribute.GetValue())
+	}
+
+	return res
+}
+
+func stringAttribute(attributes []*commonpb.KeyValue, key string) string {
+	for _, attribute := range attributes {
+		if attribute.GetKey() == key {
+			return attribute.GetValue().GetStringValue()
+		}
+	}
+
+	return ""
+}
+
+// stringsAttribute accepts both a string and an array of strings, host.ip is
+// an array in the semantic conventions but often sent as a single value.
+func stringsAttribute(attributes []*commonpb.KeyValue, key string) []string {
+	for _, attribute := range attributes {
+		if attribute.GetKey() != key {
+			continue
+		}
+
+		if values := attribute.GetValue().GetArrayValue().GetValues(); len(values) > 0 {
+			res := make([]string, 0, len(values))
+			for _, value := range values {
+				res = append(res, value.GetStringValue())
+			}
+			return res
+		}
+
+		return []string{attribute.GetValue().GetStringValue()}
+	}
+
+	return nil
+}
+
+func anyValueString(value *commonpb.AnyValue) string {
+	switch v := value.GetValue().(type) {
+	case nil:
+		return ""
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(v.IntValue, 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+	case *commonpb.AnyValue_BytesValue:
+		return string(v.BytesValue)
+	}
+
+	// Maps and arrays are kept as JSON.
+	data, err := protojson.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(data)
+}
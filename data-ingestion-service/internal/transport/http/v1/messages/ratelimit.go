@@ -0,0 +1,41 @@
+package messages
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"data-ingestion-service/internal/services"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type rateLimitedError struct {
+	retryAfter time.Duration
+}
+
+func (e *rateLimitedError) Error() string {
+	return fmt.Sprintf("rate limit exceeded, retry after %s", e.retryAfter)
+}
+
+// rateLimit returns a *rateLimitedError if the device at the address is over
+// its limit, HTTP senders are expected to retry so the message is throttled.
+func (h *messagesHandler) rateLimit(address string) error {
+	ok, retryAfter := h.rateLimiter.Allow(address, services.RateLimitThrottled)
+	if ok {
+		return nil
+	}
+
+	return &rateLimitedError{retryAfter: retryAfter}
+}
+
+func setRetryAfter(ctx fiber.Ctx, retryAfter time.Duration) {
+	ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(retryAfterSeconds(retryAfter)))
+}
+
+// retryAfterSeconds rounds up to whole seconds, the only form of Retry-After
+// most clients understand.
+func retryAfterSeconds(retryAfter time.Duration) int {
+	return max(int(math.Ceil(retryAfter.Seconds())), 1)
+}
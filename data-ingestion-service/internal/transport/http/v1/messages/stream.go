@@ -0,0 +1,231 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"data-ingestion-service/internal/transport/natslistener"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v3"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	streamWriteWait  = 10 * time.Second
+	streamPongWait   = 60 * time.Second
+	streamPingPeriod = streamPongWait * 9 / 10
+)
+
+var errBinaryFrame = errors.New("only text frames are accepted")
+
+type (
+	// streamFrame is a send_msg body, ID is echoed back in the ack so the
+	// sender can match them. Frames without an ID are acked by sequence number.
+	streamFrame struct {
+		ID string `json:"id"`
+		sendMsgReq
+	}
+
+	streamAck struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+		Reason string `json:"reason,omitempty"`
+		// RetryAfter is set in seconds when the frame was rate limited or
+		// timed out.
+		RetryAfter int `json:"retry_after,omitempty"`
+	}
+)
+
+func newStreamConnections() prometheus.Gauge {
+	streamConnections := prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "ingress_stream_connections",
+			Help: "Open WebSocket ingestion sessions",
+		},
+	)
+
+	prometheus.MustRegister(streamConnections)
+
+	return streamConnections
+}
+
+// stream upgrades to a WebSocket for gateways that keep a session open. The
+// device key is checked once by deviceKeyMW, then every text frame is one
+// send_msg body answered with an ack or a nack.
+func (h *messagesHandler) stream(ctx fiber.Ctx) error {
+	if !websocket.FastHTTPIsWebSocketUpgrade(ctx.RequestCtx()) {
+		return fiber.NewError(
+			fiber.StatusUpgradeRequired,
+			errors.New("websocket upgrade expected").Error(),
+		)
+	}
+
+	// Nothing of the fiber context may be used once the connection is hijacked.
+	deviceID, authenticated := ctx.Locals(localDeviceID).(int32)
+	validator := ctx.App().Config().StructValidator
+	remoteAddr := ctx.IP()
+
+	upgrader := websocket.FastHTTPUpgrader{
+		ReadBufferSize:  0,
+		WriteBufferSize: 0,
+	}
+
+	err := upgrader.Upgrade(ctx.RequestCtx(), func(conn *websocket.Conn) {
+		h.streamConnections.Inc()
+		defer h.streamConnections.Dec()
+
+		session := &streamSession{
+			handler:       h,
+			conn:          conn,
+			validator:     validator,
+			deviceID:      deviceID,
+			authenticated: authenticated,
+			remoteAddr:    remoteAddr,
+		}
+		if err := session.run(); err != nil {
+			h.log.Warn("stream session closed", zap.Error(err), zap.String("remote_addr", remoteAddr))
+		}
+	})
+	if err != nil {
+		return fiber.NewError(
+			fiber.StatusBadRequest,
+			fmt.Errorf("upgrader.Upgrade: %w", err).Error(),
+		)
+	}
+
+	return nil
+}
+
+type streamSession struct {
+	handler   *messagesHandler
+	conn      *websocket.Conn
+	validator fiber.StructValidator
+
+	deviceID      int32
+	authenticated bool
+	remoteAddr    string
+
+	seq uint64
+}
+
+func (s *streamSession) run() error {
+	defer s.conn.Close()
+
+	s.conn.SetReadLimit(maxLineSize)
+	if err := s.conn.SetReadDeadline(time.Now().Add(streamPongWait)); err != nil {
+		return fmt.Errorf("s.conn.SetReadDeadline: %w", err)
+	}
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(streamPongWait)) //nolint:wrapcheck
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.ping(done)
+
+	for {
+		messageType, data, err := s.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				return nil
+			}
+			return fmt.Errorf("s.conn.ReadMessage: %w", err)
+		}
+
+		// Any frame proves the peer is alive, not only pongs.
+		if err = s.conn.SetReadDeadline(time.Now().Add(streamPongWait)); err != nil {
+			return fmt.Errorf("s.conn.SetReadDeadline: %w", err)
+		}
+
+		s.seq++
+		ack := s.handle(messageType, data)
+		if ack.ID == "" {
+			ack.ID = strconv.FormatUint(s.seq, 10)
+		}
+
+		if err = s.conn.SetWriteDeadline(time.Now().Add(streamWriteWait)); err != nil {
+			return fmt.Errorf("s.conn.SetWriteDeadline: %w", err)
+		}
+		if err = s.conn.WriteJSON(ack); err != nil {
+			return fmt.Errorf("s.conn.WriteJSON: %w", err)
+		}
+	}
+}
+
+func (s *streamSession) handle(messageType int, data []byte) streamAck {
+	if messageType != websocket.TextMessage {
+		return nack("", errBinaryFrame)
+	}
+
+	frame := streamFrame{}
+	if err := jsoniter.Unmarshal(data, &frame); err != nil {
+		return nack("", fmt.Errorf("json.Unmarshal: %w", err))
+	}
+
+	if err := s.validator.Validate(&frame.sendMsgReq); err != nil {
+		return nack(frame.ID, err)
+	}
+
+	if err := s.handler.checkDeviceAddress(s.deviceID, s.authenticated, frame.Address); err != nil {
+		return nack(frame.ID, err)
+	}
+
+	if err := s.handler.rateLimit(frame.Address); err != nil {
+		ack := nack(frame.ID, err)
+
+		var limited *rateLimitedError
+		if errors.As(err, &limited) {
+			ack.RetryAfter = retryAfterSeconds(limited.retryAfter)
+		}
+
+		return ack
+	}
+
+	if err := s.handler.natsHandlers.PublishSaveMessage(frame.toMessage(s.remoteAddr)); err != nil {
+		ack := nack(frame.ID, fmt.Errorf("h.natsHandlers.PublishSaveMessage: %w", err))
+		if errors.Is(err, natslistener.ErrAckTimeout) {
+			ack.Status = statusTimeout
+			ack.RetryAfter = retryAfterSeconds(ackTimeoutRetryAfter)
+		}
+
+		return ack
+	}
+
+	s.handler.metrics.Inc()
+
+	return streamAck{
+		ID:     frame.ID,
+		Status: statusAccepted,
+	}
+}
+
+// ping runs next to the read loop, WriteControl is safe to call concurrently
+// with the acks written there.
+func (s *streamSession) ping(done <-chan struct{}) {
+	ticker := time.NewTicker(streamPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func nack(id string, err error) streamAck {
+	return streamAck{
+		ID:     id,
+		Status: statusRejected,
+		Reason: err.Error(),
+	}
+}
@@ -0,0 +1,251 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"data-ingestion-service/internal/models"
+	"data-ingestion-service/internal/services"
+	"data-ingestion-service/internal/transport/natslistener"
+
+	pahomqtt "github.com/eclipse/paho.mqtt.golang"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	connectTimeout    = 10 * time.Second
+	disconnectQuiesce = 250 // milliseconds
+
+	defaultMessageType = "info"
+	defaultComponent   = "mqtt"
+
+	// message_id column in data-processing-service is varchar(128).
+	maxMessageIDLen = 128
+
+	singleLevelWildcard = "+"
+)
+
+var (
+	ErrInvalidAddress  = errors.New("unable to resolve device address")
+	ErrAddressMismatch = errors.New("payload address differs from the topic")
+)
+
+type Subscriber struct {
+	client pahomqtt.Client
+	topics []string
+	qos    byte
+
+	natsHandlers *natslistener.NatsListeners
+	rateLimiter  *services.RateLimiter
+
+	received *prometheus.CounterVec
+
+	log *zap.Logger
+}
+
+type Config struct {
+	BrokerURL string
+	ClientID  string
+	Username  string
+	Password  string
+	Topics    []string
+	QoS       byte
+
+	NatsHandlers *natslistener.NatsListeners
+	RateLimiter  *services.RateLimiter
+
+	Log *zap.Logger
+}
+
+func NewSubscriber(cfg Config) *Subscriber {
+	received := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mqtt_messages_total",
+			Help: "Total MQTT messages received",
+		},
+		[]string{"status"},
+	)
+
+	prometheus.MustRegister(received)
+
+	subscriber := &Subscriber{
+		topics:       cfg.Topics,
+		qos:          cfg.QoS,
+		natsHandlers: cfg.NatsHandlers,
+		rateLimiter:  cfg.RateLimiter,
+		received:     received,
+		log:          cfg.Log,
+	}
+
+	if cfg.BrokerURL == "" {
+		return subscriber
+	}
+
+	opts := pahomqtt.NewClientOptions().
+		AddBroker(cfg.BrokerURL).
+		SetClientID(cfg.ClientID).
+		SetUsername(cfg.Username).
+		SetPassword(cfg.Password).
+		SetCleanSession(false).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetOrderMatters(false).
+		SetOnConnectHandler(subscriber.onConnect).
+		SetConnectionLostHandler(func(_ pahomqtt.Client, err error) {
+			subscriber.log.Warn("mqtt connection lost", zap.Error(err))
+		})
+
+	subscriber.client = pahomqtt.NewClient(opts)
+
+	return subscriber
+}
+
+// Run connects to the broker, subscriptions are (re)made in onConnect so
+// they survive reconnects. Does nothing if no broker is configured.
+func (s *Subscriber) Run() error {
+	if s.client == nil {
+		return nil
+	}
+
+	token := s.client.Connect()
+	if !token.WaitTimeout(connectTimeout) {
+		s.log.Warn("mqtt broker is not reachable yet, retrying in background")
+		return nil
+	}
+	if err := token.Error(); err != nil {
+		return fmt.Errorf("s.client.Connect: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Subscriber) Stop(_ context.Context) error {
+	if s.client != nil {
+		s.client.Disconnect(disconnectQuiesce)
+	}
+	return nil
+}
+
+func (s *Subscriber) onConnect(client pahomqtt.Client) {
+	for _, topic := range s.topics {
+		token := client.Subscribe(topic, s.qos, s.messageHandler(topic))
+		if token.Wait() && token.Error() != nil {
+			s.log.Error("client.Subscribe", zap.Error(token.Error()), zap.String("topic", topic))
+			continue
+		}
+		s.log.Info("subscribed to mqtt topic", zap.String("topic", topic))
+	}
+}
+
+type payload struct {
+	Message     string `json:"message"`
+	MessageType string `json:"message_type"`
+	Component   string `json:"component"`
+	Address     string `json:"address"`
+
+	EventTime  *time.Time        `json:"event_time"`
+	Attributes map[string]string `json:"attributes"`
+	MessageID  string            `json:"message_id"`
+}
+
+func (s *Subscriber) messageHandler(pattern string) pahomqtt.MessageHandler {
+	return func(_ pahomqtt.Client, msg pahomqtt.Message) {
+		message, err := s.toMessage(pattern, msg.Topic(), msg.Payload())
+		if err != nil {
+			s.received.WithLabelValues("rejected").Inc()
+			s.log.Warn("s.toMessage", zap.Error(err), zap.String("topic", msg.Topic()))
+			return
+		}
+
+		if ok, _ := s.rateLimiter.Allow(message.DeviceIP, services.RateLimitDropped); !ok {
+			s.received.WithLabelValues("rate_limited").Inc()
+			return
+		}
+
+		if err = s.natsHandlers.PublishSaveMessage(message); err != nil {
+			s.received.WithLabelValues("failed").Inc()
+			s.log.Error("s.natsHandlers.PublishSaveMessage", zap.Error(err), zap.String("topic", msg.Topic()))
+			return
+		}
+
+		s.received.WithLabelValues("accepted").Inc()
+	}
+}
+
+// toMessage accepts either a JSON payload with the same fields as send_msg
+// or plain text. If the pattern has a '+' the device address is the topic
+// level it matched, broker ACLs on the topic are what authenticate a device,
+// so a payload address other than that one is rejected. Without a '+' the
+// address comes from the payload.
+func (s *Subscriber) toMessage(pattern string, topic string, data []byte) (models.Message, error) {
+	body := payload{}
+	if err := jsoniter.Unmarshal(data, &body); err != nil || body.Message == "" {
+		body = payload{Message: strings.TrimSpace(string(data))}
+	}
+
+	if address, ok := addressFromTopic(pattern, topic); ok {
+		if body.Address != "" && body.Address != address {
+			return models.Message{}, fmt.Errorf("%w: %q in %q", ErrAddressMismatch, body.Address, topic)
+		}
+		body.Address = address
+	}
+
+	if net.ParseIP(body.Address) == nil {
+		return models.Message{}, fmt.Errorf("%w: %q", ErrInvalidAddress, body.Address)
+	}
+
+	if body.Message == "" {
+		return models.Message{}, errors.New("empty message")
+	}
+
+	if len(body.MessageID) > maxMessageIDLen {
+		return models.Message{}, fmt.Errorf("message_id is longer than %d", maxMessageIDLen)
+	}
+
+	if body.MessageType == "" {
+		body.MessageType = defaultMessageType
+	}
+
+	if body.Component == "" {
+		body.Component = defaultComponent
+	}
+
+	message := models.Message{
+		Message:     body.Message,
+		MessageType: body.MessageType,
+		Component:   body.Component,
+		DeviceIP:    body.Address,
+		Attributes:  body.Attributes,
+		MessageID:   body.MessageID,
+	}
+	if body.EventTime != nil {
+		message.EventTime = *body.EventTime
+	}
+
+	return message, nil
+}
+
+// addressFromTopic returns the topic level matched by the first '+' of the
+// pattern, ok is false if the pattern has none.
+func addressFromTopic(pattern string, topic string) (string, bool) {
+	patternLevels := strings.Split(pattern, "/")
+	topicLevels := strings.Split(topic, "/")
+
+	for i, level := range patternLevels {
+		if level != singleLevelWildcard {
+			continue
+		}
+		if i < len(topicLevels) {
+			return topicLevels[i], true
+		}
+		return "", true
+	}
+
+	return "", false
+}
@@ -0,0 +1,73 @@
+package mqtt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestToMessageAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		topic   string
+		payload string
+		address string
+		err     error
+	}{
+		{
+			name:    "address from topic",
+			pattern: "devices/+/logs",
+			topic:   "devices/10.0.0.1/logs",
+			payload: `{"message":"disk full"}`,
+			address: "10.0.0.1",
+		},
+		{
+			name:    "plain text",
+			pattern: "devices/+/logs",
+			topic:   "devices/10.0.0.1/logs",
+			payload: "disk full",
+			address: "10.0.0.1",
+		},
+		{
+			name:    "payload address matches topic",
+			pattern: "devices/+/logs",
+			topic:   "devices/10.0.0.1/logs",
+			payload: `{"message":"disk full","address":"10.0.0.1"}`,
+			address: "10.0.0.1",
+		},
+		{
+			name:    "payload address differs from topic",
+			pattern: "devices/+/logs",
+			topic:   "devices/10.0.0.1/logs",
+			payload: `{"message":"disk full","address":"10.0.0.2"}`,
+			err:     ErrAddressMismatch,
+		},
+		{
+			name:    "address from payload without wildcard",
+			pattern: "logs",
+			topic:   "logs",
+			payload: `{"message":"disk full","address":"10.0.0.2"}`,
+			address: "10.0.0.2",
+		},
+		{
+			name:    "no address",
+			pattern: "logs",
+			topic:   "logs",
+			payload: "disk full",
+			err:     ErrInvalidAddress,
+		},
+	}
+
+	s := &Subscriber{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			message, err := s.toMessage(tt.pattern, tt.topic, []byte(tt.payload))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("toMessage() error = %v, want %v", err, tt.err)
+			}
+			if message.DeviceIP != tt.address {
+				t.Errorf("toMessage() address = %q, want %q", message.DeviceIP, tt.address)
+			}
+		})
+	}
+}
@@ -5,93 +5,242 @@ import (
 	pbdevices "data-ingestion-service/proto/devices"
 	pbmessages "data-ingestion-service/proto/messages"
 
+	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/nats-io/nats.go"
 	"go.uber.org/zap"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 const (
 	dataIngestionQueue = "data-ingestion"
 
 	devicesUpdatedSubject = "devices.updated"
-	readDevicesSubject    = "devices.read"
+	readKeysSubject       = "devices.read_keys"
 
 	saveMessageSubject = "monitoring.msg.save"
 )
 
+// ErrAckTimeout is a message JetStream did not acknowledge in time. It may be
+// stored anyway, so it is worth sending again rather than rejected, a message
+// id keeps the retry from being stored twice.
+var ErrAckTimeout = errors.New("ack timeout")
+
+// ErrQuarantined is not a failure, the message of an unknown source is held in
+// the quarantine stream until the source is registered.
+var ErrQuarantined = errors.New("held in quarantine")
+
 func (n *NatsListeners) listen() error {
-	_, err := n.natsConn.QueueSubscribe(devicesUpdatedSubject, dataIngestionQueue, n.devicesUpdatedHandler)
+	// Every replica keeps its own key hashes, so this is not a queue
+	// subscription.
+	_, err := n.natsConn.Subscribe(devicesUpdatedSubject, n.devicesUpdatedHandler)
 	if err != nil {
 		return fmt.Errorf("n.natsConn.Subscribe("+devicesUpdatedSubject+"): %w", err)
 	}
 
-	err = n.publishDevicesRead()
+	err = n.watchDevices()
+	if err != nil {
+		return fmt.Errorf("n.watchDevices(): %w", err)
+	}
+
+	err = n.listenQuarantine()
 	if err != nil {
-		return fmt.Errorf("n.publishDevicesRead(): %w", err)
+		return fmt.Errorf("n.listenQuarantine(): %w", err)
+	}
+
+	err = n.publishKeysRead()
+	if err != nil {
+		return fmt.Errorf("n.publishKeysRead(): %w", err)
 	}
 
 	return nil
 }
 
+// devicesUpdatedHandler reloads the key hashes, the devices themselves come
+// from the devices bucket.
 func (n *NatsListeners) devicesUpdatedHandler(msg *nats.Msg) {
-	err := n.publishDevicesRead()
+	err := n.publishKeysRead()
 	if err != nil {
-		n.log.Error("n.publishDevicesRead", zap.Error(err))
+		n.log.Error("n.publishKeysRead", zap.Error(err))
 	}
 }
 
-func (n *NatsListeners) publishDevicesRead() error {
-	reply, err := n.natsConn.Request(readDevicesSubject, nil, n.timeout)
+func (n *NatsListeners) publishKeysRead() error {
+	reply, err := n.natsConn.Request(readKeysSubject, nil, n.timeout)
 	if err != nil {
 		return fmt.Errorf("natsConn.Request: %w", err)
 	}
 
-	var devicesProto pbdevices.ReadResp
-	err = proto.Unmarshal(reply.Data, &devicesProto)
+	var keysProto pbdevices.ReadKeysResp
+	err = proto.Unmarshal(reply.Data, &keysProto)
 	if err != nil {
 		return fmt.Errorf("proto.Unmarshal: %w", err)
 	}
+	if keysProto.Error != "" {
+		return fmt.Errorf("reply.Error: %s", keysProto.Error)
+	}
 
-	devices := make([]models.Device, 0)
-	for _, device := range devicesProto.Devices {
-		devices = append(devices, models.Device{
-			ID:          device.ID,
-			Name:        device.Name,
-			DeviceType:  device.DeviceType,
-			Address:     device.Address,
-			Responsible: device.Responsible,
-			CreatedAt:   device.CreatedAt.AsTime(),
-			UpdatedAt:   device.UpdatedAt.AsTime(),
-		})
+	keyHashes := make(map[string]int32, len(keysProto.Keys))
+	for _, key := range keysProto.Keys {
+		keyHashes[key.Hash] = key.DeviceID
 	}
 
-	n.devicesService.UpdateDevices(devices)
+	n.devicesService.UpdateKeyHashes(keyHashes)
 	return nil
 }
 
+// PublishSaveMessage holds messages from addresses that are not registered
+// as devices in the quarantine stream instead of rejecting them.
 func (n *NatsListeners) PublishSaveMessage(message models.Message) error {
+	saveMsg, err := n.marshalSaveMessage(message)
+	if errors.Is(err, ErrUnknownDevice) {
+		return n.quarantineMessage(message)
+	}
+	if err != nil {
+		return fmt.Errorf("n.marshalSaveMessage: %w", err)
+	}
+
+	if n.spoolNotEmpty() {
+		return n.spoolMessage(saveMsg.Data)
+	}
+
+	_, err = n.js.PublishMsg(saveMsg)
+	if err != nil {
+		if n.spool == nil && errors.Is(err, nats.ErrTimeout) {
+			return fmt.Errorf("js.PublishMsg: %w: %w", ErrAckTimeout, err)
+		}
+		if n.spool == nil {
+			return fmt.Errorf("js.PublishMsg: %w", err)
+		}
+
+		n.log.Warn("js.PublishMsg failed, spooling message", zap.Error(err))
+		return n.spoolMessage(saveMsg.Data)
+	}
+
+	return nil
+}
+
+// PublishSaveMessagesAsync publishes all messages without waiting for each
+// ack in turn and returns a per-message error (nil if the message was stored,
+// ErrQuarantined if it was held in quarantine).
+func (n *NatsListeners) PublishSaveMessagesAsync(messages []models.Message) []error {
+	errs := make([]error, len(messages))
+	binaryMessages := make([][]byte, len(messages))
+	futures := make([]nats.PubAckFuture, len(messages))
+
+	spooling := n.spoolNotEmpty()
+
+	for i, message := range messages {
+		saveMsg, err := n.marshalSaveMessage(message)
+		if errors.Is(err, ErrUnknownDevice) {
+			errs[i] = ErrQuarantined
+			if err = n.quarantineMessage(message); err != nil {
+				errs[i] = fmt.Errorf("n.quarantineMessage: %w", err)
+			}
+			continue
+		}
+		if err != nil {
+			errs[i] = fmt.Errorf("n.marshalSaveMessage: %w", err)
+			continue
+		}
+		binaryMessages[i] = saveMsg.Data
+
+		if spooling {
+			errs[i] = n.spoolMessage(saveMsg.Data)
+			continue
+		}
+
+		futures[i], err = n.js.PublishMsgAsync(saveMsg)
+		if err != nil {
+			errs[i] = fmt.Errorf("js.PublishMsgAsync: %w", err)
+		}
+	}
+
+	timeout := time.After(n.timeout)
+	for i, future := range futures {
+		if future == nil {
+			continue
+		}
+
+		select {
+		case <-future.Ok():
+		case err := <-future.Err():
+			errs[i] = fmt.Errorf("js.PublishMsgAsync: %w", err)
+		case <-timeout:
+			errs[i] = fmt.Errorf("js.PublishMsgAsync: %w", ErrAckTimeout)
+		}
+	}
+
+	if n.spool == nil || spooling {
+		return errs
+	}
+
+	// Marshal errors and quarantined messages leave binaryMessages[i] empty,
+	// only failed publishes are spooled.
+	for i, err := range errs {
+		if err != nil && binaryMessages[i] != nil {
+			errs[i] = n.spoolMessage(binaryMessages[i])
+		}
+	}
+
+	return errs
+}
+
+func (n *NatsListeners) marshalSaveMessage(message models.Message) (*nats.Msg, error) {
 	deviceId, ok := n.devicesService.GetDeviceIDByIp(message.DeviceIP)
 	if !ok {
-		return fmt.Errorf("unknown ip")
+		return nil, ErrUnknownDevice
+	}
+	receivedAt := message.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
 	}
+
 	pbMessage := pbmessages.MessageSave{
 		DeviceID:    deviceId,
 		Message:     message.Message,
 		MessageType: message.MessageType,
 		Component:   message.Component,
+		ReceivedAt:  timestamppb.New(receivedAt),
+		Attributes:  message.Attributes,
+		MessageID:   message.MessageID,
+	}
+	if !message.EventTime.IsZero() {
+		pbMessage.EventTime = timestamppb.New(message.EventTime)
 	}
 
 	binaryMessage, err := proto.Marshal(&pbMessage)
 	if err != nil {
-		return fmt.Errorf("proto.Marshal: %w", err)
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
 	}
 
-	_, err = n.js.Publish(saveMessageSubject, binaryMessage)
-	if err != nil {
-		return fmt.Errorf("js.Publish: %w", err)
+	return newSaveMsg(binaryMessage, deviceId, message.MessageID), nil
+}
+
+// newSaveMsg sets the client message ID as Nats-Msg-Id, so the stream drops
+// retries of a message within its duplicates window. The ID is only unique
+// per device, hence the prefix.
+func newSaveMsg(binaryMessage []byte, deviceID int32, messageID string) *nats.Msg {
+	msg := nats.NewMsg(saveMessageSubject)
+	msg.Data = binaryMessage
+	if messageID != "" {
+		msg.Header.Set(nats.MsgIdHdr, strconv.Itoa(int(deviceID))+":"+messageID)
 	}
 
-	return nil
+	return msg
+}
+
+// spooledSaveMsg restores the Nats-Msg-Id of a spooled message, only its
+// payload is kept on disk.
+func spooledSaveMsg(binaryMessage []byte) *nats.Msg {
+	var pbMessage pbmessages.MessageSave
+	if err := proto.Unmarshal(binaryMessage, &pbMessage); err != nil {
+		return newSaveMsg(binaryMessage, 0, "")
+	}
+
+	return newSaveMsg(binaryMessage, pbMessage.DeviceID, pbMessage.MessageID)
 }
@@ -0,0 +1,140 @@
+package natslistener
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"data-ingestion-service/internal/models"
+	pbdevices "data-ingestion-service/proto/devices"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// devicesBucket is materialised by device-management-service, every device is
+// stored under its id.
+const devicesBucket = "devices"
+
+// devicesWatchRetry is the pause before a stopped watcher of the devices
+// bucket is started again.
+const devicesWatchRetry = 5 * time.Second
+
+// bindDevicesBucket binds the devices bucket. If device-management-service
+// did not create it yet, it is created with the same config, so the services
+// start in any order and the devices show up once it fills the bucket.
+func bindDevicesBucket(js nats.JetStreamContext) (nats.KeyValue, error) {
+	kv, err := js.KeyValue(devicesBucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      devicesBucket,
+			Description: "Devices by id, written by device-management-service",
+			History:     1,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("js.KeyValue("+devicesBucket+"): %w", err)
+	}
+
+	return kv, nil
+}
+
+// watchDevices loads the devices bucket into the local cache and keeps
+// applying the changes of single devices. It returns once the current content
+// is loaded.
+func (n *NatsListeners) watchDevices() error {
+	kv, err := bindDevicesBucket(n.js)
+	if err != nil {
+		return fmt.Errorf("bindDevicesBucket: %w", err)
+	}
+
+	watcher, err := kv.WatchAll()
+	if err != nil {
+		return fmt.Errorf("kv.WatchAll: %w", err)
+	}
+
+	// A nil entry marks the end of the initial values.
+	for entry := range watcher.Updates() {
+		if entry == nil {
+			break
+		}
+		n.applyDeviceEntry(entry)
+	}
+
+	n.wg.Add(1)
+	go n.followDevices(kv, watcher)
+
+	return nil
+}
+
+// followDevices applies the changes of single devices until the listeners
+// stop. A stopped watcher is started again, its initial values bring the
+// cache up to date.
+func (n *NatsListeners) followDevices(kv nats.KeyValue, watcher nats.KeyWatcher) {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case <-n.done:
+			watcher.Stop() //nolint:errcheck
+			return
+		case entry, ok := <-watcher.Updates():
+			if ok {
+				if entry != nil {
+					n.applyDeviceEntry(entry)
+				}
+				continue
+			}
+		}
+
+		n.log.Warn("devices bucket watcher stopped, restarting")
+		for {
+			select {
+			case <-n.done:
+				return
+			case <-time.After(devicesWatchRetry):
+			}
+
+			var err error
+			watcher, err = kv.WatchAll()
+			if err == nil {
+				break
+			}
+			n.log.Error("kv.WatchAll", zap.Error(err))
+		}
+	}
+}
+
+func (n *NatsListeners) applyDeviceEntry(entry nats.KeyValueEntry) {
+	if entry.Operation() != nats.KeyValuePut {
+		deviceID, err := strconv.ParseInt(entry.Key(), 10, 32)
+		if err != nil {
+			n.log.Warn("strconv.ParseInt", zap.Error(err), zap.String("key", entry.Key()))
+			return
+		}
+		n.devicesService.DeleteDevice(int32(deviceID))
+		return
+	}
+
+	var device pbdevices.Device
+	if err := proto.Unmarshal(entry.Value(), &device); err != nil {
+		n.log.Error("proto.Unmarshal", zap.Error(err), zap.String("key", entry.Key()))
+		return
+	}
+
+	n.devicesService.UpsertDevice(deviceFromProto(&device))
+}
+
+func deviceFromProto(device *pbdevices.Device) models.Device {
+	return models.Device{
+		ID:          device.GetID(),
+		Name:        device.GetName(),
+		DeviceType:  device.GetDeviceType(),
+		Address:     device.GetAddress(),
+		Responsible: device.GetResponsible(),
+		CreatedAt:   device.GetCreatedAt().AsTime(),
+		UpdatedAt:   device.GetUpdatedAt().AsTime(),
+	}
+}
@@ -1,7 +1,11 @@
 package natslistener
 
 import (
+	"context"
 	"data-ingestion-service/internal/services"
+	"data-ingestion-service/pkg/spool"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/nats-io/nats.go"
@@ -15,6 +19,18 @@ type NatsListeners struct {
 	timeout  time.Duration
 
 	devicesService services.DeviceService
+
+	spool               *spool.Spool
+	spoolReplayInterval time.Duration
+	spoolMetrics        spoolMetrics
+	reconnected         chan struct{}
+	done                chan struct{}
+	wg                  sync.WaitGroup
+
+	quarantine           QuarantineConfig
+	quarantineMetrics    quarantineMetrics
+	autoRegisterMu       sync.Mutex
+	autoRegisterAttempts map[string]time.Time
 }
 
 type Config struct {
@@ -24,18 +40,56 @@ type Config struct {
 	Js       nats.JetStreamContext
 
 	DevicesService services.DeviceService
+
+	// Spool is optional, without it a failed publish is returned to the caller.
+	Spool               *spool.Spool
+	SpoolReplayInterval time.Duration
+
+	Quarantine QuarantineConfig
 }
 
 func NewListener(cfg Config) *NatsListeners {
-	return &NatsListeners{
-		natsConn:       cfg.NatsConn,
-		log:            cfg.Log,
-		js:             cfg.Js,
-		timeout:        cfg.Timeout,
-		devicesService: cfg.DevicesService,
+	listeners := &NatsListeners{
+		natsConn:            cfg.NatsConn,
+		log:                 cfg.Log,
+		js:                  cfg.Js,
+		timeout:             cfg.Timeout,
+		devicesService:      cfg.DevicesService,
+		spool:               cfg.Spool,
+		spoolReplayInterval: cfg.SpoolReplayInterval,
+		reconnected:         make(chan struct{}, 1),
+		done:                make(chan struct{}),
+
+		quarantine:           cfg.Quarantine,
+		quarantineMetrics:    newQuarantineMetrics(),
+		autoRegisterAttempts: make(map[string]time.Time),
+	}
+
+	if listeners.spool != nil {
+		listeners.spoolMetrics = newSpoolMetrics(listeners.spool)
 	}
+
+	return listeners
 }
 
 func (n *NatsListeners) Run() error {
+	if n.spool != nil {
+		n.wg.Add(1)
+		go n.runSpoolReplayer()
+	}
+
 	return n.listen()
 }
+
+func (n *NatsListeners) Stop(_ context.Context) error {
+	close(n.done)
+	n.wg.Wait()
+
+	if n.spool != nil {
+		if err := n.spool.Close(); err != nil {
+			return fmt.Errorf("n.spool.Close: %w", err)
+		}
+	}
+
+	return nil
+}
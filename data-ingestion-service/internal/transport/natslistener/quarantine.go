@@ -0,0 +1,545 @@
+package natslistener
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"data-ingestion-service/internal/models"
+	pbapiquarantine "data-ingestion-service/proto/api-gateway/quarantine"
+	pbdevices "data-ingestion-service/proto/devices"
+	pbquarantine "data-ingestion-service/proto/quarantine"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	quarantineStream        = "quarantine"
+	quarantineSubjectPrefix = "monitoring.msg.quarantine."
+
+	listQuarantineSubject     = "quarantine.list"
+	registerQuarantineSubject = "quarantine.register"
+	replayQuarantineSubject   = "quarantine.replay"
+
+	createDevicesSubject = "devices.create"
+
+	replayBatchSize = 100
+
+	autoRegisterNamePrefix = "auto-"
+	autoRegisterRetry      = time.Minute
+)
+
+var (
+	ErrUnknownDevice = errors.New("unknown ip")
+	// ErrQuarantineFull drops the messages of a new source while MaxSources
+	// sources are held.
+	ErrQuarantineFull = errors.New("quarantine is full")
+)
+
+type QuarantineConfig struct {
+	MaxAge               time.Duration
+	MaxMessagesPerSource int64
+	// MaxBytes drops the oldest held messages of any source, MaxSources
+	// bounds the sources held. Zero disables either limit.
+	MaxBytes   int64
+	MaxSources int
+
+	// Sources from these networks are registered as devices on their first
+	// message, nil disables auto-registration.
+	AutoRegisterNets        []*net.IPNet
+	AutoRegisterDeviceType  string
+	AutoRegisterResponsible []int32
+}
+
+type quarantineMetrics struct {
+	quarantined    prometheus.Counter
+	rejected       prometheus.Counter
+	replayed       prometheus.Counter
+	autoRegistered prometheus.Counter
+}
+
+func newQuarantineMetrics() quarantineMetrics {
+	metrics := quarantineMetrics{
+		quarantined: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "quarantine_messages_total",
+			Help: "Total messages from unknown sources held in quarantine",
+		}),
+		rejected: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "quarantine_rejected_total",
+			Help: "Total messages of new sources dropped while the quarantine held its maximum of sources",
+		}),
+		replayed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "quarantine_replayed_total",
+			Help: "Total quarantined messages replayed after their source was registered",
+		}),
+		autoRegistered: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "quarantine_auto_registered_total",
+			Help: "Total sources registered as devices by the auto-register policy",
+		}),
+	}
+
+	prometheus.MustRegister(metrics.quarantined, metrics.rejected, metrics.replayed, metrics.autoRegistered)
+
+	return metrics
+}
+
+func (n *NatsListeners) listenQuarantine() error {
+	streamConfig := &nats.StreamConfig{
+		Name:              quarantineStream,
+		Subjects:          []string{quarantineSubjectPrefix + ">"},
+		Storage:           nats.FileStorage,
+		Discard:           nats.DiscardOld,
+		MaxAge:            n.quarantine.MaxAge,
+		MaxMsgsPerSubject: n.quarantine.MaxMessagesPerSource,
+		MaxBytes:          n.quarantine.MaxBytes,
+	}
+
+	_, err := n.js.AddStream(streamConfig)
+	if errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		_, err = n.js.UpdateStream(streamConfig)
+	}
+	if err != nil {
+		return fmt.Errorf("n.js.AddStream("+quarantineStream+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(listQuarantineSubject, dataIngestionQueue, n.listQuarantineHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+listQuarantineSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(registerQuarantineSubject, dataIngestionQueue, n.registerQuarantineHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+registerQuarantineSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(replayQuarantineSubject, dataIngestionQueue, n.replayQuarantineHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+replayQuarantineSubject+"): %w", err)
+	}
+
+	return nil
+}
+
+// Subject tokens are separated by dots, so they are swapped for underscores
+// in IPv4 addresses. IPv6 colons are valid in a subject as is.
+func quarantineSubject(address string) string {
+	return quarantineSubjectPrefix + strings.ReplaceAll(address, ".", "_")
+}
+
+func addressFromQuarantineSubject(subject string) string {
+	return strings.ReplaceAll(strings.TrimPrefix(subject, quarantineSubjectPrefix), "_", ".")
+}
+
+func (n *NatsListeners) quarantineMessage(message models.Message) error {
+	receivedAt := message.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	quarantined := &pbquarantine.QuarantinedMessage{
+		Address:     message.DeviceIP,
+		Message:     message.Message,
+		MessageType: message.MessageType,
+		Component:   message.Component,
+		ReceivedAt:  timestamppb.New(receivedAt),
+		Attributes:  message.Attributes,
+		MessageID:   message.MessageID,
+	}
+	if !message.EventTime.IsZero() {
+		quarantined.EventTime = timestamppb.New(message.EventTime)
+	}
+
+	binaryMessage, err := proto.Marshal(quarantined)
+	if err != nil {
+		return fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	if err = n.admitQuarantineSource(message.DeviceIP); err != nil {
+		return fmt.Errorf("n.admitQuarantineSource: %w", err)
+	}
+
+	if _, err = n.js.Publish(quarantineSubject(message.DeviceIP), binaryMessage); err != nil {
+		return fmt.Errorf("js.Publish: %w", err)
+	}
+
+	n.quarantineMetrics.quarantined.Inc()
+	n.autoRegister(message)
+
+	return nil
+}
+
+// admitQuarantineSource lets a source already held in. The stream has no limit
+// on its subjects, so a new one is checked against MaxSources here. Replicas
+// admitting new sources at the same time may go a few over it.
+func (n *NatsListeners) admitQuarantineSource(address string) error {
+	if n.quarantine.MaxSources <= 0 {
+		return nil
+	}
+
+	_, err := n.js.GetLastMsg(quarantineStream, quarantineSubject(address))
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, nats.ErrMsgNotFound) {
+		return fmt.Errorf("js.GetLastMsg: %w", err)
+	}
+
+	info, err := n.js.StreamInfo(quarantineStream)
+	if err != nil {
+		return fmt.Errorf("js.StreamInfo: %w", err)
+	}
+	if info.State.NumSubjects >= uint64(n.quarantine.MaxSources) {
+		n.quarantineMetrics.rejected.Inc()
+		return fmt.Errorf("%w: %d sources are held", ErrQuarantineFull, info.State.NumSubjects)
+	}
+
+	return nil
+}
+
+func (n *NatsListeners) listQuarantineHandler(msg *nats.Msg) {
+	sources, err := n.quarantinedSources()
+	if err != nil {
+		n.log.Error("n.quarantinedSources", zap.Error(err))
+		n.sendError(msg.Reply, &pbapiquarantine.ListResp{Error: err.Error()})
+		return
+	}
+
+	resp := pbapiquarantine.ListResp{
+		Sources: sources,
+	}
+
+	binaryResp, err := proto.Marshal(&resp)
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) quarantinedSources() ([]*pbapiquarantine.Source, error) {
+	info, err := n.js.StreamInfo(quarantineStream, &nats.StreamInfoRequest{
+		SubjectsFilter: quarantineSubjectPrefix + ">",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("js.StreamInfo: %w", err)
+	}
+
+	sources := make([]*pbapiquarantine.Source, 0, len(info.State.Subjects))
+	for subject, count := range info.State.Subjects {
+		source := &pbapiquarantine.Source{
+			Address:  addressFromQuarantineSubject(subject),
+			Messages: count,
+		}
+
+		last, err := n.js.GetLastMsg(quarantineStream, subject)
+		if err != nil {
+			return nil, fmt.Errorf("js.GetLastMsg: %w", err)
+		}
+
+		var held pbquarantine.QuarantinedMessage
+		if err = proto.Unmarshal(last.Data, &held); err == nil {
+			source.LastSeenAt = held.ReceivedAt
+			source.LastMessage = held.Message
+		}
+
+		sources = append(sources, source)
+	}
+
+	sort.Slice(sources, func(i, j int) bool {
+		return sources[i].Address < sources[j].Address
+	})
+
+	return sources, nil
+}
+
+func (n *NatsListeners) registerQuarantineHandler(msg *nats.Msg) {
+	var request pbapiquarantine.RegisterReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+		n.sendError(msg.Reply, &pbapiquarantine.RegisterResp{Error: err.Error()})
+		return
+	}
+
+	deviceID, apiKey, err := n.registerSource(request.Address, request.Name, request.DeviceType, request.Responsible)
+	if err != nil {
+		n.log.Error("n.registerSource", zap.Error(err))
+		n.sendError(msg.Reply, &pbapiquarantine.RegisterResp{Error: err.Error()})
+		return
+	}
+
+	resp := pbapiquarantine.RegisterResp{
+		DeviceID: deviceID,
+		APIKey:   apiKey,
+	}
+
+	if request.Replay {
+		resp.Replayed, err = n.replaySource(request.Address)
+		if err != nil {
+			n.log.Error("n.replaySource", zap.Error(err))
+			resp.Error = fmt.Sprintf("device %d is registered, but replay failed: %s", deviceID, err)
+		}
+	}
+
+	binaryResp, err := proto.Marshal(&resp)
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) replayQuarantineHandler(msg *nats.Msg) {
+	var request pbapiquarantine.ReplayReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+		n.sendError(msg.Reply, &pbapiquarantine.ReplayResp{Error: err.Error()})
+		return
+	}
+
+	replayed, err := n.replaySource(request.Address)
+	if err != nil {
+		n.log.Error("n.replaySource", zap.Error(err))
+		n.sendError(msg.Reply, &pbapiquarantine.ReplayResp{Replayed: replayed, Error: err.Error()})
+		return
+	}
+
+	binaryResp, err := proto.Marshal(&pbapiquarantine.ReplayResp{Replayed: replayed})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+// registerSource creates the device and adds it to the local cache right away,
+// so held messages can be replayed without waiting for the bucket watcher. It
+// returns the id and the ingestion key of the new device.
+func (n *NatsListeners) registerSource(address string, name string, deviceType string, responsible []int32) (int32, string, error) {
+	if net.ParseIP(address) == nil {
+		return 0, "", fmt.Errorf("invalid address %q", address)
+	}
+
+	createReqBytes, err := proto.Marshal(&pbdevices.CreateReq{
+		Device: &pbdevices.Device{
+			Name:        name,
+			DeviceType:  deviceType,
+			Address:     address,
+			Responsible: responsible,
+		},
+	})
+	if err != nil {
+		return 0, "", fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	replyMsg, err := n.natsConn.Request(createDevicesSubject, createReqBytes, n.timeout)
+	if err != nil {
+		return 0, "", fmt.Errorf("natsConn.Request: %w", err)
+	}
+
+	var reply pbdevices.CreateResp
+	if err = proto.Unmarshal(replyMsg.Data, &reply); err != nil {
+		return 0, "", fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+	if reply.Error != "" {
+		return 0, "", fmt.Errorf("reply.Error: %s", reply.Error)
+	}
+
+	n.devicesService.UpsertDevice(deviceFromProto(reply.Created))
+
+	if err = n.publishKeysRead(); err != nil {
+		return 0, "", fmt.Errorf("n.publishKeysRead: %w", err)
+	}
+
+	return reply.Created.GetID(), reply.APIKey, nil
+}
+
+// replaySource publishes the held messages of a registered source in order
+// and purges the ones that made it.
+func (n *NatsListeners) replaySource(address string) (uint64, error) {
+	if _, ok := n.devicesService.GetDeviceIDByIp(address); !ok {
+		return 0, fmt.Errorf("%w: %s is not registered", ErrUnknownDevice, address)
+	}
+
+	subject := quarantineSubject(address)
+	sub, err := n.js.PullSubscribe(subject, "", nats.BindStream(quarantineStream), nats.AckNone())
+	if err != nil {
+		return 0, fmt.Errorf("js.PullSubscribe: %w", err)
+	}
+	defer sub.Unsubscribe() //nolint:errcheck
+
+	var (
+		replayed uint64
+		lastSeq  uint64
+	)
+
+	err = func() error {
+		for {
+			msgs, err := sub.Fetch(replayBatchSize, nats.MaxWait(n.timeout))
+			if errors.Is(err, nats.ErrTimeout) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("sub.Fetch: %w", err)
+			}
+
+			for _, msg := range msgs {
+				meta, err := msg.Metadata()
+				if err != nil {
+					return fmt.Errorf("msg.Metadata: %w", err)
+				}
+
+				var held pbquarantine.QuarantinedMessage
+				if err = proto.Unmarshal(msg.Data, &held); err != nil {
+					n.log.Error("proto.Unmarshal", zap.Error(err), zap.String("Subject", msg.Subject))
+				} else {
+					// Replayed messages keep the time they were first received.
+					message := models.Message{
+						Message:     held.Message,
+						MessageType: held.MessageType,
+						Component:   held.Component,
+						DeviceIP:    held.Address,
+						ReceivedAt:  held.ReceivedAt.AsTime(),
+						Attributes:  held.Attributes,
+						MessageID:   held.MessageID,
+					}
+					if held.EventTime != nil {
+						message.EventTime = held.EventTime.AsTime()
+					}
+
+					err = n.PublishSaveMessage(message)
+					if err != nil {
+						return fmt.Errorf("n.PublishSaveMessage: %w", err)
+					}
+					replayed++
+				}
+
+				lastSeq = meta.Sequence.Stream
+				if meta.NumPending == 0 {
+					return nil
+				}
+			}
+		}
+	}()
+
+	n.quarantineMetrics.replayed.Add(float64(replayed))
+
+	if lastSeq > 0 {
+		purgeErr := n.js.PurgeStream(quarantineStream, &nats.StreamPurgeRequest{
+			Subject:  subject,
+			Sequence: lastSeq + 1,
+		})
+		if purgeErr != nil {
+			err = errors.Join(err, fmt.Errorf("js.PurgeStream: %w", purgeErr))
+		}
+	}
+
+	return replayed, err
+}
+
+// autoRegister only registers a source that sent the message itself, the
+// address in the body of an HTTP or MQTT message can be anything.
+func (n *NatsListeners) autoRegister(message models.Message) {
+	address := message.DeviceIP
+	if !n.autoRegisterAllowed(address, message.PeerIP) {
+		return
+	}
+
+	go func() {
+		// The key is not kept, it can be issued again with devices.rotate_key.
+		deviceID, _, err := n.registerSource(
+			address,
+			autoRegisterNamePrefix+address,
+			n.quarantine.AutoRegisterDeviceType,
+			n.quarantine.AutoRegisterResponsible,
+		)
+		if err != nil {
+			n.log.Error("n.registerSource", zap.Error(err), zap.String("address", address))
+			return
+		}
+
+		n.quarantineMetrics.autoRegistered.Inc()
+
+		replayed, err := n.replaySource(address)
+		if err != nil {
+			n.log.Error("n.replaySource", zap.Error(err), zap.String("address", address))
+		}
+
+		n.log.Info("auto-registered device",
+			zap.String("address", address),
+			zap.Int32("device_id", deviceID),
+			zap.Uint64("replayed", replayed),
+		)
+	}()
+}
+
+// autoRegisterAllowed checks the peer the message came from against the
+// networks, it must be the address the message claims. It also rate limits
+// attempts per address, so a source that keeps failing to register does not
+// flood devices.create.
+func (n *NatsListeners) autoRegisterAllowed(address string, peerIP string) bool {
+	ip := net.ParseIP(peerIP)
+	if ip == nil || !ip.Equal(net.ParseIP(address)) {
+		return false
+	}
+
+	allowed := false
+	for _, ipNet := range n.quarantine.AutoRegisterNets {
+		if ipNet.Contains(ip) {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return false
+	}
+
+	n.autoRegisterMu.Lock()
+	defer n.autoRegisterMu.Unlock()
+
+	if lastAttempt, ok := n.autoRegisterAttempts[address]; ok && time.Since(lastAttempt) < autoRegisterRetry {
+		return false
+	}
+	n.autoRegisterAttempts[address] = time.Now()
+
+	return true
+}
+
+func (n *NatsListeners) sendError(subject string, message proto.Message) {
+	binaryResp, err := proto.Marshal(message)
+	if err != nil {
+		n.log.Error("sendError: proto.Marshal", zap.Error(err))
+		return
+	}
+	if err := n.natsConn.Publish(subject, binaryResp); err != nil {
+		n.log.Error("sendError: n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
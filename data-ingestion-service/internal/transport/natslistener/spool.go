@@ -0,0 +1,142 @@
+package natslistener
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"data-ingestion-service/pkg/spool"
+
+	"github.com/nats-io/nats.go"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+type spoolMetrics struct {
+	spooled  prometheus.Counter
+	replayed prometheus.Counter
+}
+
+func newSpoolMetrics(s *spool.Spool) spoolMetrics {
+	metrics := spoolMetrics{
+		spooled: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "spool_appended_total",
+			Help: "Total messages written to the spool because JetStream was unavailable",
+		}),
+		replayed: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "spool_replayed_total",
+			Help: "Total spooled messages published to JetStream",
+		}),
+	}
+
+	depth := prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "spool_depth_messages",
+			Help: "Messages waiting in the spool",
+		},
+		func() float64 { return float64(s.Stats().Records) },
+	)
+
+	size := prometheus.NewGaugeFunc(
+		prometheus.GaugeOpts{
+			Name: "spool_size_bytes",
+			Help: "Bytes waiting in the spool",
+		},
+		func() float64 { return float64(s.Stats().Bytes) },
+	)
+
+	dropped := func(reason string, value func(spool.Stats) uint64) prometheus.CounterFunc {
+		return prometheus.NewCounterFunc(
+			prometheus.CounterOpts{
+				Name:        "spool_dropped_total",
+				Help:        "Total spooled messages dropped before replay",
+				ConstLabels: prometheus.Labels{"reason": reason},
+			},
+			func() float64 { return float64(value(s.Stats())) },
+		)
+	}
+
+	prometheus.MustRegister(
+		metrics.spooled,
+		metrics.replayed,
+		depth,
+		size,
+		dropped("expired", func(stats spool.Stats) uint64 { return stats.Expired }),
+		dropped("overflow", func(stats spool.Stats) uint64 { return stats.Overflowed }),
+		dropped("corrupted", func(stats spool.Stats) uint64 { return stats.Corrupted }),
+	)
+
+	return metrics
+}
+
+func (n *NatsListeners) spoolNotEmpty() bool {
+	return n.spool != nil && n.spool.Len() > 0
+}
+
+// spoolMessage keeps the message on disk until the replayer publishes it.
+// Once anything is spooled new messages go to the spool too, so they reach
+// JetStream in the order they were received.
+func (n *NatsListeners) spoolMessage(binaryMessage []byte) error {
+	if err := n.spool.Append(binaryMessage); err != nil {
+		return fmt.Errorf("spool.Append: %w", err)
+	}
+
+	n.spoolMetrics.spooled.Inc()
+	return nil
+}
+
+func (n *NatsListeners) runSpoolReplayer() {
+	defer n.wg.Done()
+
+	n.natsConn.SetReconnectHandler(func(_ *nats.Conn) {
+		select {
+		case n.reconnected <- struct{}{}:
+		default:
+		}
+	})
+
+	ticker := time.NewTicker(n.spoolReplayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.done:
+			return
+		case <-n.reconnected:
+		case <-ticker.C:
+		}
+
+		n.drainSpool()
+	}
+}
+
+func (n *NatsListeners) drainSpool() {
+	for {
+		select {
+		case <-n.done:
+			return
+		default:
+		}
+
+		record, err := n.spool.Next()
+		if errors.Is(err, spool.ErrEmpty) {
+			return
+		}
+		if err != nil {
+			n.log.Error("spool.Next", zap.Error(err))
+			return
+		}
+
+		if _, err = n.js.PublishMsg(spooledSaveMsg(record.Data)); err != nil {
+			n.log.Debug("js.PublishMsg spooled message", zap.Error(err))
+			return
+		}
+
+		if err = n.spool.Ack(); err != nil {
+			n.log.Error("spool.Ack", zap.Error(err))
+			return
+		}
+
+		n.spoolMetrics.replayed.Inc()
+	}
+}
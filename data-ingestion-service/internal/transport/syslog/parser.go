@@ -0,0 +1,267 @@
+package syslog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	nilValue = "-"
+
+	// BOM that RFC 5424 allows in front of an UTF-8 MSG part.
+	utf8BOM = "\xef\xbb\xbf"
+
+	// Biggest PRI value, facility 23 (local7) and severity 7 (debug).
+	maxPriority = 191
+)
+
+var (
+	ErrEmptyMessage    = errors.New("empty syslog message")
+	ErrInvalidPriority = errors.New("invalid syslog priority")
+)
+
+var severityNames = [...]string{
+	"emergency",
+	"alert",
+	"critical",
+	"error",
+	"warning",
+	"notice",
+	"info",
+	"debug",
+}
+
+var facilityNames = [...]string{
+	"kern",
+	"user",
+	"mail",
+	"daemon",
+	"auth",
+	"syslog",
+	"lpr",
+	"news",
+	"uucp",
+	"cron",
+	"authpriv",
+	"ftp",
+	"ntp",
+	"security",
+	"console",
+	"solaris-cron",
+	"local0",
+	"local1",
+	"local2",
+	"local3",
+	"local4",
+	"local5",
+	"local6",
+	"local7",
+}
+
+type Message struct {
+	Facility  int
+	Severity  int
+	Timestamp time.Time
+	Hostname  string
+	AppName   string
+	ProcID    string
+	MsgID     string
+	Content   string
+}
+
+func (m Message) SeverityName() string {
+	return severityNames[m.Severity]
+}
+
+func (m Message) FacilityName() string {
+	return facilityNames[m.Facility]
+}
+
+// Parse detects the frame format by the VERSION field that follows PRI:
+// RFC 5424 frames have it, RFC 3164 (BSD) frames don't.
+func Parse(data []byte) (Message, error) {
+	data = bytes.TrimRight(data, "\r\n\x00")
+	if len(data) == 0 {
+		return Message{}, ErrEmptyMessage
+	}
+
+	priority, rest, err := parsePriority(data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	msg := Message{
+		Facility: priority / 8, //nolint:mnd
+		Severity: priority % 8, //nolint:mnd
+	}
+
+	if len(rest) > 1 && rest[0] >= '1' && rest[0] <= '9' && rest[1] == ' ' {
+		err = parseRFC5424(string(rest[2:]), &msg)
+		if err != nil {
+			return Message{}, fmt.Errorf("parseRFC5424: %w", err)
+		}
+		return msg, nil
+	}
+
+	parseRFC3164(string(rest), &msg)
+	return msg, nil
+}
+
+func parsePriority(data []byte) (int, []byte, error) {
+	if data[0] != '<' {
+		return 0, nil, ErrInvalidPriority
+	}
+
+	end := bytes.IndexByte(data, '>')
+	if end < 2 || end > 4 { //nolint:mnd
+		return 0, nil, ErrInvalidPriority
+	}
+
+	priority, err := strconv.Atoi(string(data[1:end]))
+	if err != nil || priority < 0 || priority > maxPriority {
+		return 0, nil, ErrInvalidPriority
+	}
+
+	return priority, data[end+1:], nil
+}
+
+// <PRI>VERSION SP TIMESTAMP SP HOSTNAME SP APP-NAME SP PROCID SP MSGID SP STRUCTURED-DATA [SP MSG]
+func parseRFC5424(data string, msg *Message) error {
+	fields := make([]string, 0, 5) //nolint:mnd
+	for range 5 {
+		field, rest, ok := strings.Cut(data, " ")
+		if !ok {
+			return fmt.Errorf("header is too short: %q", data)
+		}
+		fields = append(fields, field)
+		data = rest
+	}
+
+	if fields[0] != nilValue {
+		timestamp, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return fmt.Errorf("time.Parse: %w", err)
+		}
+		msg.Timestamp = timestamp
+	}
+
+	msg.Hostname = nilToEmpty(fields[1])
+	msg.AppName = nilToEmpty(fields[2])
+	msg.ProcID = nilToEmpty(fields[3])
+	msg.MsgID = nilToEmpty(fields[4])
+
+	rest, err := skipStructuredData(data)
+	if err != nil {
+		return fmt.Errorf("skipStructuredData: %w", err)
+	}
+
+	rest = strings.TrimPrefix(rest, " ")
+	rest = strings.TrimPrefix(rest, utf8BOM)
+	msg.Content = rest
+
+	return nil
+}
+
+// skipStructuredData returns everything after the STRUCTURED-DATA field.
+// SD-PARAM values are quoted and may contain escaped '"', '\' and ']'.
+func skipStructuredData(data string) (string, error) {
+	if strings.HasPrefix(data, nilValue) {
+		return data[1:], nil
+	}
+
+	i := 0
+	for i < len(data) && data[i] == '[' {
+		inQuotes := false
+		closed := false
+		for i++; i < len(data); i++ {
+			switch c := data[i]; {
+			case c == '\\' && inQuotes:
+				i++
+			case c == '"':
+				inQuotes = !inQuotes
+			case c == ']' && !inQuotes:
+				closed = true
+			}
+			if closed {
+				break
+			}
+		}
+		if !closed {
+			return "", fmt.Errorf("unterminated SD-ELEMENT: %q", data)
+		}
+		i++
+	}
+
+	if i == 0 {
+		return "", fmt.Errorf("invalid STRUCTURED-DATA: %q", data)
+	}
+
+	return data[i:], nil
+}
+
+// RFC 3164 is a description of existing practice rather than a strict format,
+// so every header field is optional: <PRI>Mmm dd hh:mm:ss HOSTNAME TAG[PID]: MSG
+func parseRFC3164(data string, msg *Message) {
+	if len(data) >= len(time.Stamp) {
+		timestamp, err := time.ParseInLocation(time.Stamp, data[:len(time.Stamp)], time.Local)
+		if err == nil {
+			msg.Timestamp = withCurrentYear(timestamp)
+			data = strings.TrimPrefix(data[len(time.Stamp):], " ")
+
+			if hostname, rest, ok := strings.Cut(data, " "); ok && !isTag(hostname) {
+				msg.Hostname = hostname
+				data = rest
+			}
+		}
+	}
+
+	if tag, rest, ok := strings.Cut(data, ":"); ok && isTag(tag+":") {
+		if name, pid, ok := strings.Cut(tag, "["); ok {
+			msg.AppName = name
+			msg.ProcID = strings.TrimSuffix(pid, "]")
+		} else {
+			msg.AppName = tag
+		}
+		data = strings.TrimPrefix(rest, " ")
+	}
+
+	msg.Content = data
+}
+
+// isTag reports whether the token looks like "name:" or "name[pid]:".
+func isTag(token string) bool {
+	if !strings.HasSuffix(token, ":") || len(token) == 1 {
+		return false
+	}
+
+	for _, r := range strings.TrimSuffix(token, ":") {
+		if r == ' ' || r == utf8.RuneError {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RFC 3164 timestamps carry no year, assume the message is not from the future.
+func withCurrentYear(timestamp time.Time) time.Time {
+	now := time.Now()
+	timestamp = timestamp.AddDate(now.Year(), 0, 0)
+	if timestamp.After(now.Add(24 * time.Hour)) {
+		timestamp = timestamp.AddDate(-1, 0, 0)
+	}
+
+	return timestamp
+}
+
+func nilToEmpty(field string) string {
+	if field == nilValue {
+		return ""
+	}
+	return field
+}
@@ -0,0 +1,296 @@
+package syslog
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"data-ingestion-service/internal/models"
+	"data-ingestion-service/internal/services"
+	"data-ingestion-service/internal/transport/natslistener"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	transportUDP = "udp"
+	transportTCP = "tcp"
+
+	maxFrameSize = 64 * 1024
+
+	// Component column in data-processing-service is varchar(30).
+	maxComponentLen = 30
+)
+
+type Server struct {
+	udpAddr string
+	tcpAddr string
+
+	natsHandlers *natslistener.NatsListeners
+	rateLimiter  *services.RateLimiter
+
+	udpConn     net.PacketConn
+	tcpListener net.Listener
+	wg          sync.WaitGroup
+	closed      atomic.Bool
+
+	received    *prometheus.CounterVec
+	parseErrors *prometheus.CounterVec
+
+	log *zap.Logger
+}
+
+type Config struct {
+	UDPAddr string
+	TCPAddr string
+
+	NatsHandlers *natslistener.NatsListeners
+	RateLimiter  *services.RateLimiter
+
+	Log *zap.Logger
+}
+
+func NewServer(cfg Config) *Server {
+	received := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "syslog_messages_total",
+			Help: "Total syslog messages received",
+		},
+		[]string{"transport"},
+	)
+
+	parseErrors := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "syslog_parse_errors_total",
+			Help: "Total syslog messages that could not be parsed",
+		},
+		[]string{"transport"},
+	)
+
+	prometheus.MustRegister(received, parseErrors)
+
+	return &Server{
+		udpAddr:      cfg.UDPAddr,
+		tcpAddr:      cfg.TCPAddr,
+		natsHandlers: cfg.NatsHandlers,
+		rateLimiter:  cfg.RateLimiter,
+		received:     received,
+		parseErrors:  parseErrors,
+		log:          cfg.Log,
+	}
+}
+
+// Run starts the configured listeners and blocks until they are stopped.
+// An empty address disables the corresponding transport.
+func (s *Server) Run() error {
+	var err error
+
+	if s.udpAddr != "" {
+		s.udpConn, err = net.ListenPacket(transportUDP, s.udpAddr)
+		if err != nil {
+			return fmt.Errorf("net.ListenPacket: %w", err)
+		}
+
+		s.wg.Add(1)
+		go s.serveUDP()
+	}
+
+	if s.tcpAddr != "" {
+		s.tcpListener, err = net.Listen(transportTCP, s.tcpAddr)
+		if err != nil {
+			return fmt.Errorf("net.Listen: %w", err)
+		}
+
+		s.wg.Add(1)
+		go s.serveTCP()
+	}
+
+	s.wg.Wait()
+	return nil
+}
+
+func (s *Server) Stop(_ context.Context) error {
+	s.closed.Store(true)
+
+	var errs []error
+	if s.udpConn != nil {
+		if err := s.udpConn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("s.udpConn.Close: %w", err))
+		}
+	}
+
+	if s.tcpListener != nil {
+		if err := s.tcpListener.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("s.tcpListener.Close: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Server) serveUDP() {
+	defer s.wg.Done()
+
+	buf := make([]byte, maxFrameSize)
+	for {
+		n, addr, err := s.udpConn.ReadFrom(buf)
+		if err != nil {
+			if s.closed.Load() {
+				return
+			}
+			s.log.Error("s.udpConn.ReadFrom", zap.Error(err))
+			continue
+		}
+
+		udpAddr, ok := addr.(*net.UDPAddr)
+		if !ok {
+			continue
+		}
+
+		s.handle(transportUDP, udpAddr.IP.String(), buf[:n])
+	}
+}
+
+func (s *Server) serveTCP() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.tcpListener.Accept()
+		if err != nil {
+			if s.closed.Load() {
+				return
+			}
+			s.log.Error("s.tcpListener.Accept", zap.Error(err))
+			continue
+		}
+
+		go s.serveConn(conn)
+	}
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	tcpAddr, ok := conn.RemoteAddr().(*net.TCPAddr)
+	if !ok {
+		return
+	}
+	ip := tcpAddr.IP.String()
+
+	reader := bufio.NewReaderSize(conn, maxFrameSize)
+	for {
+		frame, err := readFrame(reader)
+		if err != nil {
+			if !errors.Is(err, io.EOF) && !s.closed.Load() {
+				s.log.Warn("readFrame", zap.Error(err), zap.String("ip", ip))
+			}
+			return
+		}
+
+		s.handle(transportTCP, ip, frame)
+	}
+}
+
+// readFrame supports both TCP framings from RFC 6587: octet counting
+// ("LEN SP MSG") and non-transparent framing with LF as the trailer.
+func readFrame(reader *bufio.Reader) ([]byte, error) {
+	first, err := reader.Peek(1)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	if first[0] < '1' || first[0] > '9' {
+		frame, err := reader.ReadSlice('\n')
+		if errors.Is(err, io.EOF) && len(frame) > 0 {
+			return frame, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reader.ReadSlice: %w", err)
+		}
+		return frame, nil
+	}
+
+	length, err := reader.ReadString(' ')
+	if err != nil {
+		return nil, fmt.Errorf("reader.ReadString: %w", err)
+	}
+
+	size, err := strconv.Atoi(length[:len(length)-1])
+	if err != nil || size > maxFrameSize {
+		return nil, fmt.Errorf("invalid frame length %q", length)
+	}
+
+	frame := make([]byte, size)
+	if _, err = io.ReadFull(reader, frame); err != nil {
+		return nil, fmt.Errorf("io.ReadFull: %w", err)
+	}
+
+	return frame, nil
+}
+
+func (s *Server) handle(transport string, ip string, frame []byte) {
+	s.received.WithLabelValues(transport).Inc()
+
+	msg, err := Parse(frame)
+	if err != nil {
+		s.parseErrors.WithLabelValues(transport).Inc()
+		s.log.Warn("syslog.Parse", zap.Error(err), zap.String("ip", ip), zap.ByteString("frame", frame))
+		return
+	}
+
+	if ok, _ := s.rateLimiter.Allow(ip, services.RateLimitDropped); !ok {
+		return
+	}
+
+	err = s.natsHandlers.PublishSaveMessage(models.Message{
+		DeviceIP:    ip,
+		PeerIP:      ip,
+		Message:     msg.Content,
+		MessageType: msg.SeverityName(),
+		Component:   component(msg),
+		EventTime:   msg.Timestamp,
+		Attributes:  attributes(msg),
+	})
+	if err != nil {
+		s.log.Error("s.natsHandlers.PublishSaveMessage", zap.Error(err), zap.String("ip", ip))
+	}
+}
+
+// attributes keeps the header fields that are not mapped to the message itself.
+func attributes(msg Message) map[string]string {
+	res := map[string]string{
+		"facility": msg.FacilityName(),
+	}
+
+	for key, value := range map[string]string{
+		"hostname": msg.Hostname,
+		"proc_id":  msg.ProcID,
+		"msg_id":   msg.MsgID,
+	} {
+		if value != "" {
+			res[key] = value
+		}
+	}
+
+	return res
+}
+
+func component(msg Message) string {
+	name := msg.AppName
+	if name == "" {
+		name = msg.FacilityName()
+	}
+
+	if len(name) > maxComponentLen {
+		name = name[:maxComponentLen]
+	}
+
+	return name
+}
@@ -19,7 +19,13 @@ func NewNats(natsConnString string, log *zap.Logger) (*Nats, error) {
 		log: log,
 	}
 	var err error
-	natsConn.NatsConn, err = nats.Connect(natsConnString, nats.ErrorHandler(natsConn.natsErrHandler))
+	natsConn.NatsConn, err = nats.Connect(
+		natsConnString,
+		nats.ErrorHandler(natsConn.natsErrHandler),
+		// Keep reconnecting for as long as the service runs, messages are
+		// spooled to disk in the meantime.
+		nats.MaxReconnects(-1),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("nats.Connect: %w", err)
 	}
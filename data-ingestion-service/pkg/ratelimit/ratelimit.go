@@ -0,0 +1,151 @@
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Buckets untouched for this long are refilled anyway, so they are dropped
+// to keep the map bounded by the number of active keys.
+const (
+	defaultIdleTimeout = 10 * time.Minute
+	sweepEvery         = 1024
+)
+
+// Limit is a token bucket refilled with Rate tokens per second up to Burst.
+// A zero Rate means unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0
+}
+
+// Key names a bucket and the limit it is refilled with.
+type Key struct {
+	Name  string
+	Limit Limit
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Limiter keeps a token bucket per key.
+type Limiter struct {
+	mu sync.Mutex
+
+	buckets     map[string]*bucket
+	idleTimeout time.Duration
+	calls       uint64
+}
+
+type Config struct {
+	IdleTimeout time.Duration
+}
+
+func New(cfg Config) *Limiter {
+	if cfg.IdleTimeout <= 0 {
+		cfg.IdleTimeout = defaultIdleTimeout
+	}
+
+	return &Limiter{
+		buckets:     map[string]*bucket{},
+		idleTimeout: cfg.IdleTimeout,
+	}
+}
+
+// Allow takes a token from every key only if all of them have one, so a
+// message rejected by one bucket does not drain the others. Otherwise it
+// returns how long to wait until all of them are refilled enough.
+func (l *Limiter) Allow(now time.Time, keys ...Key) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%sweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	var retryAfter time.Duration
+	buckets := make([]*bucket, len(keys))
+	for i, key := range keys {
+		if key.Limit.Unlimited() {
+			continue
+		}
+
+		b := l.refill(key, now)
+		buckets[i] = b
+
+		if b.tokens < 1 {
+			wait := time.Duration(math.Ceil((1 - b.tokens) / key.Limit.Rate * float64(time.Second)))
+			retryAfter = max(retryAfter, wait)
+		}
+	}
+
+	if retryAfter > 0 {
+		return false, retryAfter
+	}
+
+	for _, b := range buckets {
+		if b != nil {
+			b.tokens--
+		}
+	}
+
+	return true, 0
+}
+
+func (l *Limiter) refill(key Key, now time.Time) *bucket {
+	burst := float64(max(key.Limit.Burst, 1))
+
+	b, ok := l.buckets[key.Name]
+	if !ok {
+		b = &bucket{tokens: burst, last: now}
+		l.buckets[key.Name] = b
+		return b
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = math.Min(burst, b.tokens+elapsed.Seconds()*key.Limit.Rate)
+		b.last = now
+	}
+
+	return b
+}
+
+func (l *Limiter) sweep(now time.Time) {
+	for name, b := range l.buckets {
+		if now.Sub(b.last) > l.idleTimeout {
+			delete(l.buckets, name)
+		}
+	}
+}
+
+// ParseLimit reads a limit written as "rate:burst" or just "rate", in which
+// case the burst equals the rate.
+func ParseLimit(s string) (Limit, error) {
+	rateStr, burstStr, hasBurst := strings.Cut(s, ":")
+
+	rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+	if err != nil {
+		return Limit{}, fmt.Errorf("strconv.ParseFloat: %w", err)
+	}
+
+	burst := int(math.Ceil(rate))
+	if hasBurst {
+		burst, err = strconv.Atoi(strings.TrimSpace(burstStr))
+		if err != nil {
+			return Limit{}, fmt.Errorf("strconv.Atoi: %w", err)
+		}
+	}
+
+	return Limit{Rate: rate, Burst: burst}, nil
+}
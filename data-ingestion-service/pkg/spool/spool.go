@@ -0,0 +1,536 @@
+package spool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	segmentExt = ".seg"
+	cursorName = "cursor"
+
+	// length (4) + written at, unix nano (8) + crc32 of data (4).
+	headerSize = 16
+
+	dirPerm  = 0o755
+	filePerm = 0o644
+)
+
+var (
+	ErrEmpty = errors.New("spool is empty")
+	ErrFull  = errors.New("spool is full")
+
+	errCorrupted = errors.New("corrupted record")
+)
+
+// Spool is an append-only on-disk queue split into segment files. Records
+// are read back in write order, the read position is kept in a cursor file
+// so acknowledged records are not replayed after a restart.
+type Spool struct {
+	mu sync.Mutex
+
+	dir          string
+	maxBytes     int64
+	maxAge       time.Duration
+	segmentBytes int64
+
+	segments []*segment
+	writer   *os.File
+
+	reader     *os.File
+	readerSeq  uint64
+	readOffset int64
+	pending    int64
+
+	expired    uint64
+	overflowed uint64
+	corrupted  uint64
+}
+
+type segment struct {
+	seq     uint64
+	size    int64
+	records int64
+}
+
+type Config struct {
+	Dir          string
+	MaxBytes     int64
+	MaxAge       time.Duration
+	SegmentBytes int64
+}
+
+type Record struct {
+	Data      []byte
+	WrittenAt time.Time
+}
+
+type Stats struct {
+	Records    int64
+	Bytes      int64
+	Expired    uint64
+	Overflowed uint64
+	Corrupted  uint64
+}
+
+func Open(cfg Config) (*Spool, error) {
+	if err := os.MkdirAll(cfg.Dir, dirPerm); err != nil {
+		return nil, fmt.Errorf("os.MkdirAll: %w", err)
+	}
+
+	s := &Spool{
+		dir:          cfg.Dir,
+		maxBytes:     cfg.MaxBytes,
+		maxAge:       cfg.MaxAge,
+		segmentBytes: cfg.SegmentBytes,
+	}
+
+	if err := s.load(); err != nil {
+		return nil, fmt.Errorf("s.load: %w", err)
+	}
+
+	return s, nil
+}
+
+func (s *Spool) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("os.ReadDir: %w", err)
+	}
+
+	seqs := make([]uint64, 0, len(entries))
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), segmentExt)
+		if !ok {
+			continue
+		}
+		seq, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	cursorSeq, cursorOffset := s.readCursor()
+
+	for i, seq := range seqs {
+		if seq < cursorSeq {
+			if err = os.Remove(s.segmentPath(seq)); err != nil {
+				return fmt.Errorf("os.Remove: %w", err)
+			}
+			continue
+		}
+
+		from := int64(0)
+		if seq == cursorSeq {
+			from = cursorOffset
+		}
+
+		end, records, err := scanSegment(s.segmentPath(seq), from)
+		if err != nil {
+			return fmt.Errorf("scanSegment: %w", err)
+		}
+
+		// Only the last segment can have a torn tail from a crash mid-write,
+		// cut it off so new records are appended right after valid ones.
+		if i == len(seqs)-1 {
+			if err = os.Truncate(s.segmentPath(seq), end); err != nil {
+				return fmt.Errorf("os.Truncate: %w", err)
+			}
+		}
+
+		if len(s.segments) == 0 {
+			s.readOffset = min(from, end)
+		}
+		s.segments = append(s.segments, &segment{seq: seq, size: end, records: records})
+	}
+
+	if len(s.segments) == 0 {
+		s.segments = append(s.segments, &segment{seq: max(cursorSeq, 1)})
+		s.readOffset = 0
+	}
+
+	return s.openWriter()
+}
+
+// Append stores the record at the end of the spool. If the spool would grow
+// past MaxBytes, the oldest segments are dropped to make room.
+func (s *Spool) Append(data []byte) error {
+	if len(data) > math.MaxUint32 {
+		return fmt.Errorf("record is too big: %d bytes", len(data))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recordSize := int64(headerSize + len(data))
+
+	active := s.segments[len(s.segments)-1]
+	if active.size > 0 && active.size+recordSize > s.segmentBytes {
+		if err := s.rotate(); err != nil {
+			return fmt.Errorf("s.rotate: %w", err)
+		}
+	}
+
+	for s.bytes()+recordSize > s.maxBytes && len(s.segments) > 1 {
+		if err := s.dropOldest(); err != nil {
+			return fmt.Errorf("s.dropOldest: %w", err)
+		}
+	}
+
+	if s.bytes()+recordSize > s.maxBytes {
+		s.overflowed++
+		return ErrFull
+	}
+
+	record := make([]byte, recordSize)
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(data)))
+	binary.BigEndian.PutUint64(record[4:12], uint64(time.Now().UnixNano()))
+	binary.BigEndian.PutUint32(record[12:16], crc32.ChecksumIEEE(data))
+	copy(record[headerSize:], data)
+
+	if _, err := s.writer.Write(record); err != nil {
+		return fmt.Errorf("s.writer.Write: %w", err)
+	}
+
+	if err := s.writer.Sync(); err != nil {
+		return fmt.Errorf("s.writer.Sync: %w", err)
+	}
+
+	active = s.segments[len(s.segments)-1]
+	active.size += recordSize
+	active.records++
+
+	return nil
+}
+
+// Next returns the oldest record without removing it, call Ack once it has
+// been handled. Records older than MaxAge are dropped on the way.
+func (s *Spool) Next() (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for {
+		first := s.segments[0]
+		if s.readOffset >= first.size {
+			if len(s.segments) == 1 {
+				return Record{}, ErrEmpty
+			}
+			if err := s.dropOldest(); err != nil {
+				return Record{}, fmt.Errorf("s.dropOldest: %w", err)
+			}
+			continue
+		}
+
+		record, size, err := s.read(first, s.readOffset)
+		if errors.Is(err, errCorrupted) {
+			s.corrupted += uint64(first.records)
+			first.records = 0
+			s.readOffset = first.size
+			continue
+		}
+		if err != nil {
+			return Record{}, fmt.Errorf("s.read: %w", err)
+		}
+
+		if s.maxAge > 0 && time.Since(record.WrittenAt) > s.maxAge {
+			s.expired++
+			s.advance(size)
+			continue
+		}
+
+		s.pending = size
+		return record, nil
+	}
+}
+
+// Ack removes the record returned by the last Next call.
+func (s *Spool) Ack() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.pending == 0 {
+		return nil
+	}
+
+	s.advance(s.pending)
+	s.pending = 0
+
+	if len(s.segments) == 1 && s.readOffset == s.segments[0].size {
+		if err := s.reset(); err != nil {
+			return fmt.Errorf("s.reset: %w", err)
+		}
+	}
+
+	return s.writeCursor()
+}
+
+func (s *Spool) Len() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.records()
+}
+
+func (s *Spool) Stats() Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return Stats{
+		Records:    s.records(),
+		Bytes:      s.bytes(),
+		Expired:    s.expired,
+		Overflowed: s.overflowed,
+		Corrupted:  s.corrupted,
+	}
+}
+
+func (s *Spool) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	if s.reader != nil {
+		if err := s.reader.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("s.reader.Close: %w", err))
+		}
+		s.reader = nil
+	}
+
+	if err := s.writer.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("s.writer.Close: %w", err))
+	}
+
+	if err := s.writeCursor(); err != nil {
+		errs = append(errs, fmt.Errorf("s.writeCursor: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *Spool) records() int64 {
+	var records int64
+	for _, seg := range s.segments {
+		records += seg.records
+	}
+	return records
+}
+
+func (s *Spool) bytes() int64 {
+	var size int64
+	for _, seg := range s.segments {
+		size += seg.size
+	}
+	return size - s.readOffset
+}
+
+func (s *Spool) advance(size int64) {
+	s.readOffset += size
+	s.segments[0].records--
+}
+
+func (s *Spool) read(seg *segment, offset int64) (Record, int64, error) {
+	if s.reader == nil || s.readerSeq != seg.seq {
+		if s.reader != nil {
+			_ = s.reader.Close()
+		}
+
+		reader, err := os.Open(s.segmentPath(seg.seq))
+		if err != nil {
+			return Record{}, 0, fmt.Errorf("os.Open: %w", err)
+		}
+		s.reader = reader
+		s.readerSeq = seg.seq
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := s.reader.ReadAt(header, offset); err != nil {
+		return Record{}, 0, fmt.Errorf("%w: %w", errCorrupted, err)
+	}
+
+	length := binary.BigEndian.Uint32(header[0:4])
+	writtenAt := int64(binary.BigEndian.Uint64(header[4:12]))
+	checksum := binary.BigEndian.Uint32(header[12:16])
+
+	if offset+headerSize+int64(length) > seg.size {
+		return Record{}, 0, errCorrupted
+	}
+
+	data := make([]byte, length)
+	if _, err := s.reader.ReadAt(data, offset+headerSize); err != nil {
+		return Record{}, 0, fmt.Errorf("%w: %w", errCorrupted, err)
+	}
+
+	if crc32.ChecksumIEEE(data) != checksum {
+		return Record{}, 0, errCorrupted
+	}
+
+	return Record{
+		Data:      data,
+		WrittenAt: time.Unix(0, writtenAt),
+	}, headerSize + int64(length), nil
+}
+
+func (s *Spool) rotate() error {
+	if err := s.writer.Close(); err != nil {
+		return fmt.Errorf("s.writer.Close: %w", err)
+	}
+
+	s.segments = append(s.segments, &segment{seq: s.segments[len(s.segments)-1].seq + 1})
+
+	return s.openWriter()
+}
+
+// dropOldest removes the first segment together with any records in it that
+// were not acknowledged yet.
+func (s *Spool) dropOldest() error {
+	first := s.segments[0]
+	s.overflowed += uint64(first.records)
+
+	if s.reader != nil && s.readerSeq == first.seq {
+		_ = s.reader.Close()
+		s.reader = nil
+	}
+
+	if err := os.Remove(s.segmentPath(first.seq)); err != nil {
+		return fmt.Errorf("os.Remove: %w", err)
+	}
+
+	s.segments = s.segments[1:]
+	s.readOffset = 0
+	s.pending = 0
+
+	return s.writeCursor()
+}
+
+// reset truncates the only segment once everything in it has been read.
+func (s *Spool) reset() error {
+	if err := s.writer.Truncate(0); err != nil {
+		return fmt.Errorf("s.writer.Truncate: %w", err)
+	}
+
+	s.segments[0].size = 0
+	s.segments[0].records = 0
+	s.readOffset = 0
+
+	return nil
+}
+
+func (s *Spool) openWriter() error {
+	active := s.segments[len(s.segments)-1]
+
+	writer, err := os.OpenFile(s.segmentPath(active.seq), os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm)
+	if err != nil {
+		return fmt.Errorf("os.OpenFile: %w", err)
+	}
+	s.writer = writer
+
+	return nil
+}
+
+func (s *Spool) segmentPath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", seq, segmentExt))
+}
+
+func (s *Spool) readCursor() (uint64, int64) {
+	data, err := os.ReadFile(filepath.Join(s.dir, cursorName))
+	if err != nil {
+		return 0, 0
+	}
+
+	seqField, offsetField, ok := strings.Cut(strings.TrimSpace(string(data)), " ")
+	if !ok {
+		return 0, 0
+	}
+
+	seq, err := strconv.ParseUint(seqField, 10, 64)
+	if err != nil {
+		return 0, 0
+	}
+
+	offset, err := strconv.ParseInt(offsetField, 10, 64)
+	if err != nil {
+		return 0, 0
+	}
+
+	return seq, offset
+}
+
+// writeCursor replaces the cursor file atomically so a crash leaves either
+// the old or the new position, never a partial one.
+func (s *Spool) writeCursor() error {
+	path := filepath.Join(s.dir, cursorName)
+	tmp := path + ".tmp"
+
+	data := fmt.Sprintf("%d %d\n", s.segments[0].seq, s.readOffset)
+	if err := os.WriteFile(tmp, []byte(data), filePerm); err != nil {
+		return fmt.Errorf("os.WriteFile: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	return nil
+}
+
+// scanSegment counts valid records from the given offset and returns the
+// offset right after the last one.
+func scanSegment(path string, from int64) (int64, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, fmt.Errorf("os.Open: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, 0, fmt.Errorf("file.Stat: %w", err)
+	}
+	from = min(from, info.Size())
+
+	if _, err = file.Seek(from, io.SeekStart); err != nil {
+		return 0, 0, fmt.Errorf("file.Seek: %w", err)
+	}
+
+	reader := bufio.NewReader(file)
+	header := make([]byte, headerSize)
+	end := from
+	records := int64(0)
+
+	for {
+		if _, err = io.ReadFull(reader, header); err != nil {
+			return end, records, nil
+		}
+
+		length := int64(binary.BigEndian.Uint32(header[0:4]))
+		if end+headerSize+length > info.Size() {
+			return end, records, nil
+		}
+
+		data := make([]byte, length)
+		if _, err = io.ReadFull(reader, data); err != nil {
+			return end, records, nil
+		}
+
+		if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(header[12:16]) {
+			return end, records, nil
+		}
+
+		end += headerSize + int64(len(data))
+		records++
+	}
+}
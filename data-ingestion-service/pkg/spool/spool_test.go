@@ -0,0 +1,200 @@
+package spool
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+// drain reads and acknowledges every record left in the spool.
+func drain(t *testing.T, s *Spool) []string {
+	t.Helper()
+
+	var records []string
+	for {
+		record, err := s.Next()
+		if errors.Is(err, ErrEmpty) {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+		records = append(records, string(record.Data))
+
+		if err = s.Ack(); err != nil {
+			t.Fatalf("Ack() error = %v", err)
+		}
+	}
+}
+
+func TestSpoolAppendNext(t *testing.T) {
+	// Every record of one byte takes recordSize on disk.
+	const recordSize = headerSize + 1
+
+	tests := []struct {
+		name       string
+		cfg        Config
+		appends    []string
+		appendErr  error
+		want       []string
+		expired    uint64
+		overflowed uint64
+	}{
+		{
+			name:    "write order",
+			cfg:     Config{MaxBytes: 1024, SegmentBytes: 1024},
+			appends: []string{"a", "b", "c"},
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:    "across segments",
+			cfg:     Config{MaxBytes: 1024, SegmentBytes: recordSize},
+			appends: []string{"a", "b", "c"},
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:       "oldest segment dropped when full",
+			cfg:        Config{MaxBytes: 2 * recordSize, SegmentBytes: recordSize},
+			appends:    []string{"a", "b", "c"},
+			want:       []string{"b", "c"},
+			overflowed: 1,
+		},
+		{
+			name:       "record larger than the spool",
+			cfg:        Config{MaxBytes: headerSize, SegmentBytes: 1024},
+			appends:    []string{"a"},
+			appendErr:  ErrFull,
+			overflowed: 1,
+		},
+		{
+			name:    "expired records dropped",
+			cfg:     Config{MaxBytes: 1024, SegmentBytes: 1024, MaxAge: time.Nanosecond},
+			appends: []string{"a", "b"},
+			expired: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.cfg.Dir = t.TempDir()
+			s, err := Open(tt.cfg)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			defer s.Close()
+
+			for _, data := range tt.appends {
+				if err = s.Append([]byte(data)); !errors.Is(err, tt.appendErr) {
+					t.Fatalf("Append(%q) error = %v, want %v", data, err, tt.appendErr)
+				}
+			}
+			if tt.cfg.MaxAge > 0 {
+				time.Sleep(time.Millisecond)
+			}
+
+			if got := drain(t, s); !slices.Equal(got, tt.want) {
+				t.Errorf("records = %q, want %q", got, tt.want)
+			}
+
+			stats := s.Stats()
+			if stats.Expired != tt.expired {
+				t.Errorf("Stats().Expired = %d, want %d", stats.Expired, tt.expired)
+			}
+			if stats.Overflowed != tt.overflowed {
+				t.Errorf("Stats().Overflowed = %d, want %d", stats.Overflowed, tt.overflowed)
+			}
+		})
+	}
+}
+
+func TestSpoolReopen(t *testing.T) {
+	tests := []struct {
+		name    string
+		appends []string
+		acked   int
+		// tornTail is written to the end of the last segment before the
+		// spool is opened again, as a crash in the middle of Append would.
+		tornTail []byte
+		want     []string
+	}{
+		{
+			name:    "nothing acknowledged",
+			appends: []string{"a", "b", "c"},
+			want:    []string{"a", "b", "c"},
+		},
+		{
+			name:    "acknowledged records are not replayed",
+			appends: []string{"a", "b", "c"},
+			acked:   2,
+			want:    []string{"c"},
+		},
+		{
+			name:    "everything acknowledged",
+			appends: []string{"a", "b", "c"},
+			acked:   3,
+		},
+		{
+			name:     "torn tail cut off",
+			appends:  []string{"a", "b"},
+			tornTail: []byte{0, 0, 0, 9, 1, 2},
+			want:     []string{"a", "b", "d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Config{Dir: t.TempDir(), MaxBytes: 1024, SegmentBytes: 1024}
+
+			s, err := Open(cfg)
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			for _, data := range tt.appends {
+				if err = s.Append([]byte(data)); err != nil {
+					t.Fatalf("Append(%q) error = %v", data, err)
+				}
+			}
+			for range tt.acked {
+				if _, err = s.Next(); err != nil {
+					t.Fatalf("Next() error = %v", err)
+				}
+				if err = s.Ack(); err != nil {
+					t.Fatalf("Ack() error = %v", err)
+				}
+			}
+			if err = s.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if tt.tornTail != nil {
+				segment, err := os.OpenFile(filepath.Join(cfg.Dir, "00000000000000000001"+segmentExt), os.O_WRONLY|os.O_APPEND, filePerm)
+				if err != nil {
+					t.Fatalf("os.OpenFile() error = %v", err)
+				}
+				if _, err = segment.Write(tt.tornTail); err != nil {
+					t.Fatalf("segment.Write() error = %v", err)
+				}
+				segment.Close()
+			}
+
+			s, err = Open(cfg)
+			if err != nil {
+				t.Fatalf("Open() again error = %v", err)
+			}
+			defer s.Close()
+
+			if tt.tornTail != nil {
+				if err = s.Append([]byte("d")); err != nil {
+					t.Fatalf("Append() after reopen error = %v", err)
+				}
+			}
+
+			if got := drain(t, s); !slices.Equal(got, tt.want) {
+				t.Errorf("records = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,475 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: apiquarantine.proto
+
+package pbapiquarantine
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Source struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	Messages      uint64                 `protobuf:"varint,2,opt,name=Messages,proto3" json:"Messages,omitempty"`
+	LastSeenAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=LastSeenAt,proto3" json:"LastSeenAt,omitempty"`
+	LastMessage   string                 `protobuf:"bytes,4,opt,name=LastMessage,proto3" json:"LastMessage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Source) Reset() {
+	*x = Source{}
+	mi := &file_apiquarantine_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Source) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Source) ProtoMessage() {}
+
+func (x *Source) ProtoReflect() protoreflect.Message {
+	mi := &file_apiquarantine_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Source.ProtoReflect.Descriptor instead.
+func (*Source) Descriptor() ([]byte, []int) {
+	return file_apiquarantine_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Source) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Source) GetMessages() uint64 {
+	if x != nil {
+		return x.Messages
+	}
+	return 0
+}
+
+func (x *Source) GetLastSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return nil
+}
+
+func (x *Source) GetLastMessage() string {
+	if x != nil {
+		return x.LastMessage
+	}
+	return ""
+}
+
+type ListResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sources       []*Source              `protobuf:"bytes,1,rep,name=Sources,proto3" json:"Sources,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResp) Reset() {
+	*x = ListResp{}
+	mi := &file_apiquarantine_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResp) ProtoMessage() {}
+
+func (x *ListResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apiquarantine_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResp.ProtoReflect.Descriptor instead.
+func (*ListResp) Descriptor() ([]byte, []int) {
+	return file_apiquarantine_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ListResp) GetSources() []*Source {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *ListResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type RegisterReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,3,opt,name=DeviceType,proto3" json:"DeviceType,omitempty"`
+	Responsible   []int32                `protobuf:"varint,4,rep,packed,name=Responsible,proto3" json:"Responsible,omitempty"`
+	Replay        bool                   `protobuf:"varint,5,opt,name=Replay,proto3" json:"Replay,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterReq) Reset() {
+	*x = RegisterReq{}
+	mi := &file_apiquarantine_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterReq) ProtoMessage() {}
+
+func (x *RegisterReq) ProtoReflect() protoreflect.Message {
+	mi := &file_apiquarantine_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterReq.ProtoReflect.Descriptor instead.
+func (*RegisterReq) Descriptor() ([]byte, []int) {
+	return file_apiquarantine_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *RegisterReq) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *RegisterReq) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RegisterReq) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *RegisterReq) GetResponsible() []int32 {
+	if x != nil {
+		return x.Responsible
+	}
+	return nil
+}
+
+func (x *RegisterReq) GetReplay() bool {
+	if x != nil {
+		return x.Replay
+	}
+	return false
+}
+
+type RegisterResp struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceID int32                  `protobuf:"varint,1,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Replayed uint64                 `protobuf:"varint,2,opt,name=Replayed,proto3" json:"Replayed,omitempty"`
+	Error    string                 `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
+	// Ingestion key of the new device, it is returned only once.
+	APIKey        string `protobuf:"bytes,4,opt,name=APIKey,proto3" json:"APIKey,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterResp) Reset() {
+	*x = RegisterResp{}
+	mi := &file_apiquarantine_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterResp) ProtoMessage() {}
+
+func (x *RegisterResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apiquarantine_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterResp.ProtoReflect.Descriptor instead.
+func (*RegisterResp) Descriptor() ([]byte, []int) {
+	return file_apiquarantine_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RegisterResp) GetDeviceID() int32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *RegisterResp) GetReplayed() uint64 {
+	if x != nil {
+		return x.Replayed
+	}
+	return 0
+}
+
+func (x *RegisterResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *RegisterResp) GetAPIKey() string {
+	if x != nil {
+		return x.APIKey
+	}
+	return ""
+}
+
+type ReplayReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayReq) Reset() {
+	*x = ReplayReq{}
+	mi := &file_apiquarantine_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayReq) ProtoMessage() {}
+
+func (x *ReplayReq) ProtoReflect() protoreflect.Message {
+	mi := &file_apiquarantine_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayReq.ProtoReflect.Descriptor instead.
+func (*ReplayReq) Descriptor() ([]byte, []int) {
+	return file_apiquarantine_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReplayReq) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type ReplayResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Replayed      uint64                 `protobuf:"varint,1,opt,name=Replayed,proto3" json:"Replayed,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayResp) Reset() {
+	*x = ReplayResp{}
+	mi := &file_apiquarantine_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayResp) ProtoMessage() {}
+
+func (x *ReplayResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apiquarantine_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayResp.ProtoReflect.Descriptor instead.
+func (*ReplayResp) Descriptor() ([]byte, []int) {
+	return file_apiquarantine_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReplayResp) GetReplayed() uint64 {
+	if x != nil {
+		return x.Replayed
+	}
+	return 0
+}
+
+func (x *ReplayResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_apiquarantine_proto protoreflect.FileDescriptor
+
+const file_apiquarantine_proto_rawDesc = "" +
+	"\n" +
+	"\x13apiquarantine.proto\x12\fpbquarantine\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9c\x01\n" +
+	"\x06Source\x12\x18\n" +
+	"\aAddress\x18\x01 \x01(\tR\aAddress\x12\x1a\n" +
+	"\bMessages\x18\x02 \x01(\x04R\bMessages\x12:\n" +
+	"\n" +
+	"LastSeenAt\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"LastSeenAt\x12 \n" +
+	"\vLastMessage\x18\x04 \x01(\tR\vLastMessage\"P\n" +
+	"\bListResp\x12.\n" +
+	"\aSources\x18\x01 \x03(\v2\x14.pbquarantine.SourceR\aSources\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"\x95\x01\n" +
+	"\vRegisterReq\x12\x18\n" +
+	"\aAddress\x18\x01 \x01(\tR\aAddress\x12\x12\n" +
+	"\x04Name\x18\x02 \x01(\tR\x04Name\x12\x1e\n" +
+	"\n" +
+	"DeviceType\x18\x03 \x01(\tR\n" +
+	"DeviceType\x12 \n" +
+	"\vResponsible\x18\x04 \x03(\x05R\vResponsible\x12\x16\n" +
+	"\x06Replay\x18\x05 \x01(\bR\x06Replay\"t\n" +
+	"\fRegisterResp\x12\x1a\n" +
+	"\bDeviceID\x18\x01 \x01(\x05R\bDeviceID\x12\x1a\n" +
+	"\bReplayed\x18\x02 \x01(\x04R\bReplayed\x12\x14\n" +
+	"\x05Error\x18\x03 \x01(\tR\x05Error\x12\x16\n" +
+	"\x06APIKey\x18\x04 \x01(\tR\x06APIKey\"%\n" +
+	"\tReplayReq\x12\x18\n" +
+	"\aAddress\x18\x01 \x01(\tR\aAddress\">\n" +
+	"\n" +
+	"ReplayResp\x12\x1a\n" +
+	"\bReplayed\x18\x01 \x01(\x04R\bReplayed\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05ErrorB\x13Z\x11.;pbapiquarantineb\x06proto3"
+
+var (
+	file_apiquarantine_proto_rawDescOnce sync.Once
+	file_apiquarantine_proto_rawDescData []byte
+)
+
+func file_apiquarantine_proto_rawDescGZIP() []byte {
+	file_apiquarantine_proto_rawDescOnce.Do(func() {
+		file_apiquarantine_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_apiquarantine_proto_rawDesc), len(file_apiquarantine_proto_rawDesc)))
+	})
+	return file_apiquarantine_proto_rawDescData
+}
+
+var file_apiquarantine_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_apiquarantine_proto_goTypes = []any{
+	(*Source)(nil),                // 0: pbquarantine.Source
+	(*ListResp)(nil),              // 1: pbquarantine.ListResp
+	(*RegisterReq)(nil),           // 2: pbquarantine.RegisterReq
+	(*RegisterResp)(nil),          // 3: pbquarantine.RegisterResp
+	(*ReplayReq)(nil),             // 4: pbquarantine.ReplayReq
+	(*ReplayResp)(nil),            // 5: pbquarantine.ReplayResp
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
+}
+var file_apiquarantine_proto_depIdxs = []int32{
+	6, // 0: pbquarantine.Source.LastSeenAt:type_name -> google.protobuf.Timestamp
+	0, // 1: pbquarantine.ListResp.Sources:type_name -> pbquarantine.Source
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_apiquarantine_proto_init() }
+func file_apiquarantine_proto_init() {
+	if File_apiquarantine_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_apiquarantine_proto_rawDesc), len(file_apiquarantine_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_apiquarantine_proto_goTypes,
+		DependencyIndexes: file_apiquarantine_proto_depIdxs,
+		MessageInfos:      file_apiquarantine_proto_msgTypes,
+	}.Build()
+	File_apiquarantine_proto = out.File
+	file_apiquarantine_proto_goTypes = nil
+	file_apiquarantine_proto_depIdxs = nil
+}
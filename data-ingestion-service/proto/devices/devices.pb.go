@@ -166,6 +166,215 @@ func (x *ReadResp) GetError() string {
 	return ""
 }
 
+type CreateReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        *Device                `protobuf:"bytes,1,opt,name=Device,proto3" json:"Device,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReq) Reset() {
+	*x = CreateReq{}
+	mi := &file_devices_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReq) ProtoMessage() {}
+
+func (x *CreateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_devices_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReq.ProtoReflect.Descriptor instead.
+func (*CreateReq) Descriptor() ([]byte, []int) {
+	return file_devices_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateReq) GetDevice() *Device {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+type CreateResp struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Created *Device                `protobuf:"bytes,1,opt,name=Created,proto3" json:"Created,omitempty"`
+	Error   string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	// Ingestion key of the new device, it is returned only once.
+	APIKey        string `protobuf:"bytes,3,opt,name=APIKey,proto3" json:"APIKey,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResp) Reset() {
+	*x = CreateResp{}
+	mi := &file_devices_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResp) ProtoMessage() {}
+
+func (x *CreateResp) ProtoReflect() protoreflect.Message {
+	mi := &file_devices_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResp.ProtoReflect.Descriptor instead.
+func (*CreateResp) Descriptor() ([]byte, []int) {
+	return file_devices_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateResp) GetCreated() *Device {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *CreateResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CreateResp) GetAPIKey() string {
+	if x != nil {
+		return x.APIKey
+	}
+	return ""
+}
+
+type KeyHash struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceID      int32                  `protobuf:"varint,1,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Hash          string                 `protobuf:"bytes,2,opt,name=Hash,proto3" json:"Hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyHash) Reset() {
+	*x = KeyHash{}
+	mi := &file_devices_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyHash) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyHash) ProtoMessage() {}
+
+func (x *KeyHash) ProtoReflect() protoreflect.Message {
+	mi := &file_devices_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyHash.ProtoReflect.Descriptor instead.
+func (*KeyHash) Descriptor() ([]byte, []int) {
+	return file_devices_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *KeyHash) GetDeviceID() int32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *KeyHash) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type ReadKeysResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*KeyHash             `protobuf:"bytes,1,rep,name=Keys,proto3" json:"Keys,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadKeysResp) Reset() {
+	*x = ReadKeysResp{}
+	mi := &file_devices_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadKeysResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadKeysResp) ProtoMessage() {}
+
+func (x *ReadKeysResp) ProtoReflect() protoreflect.Message {
+	mi := &file_devices_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadKeysResp.ProtoReflect.Descriptor instead.
+func (*ReadKeysResp) Descriptor() ([]byte, []int) {
+	return file_devices_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ReadKeysResp) GetKeys() []*KeyHash {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *ReadKeysResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 var File_devices_proto protoreflect.FileDescriptor
 
 const file_devices_proto_rawDesc = "" +
@@ -183,6 +392,19 @@ const file_devices_proto_rawDesc = "" +
 	"\tUpdatedAt\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tUpdatedAt\"M\n" +
 	"\bReadResp\x12+\n" +
 	"\aDevices\x18\x01 \x03(\v2\x11.pbdevices.DeviceR\aDevices\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"6\n" +
+	"\tCreateReq\x12)\n" +
+	"\x06Device\x18\x01 \x01(\v2\x11.pbdevices.DeviceR\x06Device\"g\n" +
+	"\n" +
+	"CreateResp\x12+\n" +
+	"\aCreated\x18\x01 \x01(\v2\x11.pbdevices.DeviceR\aCreated\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\x12\x16\n" +
+	"\x06APIKey\x18\x03 \x01(\tR\x06APIKey\"9\n" +
+	"\aKeyHash\x12\x1a\n" +
+	"\bDeviceID\x18\x01 \x01(\x05R\bDeviceID\x12\x12\n" +
+	"\x04Hash\x18\x02 \x01(\tR\x04Hash\"L\n" +
+	"\fReadKeysResp\x12&\n" +
+	"\x04Keys\x18\x01 \x03(\v2\x12.pbdevices.KeyHashR\x04Keys\x12\x14\n" +
 	"\x05Error\x18\x02 \x01(\tR\x05ErrorB\rZ\v.;pbdevicesb\x06proto3"
 
 var (
@@ -197,21 +419,28 @@ func file_devices_proto_rawDescGZIP() []byte {
 	return file_devices_proto_rawDescData
 }
 
-var file_devices_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_devices_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_devices_proto_goTypes = []any{
 	(*Device)(nil),                // 0: pbdevices.Device
 	(*ReadResp)(nil),              // 1: pbdevices.ReadResp
-	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+	(*CreateReq)(nil),             // 2: pbdevices.CreateReq
+	(*CreateResp)(nil),            // 3: pbdevices.CreateResp
+	(*KeyHash)(nil),               // 4: pbdevices.KeyHash
+	(*ReadKeysResp)(nil),          // 5: pbdevices.ReadKeysResp
+	(*timestamppb.Timestamp)(nil), // 6: google.protobuf.Timestamp
 }
 var file_devices_proto_depIdxs = []int32{
-	2, // 0: pbdevices.Device.CreatedAt:type_name -> google.protobuf.Timestamp
-	2, // 1: pbdevices.Device.UpdatedAt:type_name -> google.protobuf.Timestamp
+	6, // 0: pbdevices.Device.CreatedAt:type_name -> google.protobuf.Timestamp
+	6, // 1: pbdevices.Device.UpdatedAt:type_name -> google.protobuf.Timestamp
 	0, // 2: pbdevices.ReadResp.Devices:type_name -> pbdevices.Device
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	0, // 3: pbdevices.CreateReq.Device:type_name -> pbdevices.Device
+	0, // 4: pbdevices.CreateResp.Created:type_name -> pbdevices.Device
+	4, // 5: pbdevices.ReadKeysResp.Keys:type_name -> pbdevices.KeyHash
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_devices_proto_init() }
@@ -225,7 +454,7 @@ func file_devices_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_devices_proto_rawDesc), len(file_devices_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -9,6 +9,7 @@ package pbmessages
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -22,12 +23,19 @@ const (
 )
 
 type MessageSave struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	DeviceID      int32                  `protobuf:"varint,2,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
-	Message       string                 `protobuf:"bytes,3,opt,name=Message,proto3" json:"Message,omitempty"`
-	MessageType   string                 `protobuf:"bytes,4,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
-	Component     string                 `protobuf:"bytes,5,opt,name=Component,proto3" json:"Component,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	ID          int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	DeviceID    int32                  `protobuf:"varint,2,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Message     string                 `protobuf:"bytes,3,opt,name=Message,proto3" json:"Message,omitempty"`
+	MessageType string                 `protobuf:"bytes,4,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
+	Component   string                 `protobuf:"bytes,5,opt,name=Component,proto3" json:"Component,omitempty"`
+	// Time reported by the device, unset if it did not send one.
+	EventTime *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=EventTime,proto3" json:"EventTime,omitempty"`
+	// Time data-ingestion-service received the message.
+	ReceivedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=ReceivedAt,proto3" json:"ReceivedAt,omitempty"`
+	Attributes map[string]string      `protobuf:"bytes,8,rep,name=Attributes,proto3" json:"Attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Set by the sender to deduplicate retries, unique per device.
+	MessageID     string `protobuf:"bytes,9,opt,name=MessageID,proto3" json:"MessageID,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -97,18 +105,57 @@ func (x *MessageSave) GetComponent() string {
 	return ""
 }
 
+func (x *MessageSave) GetEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTime
+	}
+	return nil
+}
+
+func (x *MessageSave) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *MessageSave) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *MessageSave) GetMessageID() string {
+	if x != nil {
+		return x.MessageID
+	}
+	return ""
+}
+
 var File_messages_proto protoreflect.FileDescriptor
 
 const file_messages_proto_rawDesc = "" +
 	"\n" +
 	"\x0emessages.proto\x12\n" +
-	"pbmessages\"\x93\x01\n" +
+	"pbmessages\x1a\x1fgoogle/protobuf/timestamp.proto\"\xaf\x03\n" +
 	"\vMessageSave\x12\x0e\n" +
 	"\x02ID\x18\x01 \x01(\x05R\x02ID\x12\x1a\n" +
 	"\bDeviceID\x18\x02 \x01(\x05R\bDeviceID\x12\x18\n" +
 	"\aMessage\x18\x03 \x01(\tR\aMessage\x12 \n" +
 	"\vMessageType\x18\x04 \x01(\tR\vMessageType\x12\x1c\n" +
-	"\tComponent\x18\x05 \x01(\tR\tComponentB\x0eZ\f.;pbmessagesb\x06proto3"
+	"\tComponent\x18\x05 \x01(\tR\tComponent\x128\n" +
+	"\tEventTime\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tEventTime\x12:\n" +
+	"\n" +
+	"ReceivedAt\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"ReceivedAt\x12G\n" +
+	"\n" +
+	"Attributes\x18\b \x03(\v2'.pbmessages.MessageSave.AttributesEntryR\n" +
+	"Attributes\x12\x1c\n" +
+	"\tMessageID\x18\t \x01(\tR\tMessageID\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x0eZ\f.;pbmessagesb\x06proto3"
 
 var (
 	file_messages_proto_rawDescOnce sync.Once
@@ -122,16 +169,21 @@ func file_messages_proto_rawDescGZIP() []byte {
 	return file_messages_proto_rawDescData
 }
 
-var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_messages_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_messages_proto_goTypes = []any{
-	(*MessageSave)(nil), // 0: pbmessages.MessageSave
+	(*MessageSave)(nil),           // 0: pbmessages.MessageSave
+	nil,                           // 1: pbmessages.MessageSave.AttributesEntry
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
 }
 var file_messages_proto_depIdxs = []int32{
-	0, // [0:0] is the sub-list for method output_type
-	0, // [0:0] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	2, // 0: pbmessages.MessageSave.EventTime:type_name -> google.protobuf.Timestamp
+	2, // 1: pbmessages.MessageSave.ReceivedAt:type_name -> google.protobuf.Timestamp
+	1, // 2: pbmessages.MessageSave.Attributes:type_name -> pbmessages.MessageSave.AttributesEntry
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_messages_proto_init() }
@@ -145,7 +197,7 @@ func file_messages_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_messages_proto_rawDesc), len(file_messages_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   1,
+			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,198 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: quarantine.proto
+
+package pbquarantine
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type QuarantinedMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=Address,proto3" json:"Address,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=Message,proto3" json:"Message,omitempty"`
+	MessageType   string                 `protobuf:"bytes,3,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
+	Component     string                 `protobuf:"bytes,4,opt,name=Component,proto3" json:"Component,omitempty"`
+	ReceivedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=ReceivedAt,proto3" json:"ReceivedAt,omitempty"`
+	EventTime     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=EventTime,proto3" json:"EventTime,omitempty"`
+	Attributes    map[string]string      `protobuf:"bytes,7,rep,name=Attributes,proto3" json:"Attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	MessageID     string                 `protobuf:"bytes,8,opt,name=MessageID,proto3" json:"MessageID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuarantinedMessage) Reset() {
+	*x = QuarantinedMessage{}
+	mi := &file_quarantine_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuarantinedMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuarantinedMessage) ProtoMessage() {}
+
+func (x *QuarantinedMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_quarantine_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuarantinedMessage.ProtoReflect.Descriptor instead.
+func (*QuarantinedMessage) Descriptor() ([]byte, []int) {
+	return file_quarantine_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *QuarantinedMessage) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *QuarantinedMessage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *QuarantinedMessage) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *QuarantinedMessage) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *QuarantinedMessage) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *QuarantinedMessage) GetEventTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EventTime
+	}
+	return nil
+}
+
+func (x *QuarantinedMessage) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *QuarantinedMessage) GetMessageID() string {
+	if x != nil {
+		return x.MessageID
+	}
+	return ""
+}
+
+var File_quarantine_proto protoreflect.FileDescriptor
+
+const file_quarantine_proto_rawDesc = "" +
+	"\n" +
+	"\x10quarantine.proto\x12\fpbquarantine\x1a\x1fgoogle/protobuf/timestamp.proto\"\xad\x03\n" +
+	"\x12QuarantinedMessage\x12\x18\n" +
+	"\aAddress\x18\x01 \x01(\tR\aAddress\x12\x18\n" +
+	"\aMessage\x18\x02 \x01(\tR\aMessage\x12 \n" +
+	"\vMessageType\x18\x03 \x01(\tR\vMessageType\x12\x1c\n" +
+	"\tComponent\x18\x04 \x01(\tR\tComponent\x12:\n" +
+	"\n" +
+	"ReceivedAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"ReceivedAt\x128\n" +
+	"\tEventTime\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tEventTime\x12P\n" +
+	"\n" +
+	"Attributes\x18\a \x03(\v20.pbquarantine.QuarantinedMessage.AttributesEntryR\n" +
+	"Attributes\x12\x1c\n" +
+	"\tMessageID\x18\b \x01(\tR\tMessageID\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x10Z\x0e.;pbquarantineb\x06proto3"
+
+var (
+	file_quarantine_proto_rawDescOnce sync.Once
+	file_quarantine_proto_rawDescData []byte
+)
+
+func file_quarantine_proto_rawDescGZIP() []byte {
+	file_quarantine_proto_rawDescOnce.Do(func() {
+		file_quarantine_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_quarantine_proto_rawDesc), len(file_quarantine_proto_rawDesc)))
+	})
+	return file_quarantine_proto_rawDescData
+}
+
+var file_quarantine_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_quarantine_proto_goTypes = []any{
+	(*QuarantinedMessage)(nil),    // 0: pbquarantine.QuarantinedMessage
+	nil,                           // 1: pbquarantine.QuarantinedMessage.AttributesEntry
+	(*timestamppb.Timestamp)(nil), // 2: google.protobuf.Timestamp
+}
+var file_quarantine_proto_depIdxs = []int32{
+	2, // 0: pbquarantine.QuarantinedMessage.ReceivedAt:type_name -> google.protobuf.Timestamp
+	2, // 1: pbquarantine.QuarantinedMessage.EventTime:type_name -> google.protobuf.Timestamp
+	1, // 2: pbquarantine.QuarantinedMessage.Attributes:type_name -> pbquarantine.QuarantinedMessage.AttributesEntry
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_quarantine_proto_init() }
+func file_quarantine_proto_init() {
+	if File_quarantine_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_quarantine_proto_rawDesc), len(file_quarantine_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_quarantine_proto_goTypes,
+		DependencyIndexes: file_quarantine_proto_depIdxs,
+		MessageInfos:      file_quarantine_proto_msgTypes,
+	}.Build()
+	File_quarantine_proto = out.File
+	file_quarantine_proto_goTypes = nil
+	file_quarantine_proto_depIdxs = nil
+}
@@ -37,10 +37,41 @@ type AppConfig struct {
 type NatsConfig struct {
 	URL     string        `env:"NATS_URL"`
 	Timeout time.Duration `env:"NATS_TIMEOUT"`
+	// Retries with the same client message ID within it are dropped by the stream.
+	DuplicatesWindow time.Duration `env:"NATS_DUPLICATES_WINDOW" envDefault:"2m"`
+	// Checkpoints of windowed rules not saved within it are dropped.
+	RuleWindowsTTL time.Duration `env:"NATS_RULE_WINDOWS_TTL" envDefault:"24h"`
+	// Sent notifications not repeated within it are dropped with the count
+	// of their suppressed repeats.
+	SendedNotificationsTTL time.Duration `env:"NATS_SENDED_NOTIFICATIONS_TTL" envDefault:"24h"`
+	// A lease not renewed within it is taken over by another replica.
+	LeasesTTL time.Duration `env:"NATS_LEASES_TTL" envDefault:"1m"`
 }
 
 type ServiceConfig struct {
 	NotificationPeriod time.Duration `env:"SERVICE_NOTIFICATION_PERIOD,required"`
+	// The tags are also compared with the stored ones this often, a replica
+	// that missed a broadcast catches up then.
+	TagsVersionCheckPeriod time.Duration `env:"SERVICE_TAGS_VERSION_CHECK_PERIOD" envDefault:"1m"`
+	// An alert whose rule changes state FlapThreshold times within
+	// FlapWindow is FLAPPING, 0 turns it off. Flapping alerts are checked
+	// for having settled every FlapCheckPeriod by the replica holding their
+	// lease.
+	FlapWindow      time.Duration `env:"SERVICE_FLAP_WINDOW" envDefault:"10m"`
+	FlapThreshold   int           `env:"SERVICE_FLAP_THRESHOLD" envDefault:"6"`
+	FlapCheckPeriod time.Duration `env:"SERVICE_FLAP_CHECK_PERIOD" envDefault:"1m"`
+	// Silences are also reloaded this often, a replica that missed a change
+	// catches up then.
+	SilencesReloadPeriod time.Duration `env:"SERVICE_SILENCES_RELOAD_PERIOD" envDefault:"1m"`
+	// Rules are also reloaded this often, a replica that missed a change
+	// catches up then.
+	RulesReloadPeriod time.Duration `env:"SERVICE_RULES_RELOAD_PERIOD" envDefault:"1m"`
+	// Heartbeats are checked this often by the replica holding their lease,
+	// it should be well below NATS_LEASES_TTL to keep the lease.
+	HeartbeatCheckPeriod time.Duration `env:"SERVICE_HEARTBEAT_CHECK_PERIOD" envDefault:"15s"`
+	// The counts of repeats suppressed within a notification period that
+	// ended without another notification are sent this often.
+	SuppressedFlushPeriod time.Duration `env:"SERVICE_SUPPRESSED_FLUSH_PERIOD" envDefault:"1m"`
 }
 
 type ServerConfig struct {
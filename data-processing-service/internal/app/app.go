@@ -5,10 +5,15 @@ import (
 	"fmt"
 
 	"data-processing-service/config"
+	"data-processing-service/internal/repo/kv"
 	"data-processing-service/internal/repo/pg"
 	"data-processing-service/internal/services"
 	"data-processing-service/internal/transport/http"
+	alertslistener "data-processing-service/internal/transport/nats/alerts"
+	heartbeatslistener "data-processing-service/internal/transport/nats/heartbeats"
 	messagelisteners "data-processing-service/internal/transport/nats/messages"
+	ruleslistener "data-processing-service/internal/transport/nats/rules"
+	silenceslistener "data-processing-service/internal/transport/nats/silences"
 	tagslistener "data-processing-service/internal/transport/nats/tags"
 	"data-processing-service/pkg/closer"
 	"data-processing-service/pkg/logger"
@@ -50,15 +55,49 @@ func Run(ctx context.Context, cfg *config.Config, stop context.CancelFunc) {
 
 	tagsRepo := pg.NewTagsRepo(postgresDB, log)
 	messagesRepo := pg.NewMessagesRepo(postgresDB, log)
+	rulesRepo := pg.NewRulesRepo(postgresDB, log)
+	alertsRepo := pg.NewAlertsRepo(postgresDB, log)
+	silencesRepo := pg.NewSilencesRepo(postgresDB, log)
+	heartbeatsRepo := pg.NewHeartbeatsRepo(postgresDB, log)
+	baselinesRepo := pg.NewBaselinesRepo(postgresDB, log)
+
+	windowsRepo, err := kv.NewWindowsRepo(nats.Js, cfg.Nats.RuleWindowsTTL)
+	if err != nil {
+		log.Fatal(fmt.Errorf("kv.NewWindowsRepo: %w", err).Error())
+	}
+
+	notificationsRepo, err := kv.NewNotificationsRepo(nats.Js, cfg.Nats.SendedNotificationsTTL)
+	if err != nil {
+		log.Fatal(fmt.Errorf("kv.NewNotificationsRepo: %w", err).Error())
+	}
+
+	leasesRepo, err := kv.NewLeasesRepo(nats.Js, cfg.Nats.LeasesTTL)
+	if err != nil {
+		log.Fatal(fmt.Errorf("kv.NewLeasesRepo: %w", err).Error())
+	}
 
 	messagesService := services.NewMessagesService(services.Config{
 		MessageRepo:        messagesRepo,
 		TagRepo:            tagsRepo,
+		RuleRepo:           rulesRepo,
+		AlertRepo:          alertsRepo,
+		SilenceRepo:        silencesRepo,
+		HeartbeatRepo:      heartbeatsRepo,
+		LeaseRepo:          leasesRepo,
+		WindowRepo:         windowsRepo,
+		BaselineRepo:       baselinesRepo,
+		NotificationRepo:   notificationsRepo,
 		Log:                log,
 		NotificationPeriod: cfg.Service.NotificationPeriod,
+		FlapWindow:         cfg.Service.FlapWindow,
+		FlapThreshold:      cfg.Service.FlapThreshold,
 	})
 
 	tagsService := services.NewTagsService(tagsRepo, messagesService)
+	rulesService := services.NewRulesService(rulesRepo, baselinesRepo, messagesService)
+	alertsService := services.NewAlertsService(alertsRepo)
+	silencesService := services.NewSilencesService(silencesRepo, messagesService)
+	heartbeatsService := services.NewHeartbeatsService(heartbeatsRepo)
 
 	messagesListeners := messagelisteners.NewListener(messagelisteners.Config{
 		NatsConn:        nats.NatsConn,
@@ -66,12 +105,47 @@ func Run(ctx context.Context, cfg *config.Config, stop context.CancelFunc) {
 		MessagesService: messagesService,
 		Timeout:         cfg.Nats.Timeout,
 		Log:             log,
+
+		DuplicatesWindow:      cfg.Nats.DuplicatesWindow,
+		FlapCheckPeriod:       cfg.Service.FlapCheckPeriod,
+		HeartbeatCheckPeriod:  cfg.Service.HeartbeatCheckPeriod,
+		SuppressedFlushPeriod: cfg.Service.SuppressedFlushPeriod,
 	})
 
 	tagsListener := tagslistener.NewListener(tagslistener.Config{
-		NatsConn:    nats.NatsConn,
-		TagsService: tagsService,
-		Log:         log,
+		NatsConn:           nats.NatsConn,
+		TagsService:        tagsService,
+		MessagesService:    messagesService,
+		VersionCheckPeriod: cfg.Service.TagsVersionCheckPeriod,
+		Log:                log,
+	})
+
+	rulesListener := ruleslistener.NewListener(ruleslistener.Config{
+		NatsConn:        nats.NatsConn,
+		RulesService:    rulesService,
+		MessagesService: messagesService,
+		ReloadPeriod:    cfg.Service.RulesReloadPeriod,
+		Log:             log,
+	})
+
+	alertsListener := alertslistener.NewListener(alertslistener.Config{
+		NatsConn:      nats.NatsConn,
+		AlertsService: alertsService,
+		Log:           log,
+	})
+
+	silencesListener := silenceslistener.NewListener(silenceslistener.Config{
+		NatsConn:        nats.NatsConn,
+		SilencesService: silencesService,
+		MessagesService: messagesService,
+		ReloadPeriod:    cfg.Service.SilencesReloadPeriod,
+		Log:             log,
+	})
+
+	heartbeatsListener := heartbeatslistener.NewListener(heartbeatslistener.Config{
+		NatsConn:          nats.NatsConn,
+		HeartbeatsService: heartbeatsService,
+		Log:               log,
 	})
 
 	httpServer := http.NewServer(http.Config{
@@ -105,6 +179,34 @@ func Run(ctx context.Context, cfg *config.Config, stop context.CancelFunc) {
 		}
 	}()
 
+	go func() {
+		if err = rulesListener.Listen(); err != nil {
+			log.Error(fmt.Errorf("error occurred while running rulesListener: %w", err).Error())
+			stop()
+		}
+	}()
+
+	go func() {
+		if err = alertsListener.Listen(); err != nil {
+			log.Error(fmt.Errorf("error occurred while running alertsListener: %w", err).Error())
+			stop()
+		}
+	}()
+
+	go func() {
+		if err = silencesListener.Listen(); err != nil {
+			log.Error(fmt.Errorf("error occurred while running silencesListener: %w", err).Error())
+			stop()
+		}
+	}()
+
+	go func() {
+		if err = heartbeatsListener.Listen(); err != nil {
+			log.Error(fmt.Errorf("error occurred while running heartbeatsListener: %w", err).Error())
+			stop()
+		}
+	}()
+
 	log.Info("start nats listeners", zap.String("listen_on", cfg.Nats.URL))
 
 	// Shutdown
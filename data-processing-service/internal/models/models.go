@@ -2,6 +2,9 @@ package models
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"regexp"
 	"time"
 )
@@ -27,6 +30,174 @@ func (t Tag) Compare(val string) bool {
 	return t.CompareFunc(val, t.Value)
 }
 
+// Rule joins its conditions with Op ("and" or "or"). A DeviceId of 0 applies
+// the rule to every device. With a Window the matching messages are only
+// aggregated and the rule fires once the aggregate crosses the threshold.
+// With an Anomaly instead the rule fires once a value deviates from the
+// baseline learned for the device.
+type Rule struct {
+	ID            int32                  `db:"id"`
+	Name          string                 `db:"name"`
+	DeviceId      int32                  `db:"device_id"`
+	Op            string                 `db:"op"`
+	Conditions    SqlJsonbRuleConditions `db:"conditions"`
+	Window        *RuleWindow            `db:"window"`
+	Anomaly       *RuleAnomaly           `db:"anomaly"`
+	Subject       string                 `db:"subject"`
+	SeverityLevel string                 `db:"severity_level"`
+	CreatedAt     *time.Time             `db:"created_at"`
+	UpdatedAt     *time.Time             `db:"updated_at"`
+}
+
+// RuleCondition is either a group of conditions joined by Op or, with Op
+// empty, a check of one message field. Field is "message", "component",
+// "message_type" or "attributes.<name>". Without CompareType the check is
+// that Regexp matches, otherwise the submatch at ArrayIndex is compared with
+// Value.
+type RuleCondition struct {
+	Op         string          `json:"op,omitempty"`
+	Conditions []RuleCondition `json:"conditions,omitempty"`
+
+	Field       string `json:"field,omitempty"`
+	Regexp      string `json:"regexp,omitempty"`
+	ArrayIndex  int32  `json:"array_index,omitempty"`
+	CompareType string `json:"compare_type,omitempty"`
+	Value       string `json:"value,omitempty"`
+}
+
+type SqlJsonbRuleConditions []RuleCondition
+
+func (c SqlJsonbRuleConditions) Value() (driver.Value, error) {
+	if c == nil {
+		return []byte("[]"), nil
+	}
+	res, err := json.Marshal(c)
+	return res, err
+}
+
+func (c *SqlJsonbRuleConditions) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("SqlJsonbRuleConditions: unsupported type %T", value)
+	}
+	err := json.Unmarshal(b, c)
+	return err
+}
+
+// RuleWindow aggregates over a sliding or tumbling window of Duration. The
+// value of a message is the submatch at ArrayIndex of Regexp in Field, or 1
+// without a Regexp, so sum and rate count messages then. Rate is the sum per
+// second.
+type RuleWindow struct {
+	Type        string `json:"type"`
+	DurationSec int64  `json:"duration_sec"`
+	Aggregation string `json:"aggregation"`
+
+	Field      string `json:"field,omitempty"`
+	Regexp     string `json:"regexp,omitempty"`
+	ArrayIndex int32  `json:"array_index,omitempty"`
+
+	CompareType string  `json:"compare_type"`
+	Threshold   float64 `json:"threshold"`
+}
+
+func (w RuleWindow) Duration() time.Duration {
+	return time.Duration(w.DurationSec) * time.Second
+}
+
+func (w RuleWindow) Value() (driver.Value, error) {
+	res, err := json.Marshal(w)
+	return res, err
+}
+
+func (w *RuleWindow) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("RuleWindow: unsupported type %T", value)
+	}
+	err := json.Unmarshal(b, w)
+	return err
+}
+
+// RuleAnomaly learns a baseline per device of the submatch at ArrayIndex of
+// Regexp in Field and fires once a value deviates from it by more than Sigma
+// standard deviations, in Direction ("above", "below", or either if it is
+// empty). The level and the variance are EWMAs weighted by Alpha. With a
+// Seasonality ("hour_of_day" or "hour_of_week") an offset is also learned for
+// every hour. Nothing fires before MinSamples values were seen.
+type RuleAnomaly struct {
+	Field      string `json:"field,omitempty"`
+	Regexp     string `json:"regexp"`
+	ArrayIndex int32  `json:"array_index,omitempty"`
+
+	Alpha       float64 `json:"alpha"`
+	Sigma       float64 `json:"sigma"`
+	Direction   string  `json:"direction,omitempty"`
+	Seasonality string  `json:"seasonality,omitempty"`
+	MinSamples  int32   `json:"min_samples"`
+}
+
+func (a RuleAnomaly) Value() (driver.Value, error) {
+	res, err := json.Marshal(a)
+	return res, err
+}
+
+func (a *RuleAnomaly) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("RuleAnomaly: unsupported type %T", value)
+	}
+	err := json.Unmarshal(b, a)
+	return err
+}
+
+// AnomalyBaseline is what an anomaly rule learned for a device. Anomaly is
+// the definition it was learned for, a changed rule starts over. The last
+// value is compared with LastExpected, the baseline before the value was
+// learned, LastScore is the deviation in standard deviations.
+type AnomalyBaseline struct {
+	RuleID   int32                  `db:"rule_id"`
+	DeviceId int32                  `db:"device_id"`
+	Anomaly  RuleAnomaly            `db:"anomaly"`
+	Samples  int64                  `db:"samples"`
+	Level    float64                `db:"level"`
+	Variance float64                `db:"variance"`
+	Seasons  SqlJsonbAnomalySeasons `db:"seasons"`
+
+	LastValue     float64    `db:"last_value"`
+	LastExpected  float64    `db:"last_expected"`
+	LastScore     float64    `db:"last_score"`
+	Anomalous     bool       `db:"anomalous"`
+	LastAnomalyAt *time.Time `db:"last_anomaly_at"`
+	UpdatedAt     *time.Time `db:"updated_at"`
+}
+
+// AnomalySeason is the learned offset from the level in one hour of the
+// season.
+type AnomalySeason struct {
+	Offset  float64 `json:"offset"`
+	Samples int64   `json:"samples"`
+}
+
+type SqlJsonbAnomalySeasons []AnomalySeason
+
+func (s SqlJsonbAnomalySeasons) Value() (driver.Value, error) {
+	if s == nil {
+		return []byte("[]"), nil
+	}
+	res, err := json.Marshal(s)
+	return res, err
+}
+
+func (s *SqlJsonbAnomalySeasons) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("SqlJsonbAnomalySeasons: unsupported type %T", value)
+	}
+	err := json.Unmarshal(b, s)
+	return err
+}
+
 type Device struct {
 	ID          int32
 	Name        string
@@ -43,6 +214,36 @@ type Message struct {
 	MessageType   string    `db:"message_type"`
 	SeverityLevel string    `db:"severity_level"`
 	Component     string    `db:"component"`
+
+	// EventTime is reported by the device, ReceivedAt is set by
+	// data-ingestion-service. Both are nil for messages stored before they
+	// were introduced.
+	EventTime  *time.Time        `db:"event_time"`
+	ReceivedAt *time.Time        `db:"received_at"`
+	Attributes SqlJsonbStringMap `db:"attributes"`
+	// MessageID is the optional client ID, unique per device.
+	MessageID *string `db:"message_id"`
+	// SilenceID is the silence that muted the notifications of the message.
+	SilenceID *int32 `db:"silence_id"`
+}
+
+type SqlJsonbStringMap map[string]string
+
+func (m SqlJsonbStringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return []byte("{}"), nil
+	}
+	res, err := json.Marshal(m)
+	return res, err
+}
+
+func (m *SqlJsonbStringMap) Scan(value interface{}) error {
+	b, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("SqlJsonbStringMap: unsupported type %T", value)
+	}
+	err := json.Unmarshal(b, m)
+	return err
 }
 
 type CountByDeviceID struct {
@@ -50,10 +251,91 @@ type CountByDeviceID struct {
 	Count    int32 `db:"count"`
 }
 
+const (
+	AlertOpen         = "OPEN"
+	AlertAcknowledged = "ACKNOWLEDGED"
+	AlertResolved     = "RESOLVED"
+	// AlertFlapping holds an alert whose rule changes state too often, it
+	// stays active until the rule settles.
+	AlertFlapping = "FLAPPING"
+
+	// AlertRuleTag is the rule type of alerts raised by tags.
+	AlertRuleTag = "tag"
+	// AlertRuleHeartbeat is the rule type of alerts raised by missed
+	// heartbeats.
+	AlertRuleHeartbeat = "heartbeat"
+)
+
+// Alert is raised by a rule for a device and stays OPEN or ACKNOWLEDGED until
+// the rule recovers or a user resolves it. Message and Value are from the
+// last message that raised it again, Count is how many did. ResolvedBy is
+// nil if the alert recovered. Problem is whether the rule was raised by the
+// last message, a FLAPPING alert keeps it without changing state.
+type Alert struct {
+	ID             int32      `db:"id"`
+	RuleType       string     `db:"rule_type"`
+	RuleID         int32      `db:"rule_id"`
+	DeviceId       int32      `db:"device_id"`
+	State          string     `db:"state"`
+	Subject        string     `db:"subject"`
+	SeverityLevel  string     `db:"severity_level"`
+	Message        string     `db:"message"`
+	Value          string     `db:"value"`
+	Count          int32      `db:"count"`
+	OpenedAt       time.Time  `db:"opened_at"`
+	LastSeenAt     time.Time  `db:"last_seen_at"`
+	AcknowledgedAt *time.Time `db:"acknowledged_at"`
+	AcknowledgedBy *int32     `db:"acknowledged_by"`
+	ResolvedAt     *time.Time `db:"resolved_at"`
+	ResolvedBy     *int32     `db:"resolved_by"`
+	Problem        bool       `db:"problem"`
+	FlappingSince  *time.Time `db:"flapping_since"`
+}
+
+// Silence mutes the notifications of the messages it matches from StartsAt
+// to EndsAt. The matchers that are set must all match, a DeviceId of 0 and
+// empty strings match anything. RuleName is the name of the tag or rule that
+// notifies. With a cron Recurrence the silence is only active for Duration
+// seconds after each time the schedule fires.
+type Silence struct {
+	ID         int32     `db:"id"`
+	DeviceId   int32     `db:"device_id"`
+	DeviceType string    `db:"device_type"`
+	RuleName   string    `db:"rule_name"`
+	Component  string    `db:"component"`
+	StartsAt   time.Time `db:"starts_at"`
+	EndsAt     time.Time `db:"ends_at"`
+	Recurrence string    `db:"recurrence"`
+	Duration   int32     `db:"duration"`
+	CreatedBy  int32     `db:"created_by"`
+	Comment    string    `db:"comment"`
+	CreatedAt  time.Time `db:"created_at"`
+}
+
+// Heartbeat expects a message of MessageType, of any type if it is empty,
+// at least every Interval seconds. It applies to the device DeviceId or, if
+// that is 0, to every device of DeviceType.
+type Heartbeat struct {
+	ID            int32     `db:"id"`
+	Name          string    `db:"name"`
+	DeviceId      int32     `db:"device_id"`
+	DeviceType    string    `db:"device_type"`
+	MessageType   string    `db:"message_type"`
+	Interval      int32     `db:"interval"`
+	Subject       string    `db:"subject"`
+	SeverityLevel string    `db:"severity_level"`
+	CreatedAt     time.Time `db:"created_at"`
+}
+
+// SendedNotification is the last notification sent for a device, rule and
+// subject. Repeats until ExpiredAt are not sent but counted in Suppressed,
+// Message is the text of the last of them.
 type SendedNotification struct {
-	Message   string
-	DeviceId  int32
-	ExpiredAt time.Time
+	Message    string    `json:"message"`
+	DeviceId   int32     `json:"device_id"`
+	Subject    string    `json:"subject"`
+	ExpiredAt  time.Time `json:"expired_at"`
+	Suppressed int32     `json:"suppressed"`
 }
 
 type MonthReportRow struct {
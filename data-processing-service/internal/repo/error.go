@@ -5,4 +5,21 @@ import "errors"
 var (
 	ErrDeviceExists = errors.New("device already exists")
 	ErrNotFound     = errors.New("device not found")
+	ErrRuleExists   = errors.New("rule already exists")
+
+	ErrAlertNotFound = errors.New("alert not found")
+	// ErrAlertState means the alert can not move to the requested state.
+	ErrAlertState = errors.New("invalid alert state transition")
+
+	ErrSilenceNotFound   = errors.New("silence not found")
+	ErrHeartbeatNotFound = errors.New("heartbeat not found")
+
+	ErrWindowNotFound = errors.New("window not found")
+	// ErrWindowConflict means another replica saved the window in between.
+	ErrWindowConflict = errors.New("window changed concurrently")
+
+	ErrNotificationNotFound = errors.New("notification not found")
+	// ErrNotificationConflict means another replica saved the notification
+	// in between.
+	ErrNotificationConflict = errors.New("notification changed concurrently")
 )
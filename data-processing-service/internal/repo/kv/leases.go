@@ -0,0 +1,69 @@
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/repo"
+
+	"github.com/nats-io/nats.go"
+)
+
+const leasesBucket = "leases"
+
+type leasesRepo struct {
+	kv nats.KeyValue
+}
+
+// NewLeasesRepo binds the leases bucket, creating it on first start. A lease
+// not renewed within ttl expires and another replica can take it.
+func NewLeasesRepo(js nats.JetStreamContext, ttl time.Duration) (repo.Leases, error) {
+	kv, err := js.KeyValue(leasesBucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      leasesBucket,
+			Description: "Owners of single replica jobs, written by data-processing-service",
+			History:     1,
+			TTL:         ttl,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("js.KeyValue("+leasesBucket+"): %w", err)
+	}
+
+	return &leasesRepo{kv: kv}, nil
+}
+
+func (r *leasesRepo) Acquire(name string, owner string) (bool, error) {
+	entry, err := r.kv.Get(name)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		// Create also takes the place of a deleted entry.
+		_, err = r.kv.Create(name, []byte(owner))
+		if errors.Is(err, nats.ErrKeyExists) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("r.kv.Create: %w", err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("r.kv.Get: %w", err)
+	}
+
+	if string(entry.Value()) != owner {
+		return false, nil
+	}
+
+	// Writing the entry again restarts its ttl.
+	_, err = r.kv.Update(name, []byte(owner), entry.Revision())
+	if errors.Is(err, nats.ErrKeyExists) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("r.kv.Update: %w", err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,90 @@
+package kv
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+
+	"github.com/nats-io/nats.go"
+)
+
+const notificationsBucket = "sended_notifications"
+
+type notificationsRepo struct {
+	kv nats.KeyValue
+}
+
+// NewNotificationsRepo binds the sent notifications bucket, creating it on
+// first start. An entry expires ttl after its last save together with the
+// count of the repeats suppressed since.
+func NewNotificationsRepo(js nats.JetStreamContext, ttl time.Duration) (repo.Notifications, error) {
+	kv, err := js.KeyValue(notificationsBucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      notificationsBucket,
+			Description: "Last sent notifications, written by data-processing-service",
+			History:     1,
+			TTL:         ttl,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("js.KeyValue("+notificationsBucket+"): %w", err)
+	}
+
+	return &notificationsRepo{kv: kv}, nil
+}
+
+func (r *notificationsRepo) Load(key string) (models.SendedNotification, uint64, error) {
+	entry, err := r.kv.Get(key)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return models.SendedNotification{}, 0, repo.ErrNotificationNotFound
+	}
+	if err != nil {
+		return models.SendedNotification{}, 0, fmt.Errorf("r.kv.Get: %w", err)
+	}
+
+	var notification models.SendedNotification
+	if err = json.Unmarshal(entry.Value(), &notification); err != nil {
+		return models.SendedNotification{}, 0, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	return notification, entry.Revision(), nil
+}
+
+func (r *notificationsRepo) Keys() ([]string, error) {
+	keys, err := r.kv.Keys()
+	if errors.Is(err, nats.ErrNoKeysFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("r.kv.Keys: %w", err)
+	}
+
+	return keys, nil
+}
+
+func (r *notificationsRepo) Save(key string, notification models.SendedNotification, revision uint64) (uint64, error) {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return 0, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	if revision == 0 {
+		// Create also takes the place of a deleted entry.
+		revision, err = r.kv.Create(key, data)
+	} else {
+		revision, err = r.kv.Update(key, data, revision)
+	}
+	if errors.Is(err, nats.ErrKeyExists) {
+		return 0, repo.ErrNotificationConflict
+	}
+	if err != nil {
+		return 0, fmt.Errorf("r.kv.Update: %w", err)
+	}
+
+	return revision, nil
+}
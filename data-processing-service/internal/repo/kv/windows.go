@@ -0,0 +1,66 @@
+package kv
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/repo"
+
+	"github.com/nats-io/nats.go"
+)
+
+const windowsBucket = "rule_windows"
+
+type windowsRepo struct {
+	kv nats.KeyValue
+}
+
+// NewWindowsRepo binds the rule windows bucket, creating it on first start.
+// An entry expires ttl after its last save, so windows must be shorter.
+func NewWindowsRepo(js nats.JetStreamContext, ttl time.Duration) (repo.Windows, error) {
+	kv, err := js.KeyValue(windowsBucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      windowsBucket,
+			Description: "Windowed rule state, written by data-processing-service",
+			History:     1,
+			TTL:         ttl,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("js.KeyValue("+windowsBucket+"): %w", err)
+	}
+
+	return &windowsRepo{kv: kv}, nil
+}
+
+func (r *windowsRepo) Load(key string) ([]byte, uint64, error) {
+	entry, err := r.kv.Get(key)
+	if errors.Is(err, nats.ErrKeyNotFound) {
+		return nil, 0, repo.ErrWindowNotFound
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("r.kv.Get: %w", err)
+	}
+
+	return entry.Value(), entry.Revision(), nil
+}
+
+func (r *windowsRepo) Save(key string, state []byte, revision uint64) (uint64, error) {
+	var err error
+	if revision == 0 {
+		// Create also takes the place of a deleted entry.
+		revision, err = r.kv.Create(key, state)
+	} else {
+		revision, err = r.kv.Update(key, state, revision)
+	}
+	if errors.Is(err, nats.ErrKeyExists) {
+		return 0, repo.ErrWindowConflict
+	}
+	if err != nil {
+		return 0, fmt.Errorf("r.kv.Update: %w", err)
+	}
+
+	return revision, nil
+}
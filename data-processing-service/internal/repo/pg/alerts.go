@@ -0,0 +1,343 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+	"data-processing-service/pkg/postgres"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+type alertsRepo struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+
+	log *zap.Logger
+}
+
+func NewAlertsRepo(p *postgres.Postgres, log *zap.Logger) repo.Alerts {
+	return &alertsRepo{
+		db:  p.DB,
+		log: log,
+	}
+}
+
+func (r alertsRepo) BeginTx(ctx context.Context) (repo.Alerts, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("r.db.BeginTx: %w", err)
+	}
+
+	r.tx = tx
+
+	return r, nil
+}
+
+func (r alertsRepo) Commit() error {
+	err := r.tx.Commit()
+	if err != nil {
+		return fmt.Errorf("r.tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r alertsRepo) Rollback() error {
+	err := r.tx.Rollback()
+	if err != nil {
+		return fmt.Errorf("r.tx.Rollback: %w", err)
+	}
+
+	return nil
+}
+
+const alertsColumns = `id, rule_type, rule_id, device_id, state, "subject", severity_level, message, value, count,
+opened_at, last_seen_at, acknowledged_at, acknowledged_by, resolved_at, resolved_by, problem, flapping_since`
+
+const alertsRepoQueryActive = `
+select ` + alertsColumns + ` from alerts
+where rule_type = $1 and rule_id = $2 and device_id = $3 and state <> '` + models.AlertResolved + `'
+for update;
+`
+
+func (r alertsRepo) Active(ruleType string, ruleID int32, deviceID int32) (models.Alert, bool, error) {
+	var alert models.Alert
+	err := r.tx.Get(&alert, alertsRepoQueryActive, ruleType, ruleID, deviceID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Alert{}, false, nil
+	}
+	if err != nil {
+		return models.Alert{}, false, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return alert, true, nil
+}
+
+// The partial unique index lets only one replica open the alert, the others
+// count into it.
+const alertsRepoQueryRaise = `
+insert into alerts (rule_type, rule_id, device_id, state, "subject", severity_level, message, value, count, opened_at, last_seen_at)
+values
+(:rule_type, :rule_id, :device_id, '` + models.AlertOpen + `', :subject, :severity_level, :message, :value, 1, :now, :now)
+on conflict (rule_type, rule_id, device_id) where state <> '` + models.AlertResolved + `'
+do update set "subject" = excluded."subject",
+	severity_level = excluded.severity_level,
+	message = excluded.message,
+	value = excluded.value,
+	count = alerts.count + 1,
+	last_seen_at = excluded.last_seen_at,
+	problem = true
+returning ` + alertsColumns + `;
+`
+
+func (r alertsRepo) Raise(opts models.Alert) (models.Alert, bool, error) {
+	query, args, err := sqlx.Named(alertsRepoQueryRaise,
+		map[string]any{
+			"rule_type":      opts.RuleType,
+			"rule_id":        opts.RuleID,
+			"device_id":      opts.DeviceId,
+			"subject":        opts.Subject,
+			"severity_level": opts.SeverityLevel,
+			"message":        opts.Message,
+			"value":          opts.Value,
+			"now":            time.Now(),
+		},
+	)
+	if err != nil {
+		return models.Alert{}, false, fmt.Errorf("sqlx.Named: %w", err)
+	}
+	query = sqlx.Rebind(sqlx.BindType(r.tx.DriverName()), query)
+
+	var alert models.Alert
+	if err = r.tx.Get(&alert, query, args...); err != nil {
+		return models.Alert{}, false, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return alert, alert.Count == 1, nil
+}
+
+const alertsRepoQueryRecover = `
+update alerts
+set state = '` + models.AlertResolved + `',
+	resolved_at = $4
+where rule_type = $1 and rule_id = $2 and device_id = $3 and state <> '` + models.AlertResolved + `'
+returning ` + alertsColumns + `;
+`
+
+func (r alertsRepo) Recover(ruleType string, ruleID int32, deviceID int32) (models.Alert, bool, error) {
+	var alert models.Alert
+	err := r.tx.Get(&alert, alertsRepoQueryRecover, ruleType, ruleID, deviceID, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Alert{}, false, nil
+	}
+	if err != nil {
+		return models.Alert{}, false, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return alert, true, nil
+}
+
+const alertsRepoQueryRecovered = `
+update alerts
+set problem = false
+where id = $1 and state <> '` + models.AlertResolved + `'
+returning ` + alertsColumns + `;
+`
+
+func (r alertsRepo) Recovered(id int32) (models.Alert, error) {
+	var alert models.Alert
+	err := r.tx.Get(&alert, alertsRepoQueryRecovered, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Alert{}, repo.ErrAlertNotFound
+	}
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return alert, nil
+}
+
+// An alert leaving FLAPPING is ACKNOWLEDGED again if it was before.
+const alertsRepoQueryFlapping = `
+update alerts
+set state = case
+		when $2::timestamp is not null then '` + models.AlertFlapping + `'
+		when acknowledged_at is not null then '` + models.AlertAcknowledged + `'
+		else '` + models.AlertOpen + `'
+	end,
+	flapping_since = $2
+where id = $1 and state <> '` + models.AlertResolved + `'
+returning ` + alertsColumns + `;
+`
+
+func (r alertsRepo) Flapping(id int32, since *time.Time) (models.Alert, error) {
+	var alert models.Alert
+	err := r.tx.Get(&alert, alertsRepoQueryFlapping, id, since)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Alert{}, repo.ErrAlertNotFound
+	}
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return alert, nil
+}
+
+// Replicas checking at the same time each get the alerts the others did not
+// lock.
+const alertsRepoQueryReadFlapping = `
+select ` + alertsColumns + ` from alerts
+where state = '` + models.AlertFlapping + `'
+for update skip locked;
+`
+
+func (r alertsRepo) ReadFlapping() ([]models.Alert, error) {
+	alerts := make([]models.Alert, 0)
+
+	if err := r.tx.Select(&alerts, alertsRepoQueryReadFlapping); err != nil {
+		return nil, fmt.Errorf("r.tx.Select: %w", err)
+	}
+
+	return alerts, nil
+}
+
+const alertsRepoQueryAddStateChange = `
+insert into alert_state_changes (rule_type, rule_id, device_id, changed_at)
+values ($1, $2, $3, $4);
+`
+
+const alertsRepoQueryDropStateChanges = `
+delete from alert_state_changes
+where rule_type = $1 and rule_id = $2 and device_id = $3 and changed_at <= $4;
+`
+
+func (r alertsRepo) AddStateChange(ruleType string, ruleID int32, deviceID int32, at time.Time, since time.Time) error {
+	_, err := r.tx.Exec(alertsRepoQueryDropStateChanges, ruleType, ruleID, deviceID, since)
+	if err != nil {
+		return fmt.Errorf("r.tx.Exec: %w", err)
+	}
+
+	_, err = r.tx.Exec(alertsRepoQueryAddStateChange, ruleType, ruleID, deviceID, at)
+	if err != nil {
+		return fmt.Errorf("r.tx.Exec: %w", err)
+	}
+
+	return nil
+}
+
+const alertsRepoQueryStateChanges = `
+select count(*) from alert_state_changes
+where rule_type = $1 and rule_id = $2 and device_id = $3 and changed_at > $4;
+`
+
+func (r alertsRepo) StateChanges(ruleType string, ruleID int32, deviceID int32, since time.Time) (int, error) {
+	var count int
+	err := r.tx.Get(&count, alertsRepoQueryStateChanges, ruleType, ruleID, deviceID, since)
+	if err != nil {
+		return 0, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return count, nil
+}
+
+const alertsRepoQueryRead = `
+select ` + alertsColumns + ` from alerts
+where (cast(:state as varchar) is null or state = :state)
+	and (cast(:device_id as int) is null or device_id = :device_id)
+order by opened_at desc
+limit :limit;
+`
+
+func (r alertsRepo) Read(ctx context.Context, opts repo.ReadAlertsOpts) ([]models.Alert, error) {
+	alerts := make([]models.Alert, 0)
+
+	query, args, err := sqlx.Named(alertsRepoQueryRead,
+		map[string]any{
+			"state":     opts.State,
+			"device_id": opts.DeviceID,
+			"limit":     opts.Limit,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sqlx.Named: %w", err)
+	}
+	query = sqlx.Rebind(sqlx.BindType(r.tx.DriverName()), query)
+
+	if err = r.tx.SelectContext(ctx, &alerts, query, args...); err != nil {
+		return nil, fmt.Errorf("r.tx.SelectContext: %w", err)
+	}
+
+	return alerts, nil
+}
+
+const alertsRepoQueryAcknowledge = `
+update alerts
+set state = '` + models.AlertAcknowledged + `',
+	acknowledged_at = $3,
+	acknowledged_by = $2
+where id = $1 and state = '` + models.AlertOpen + `'
+returning ` + alertsColumns + `;
+`
+
+func (r alertsRepo) Acknowledge(ctx context.Context, id int32, userID int32) (models.Alert, error) {
+	var alert models.Alert
+	err := r.tx.GetContext(ctx, &alert, alertsRepoQueryAcknowledge, id, userID, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Alert{}, r.transitionError(ctx, id)
+	}
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("r.tx.GetContext: %w", err)
+	}
+
+	return alert, nil
+}
+
+const alertsRepoQueryResolve = `
+update alerts
+set state = '` + models.AlertResolved + `',
+	resolved_at = $3,
+	resolved_by = $2
+where id = $1 and state <> '` + models.AlertResolved + `'
+returning ` + alertsColumns + `;
+`
+
+func (r alertsRepo) Resolve(ctx context.Context, id int32, userID int32) (models.Alert, error) {
+	var alert models.Alert
+	err := r.tx.GetContext(ctx, &alert, alertsRepoQueryResolve, id, userID, time.Now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Alert{}, r.transitionError(ctx, id)
+	}
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("r.tx.GetContext: %w", err)
+	}
+
+	return alert, nil
+}
+
+const alertsRepoQueryState = `
+select state from alerts where id = $1;
+`
+
+// transitionError tells a missing alert from one in the wrong state.
+func (r alertsRepo) transitionError(ctx context.Context, id int32) error {
+	var state string
+	err := r.tx.GetContext(ctx, &state, alertsRepoQueryState, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return repo.ErrAlertNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("r.tx.GetContext: %w", err)
+	}
+
+	return fmt.Errorf("%w: alert is %s", repo.ErrAlertState, state)
+}
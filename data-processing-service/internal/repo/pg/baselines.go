@@ -0,0 +1,149 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+	"data-processing-service/pkg/postgres"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+type baselinesRepo struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+
+	log *zap.Logger
+}
+
+func NewBaselinesRepo(p *postgres.Postgres, log *zap.Logger) repo.Baselines {
+	return &baselinesRepo{
+		db:  p.DB,
+		log: log,
+	}
+}
+
+func (r baselinesRepo) BeginTx(ctx context.Context) (repo.Baselines, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("r.db.BeginTx: %w", err)
+	}
+
+	r.tx = tx
+
+	return r, nil
+}
+
+func (r baselinesRepo) Commit() error {
+	err := r.tx.Commit()
+	if err != nil {
+		return fmt.Errorf("r.tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r baselinesRepo) Rollback() error {
+	err := r.tx.Rollback()
+	if err != nil {
+		return fmt.Errorf("r.tx.Rollback: %w", err)
+	}
+
+	return nil
+}
+
+const baselinesColumns = `rule_id, device_id, anomaly, samples, "level", variance, seasons,
+	last_value, last_expected, last_score, anomalous, last_anomaly_at, updated_at`
+
+const baselinesRepoQueryInsertEmpty = `
+insert into anomaly_baselines (rule_id, device_id)
+values ($1, $2)
+on conflict (rule_id, device_id) do nothing;
+`
+
+const baselinesRepoQueryLock = `
+select ` + baselinesColumns + ` from anomaly_baselines
+where rule_id = $1 and device_id = $2
+for update;
+`
+
+// Lock inserts the empty baseline first, so replicas learning the same
+// device for the first time wait for each other on the row.
+func (r baselinesRepo) Lock(ruleID int32, deviceID int32) (models.AnomalyBaseline, error) {
+	_, err := r.tx.Exec(baselinesRepoQueryInsertEmpty, ruleID, deviceID)
+	if err != nil {
+		return models.AnomalyBaseline{}, fmt.Errorf("r.tx.Exec: %w", err)
+	}
+
+	var baseline models.AnomalyBaseline
+	if err = r.tx.Get(&baseline, baselinesRepoQueryLock, ruleID, deviceID); err != nil {
+		return models.AnomalyBaseline{}, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return baseline, nil
+}
+
+const baselinesRepoQuerySave = `
+update anomaly_baselines
+set anomaly = :anomaly,
+	samples = :samples,
+	"level" = :level,
+	variance = :variance,
+	seasons = :seasons,
+	last_value = :last_value,
+	last_expected = :last_expected,
+	last_score = :last_score,
+	anomalous = :anomalous,
+	last_anomaly_at = :last_anomaly_at,
+	updated_at = :updated_at
+where rule_id = :rule_id and device_id = :device_id;
+`
+
+func (r baselinesRepo) Save(baseline models.AnomalyBaseline) error {
+	now := time.Now()
+	baseline.UpdatedAt = &now
+
+	_, err := r.tx.NamedExec(baselinesRepoQuerySave, baseline)
+	if err != nil {
+		return fmt.Errorf("r.tx.NamedExec: %w", err)
+	}
+
+	return nil
+}
+
+const baselinesRepoQueryRead = `
+select ` + baselinesColumns + ` from anomaly_baselines
+where rule_id in (select id from rules where deleted_at is null)
+	and (cast(:rule_id as int) is null or rule_id = :rule_id)
+	and (cast(:device_id as int) is null or device_id = :device_id)
+order by rule_id, device_id;
+`
+
+func (r baselinesRepo) Read(ctx context.Context, opts repo.ReadBaselinesOpts) ([]models.AnomalyBaseline, error) {
+	baselines := make([]models.AnomalyBaseline, 0)
+
+	query, args, err := sqlx.Named(baselinesRepoQueryRead,
+		map[string]any{
+			"rule_id":   opts.RuleID,
+			"device_id": opts.DeviceID,
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sqlx.Named: %w", err)
+	}
+	query = sqlx.Rebind(sqlx.BindType(r.tx.DriverName()), query)
+
+	if err = r.tx.SelectContext(ctx, &baselines, query, args...); err != nil {
+		return nil, fmt.Errorf("r.tx.SelectContext: %w", err)
+	}
+
+	return baselines, nil
+}
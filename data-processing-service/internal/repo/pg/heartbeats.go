@@ -0,0 +1,164 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+	"data-processing-service/pkg/postgres"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+type heartbeatsRepo struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+
+	log *zap.Logger
+}
+
+func NewHeartbeatsRepo(p *postgres.Postgres, log *zap.Logger) repo.Heartbeats {
+	return &heartbeatsRepo{
+		db:  p.DB,
+		log: log,
+	}
+}
+
+func (r heartbeatsRepo) BeginTx(ctx context.Context) (repo.Heartbeats, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("r.db.BeginTx: %w", err)
+	}
+
+	r.tx = tx
+
+	return r, nil
+}
+
+func (r heartbeatsRepo) Commit() error {
+	err := r.tx.Commit()
+	if err != nil {
+		return fmt.Errorf("r.tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r heartbeatsRepo) Rollback() error {
+	err := r.tx.Rollback()
+	if err != nil {
+		return fmt.Errorf("r.tx.Rollback: %w", err)
+	}
+
+	return nil
+}
+
+const heartbeatsColumns = `id, "name", device_id, device_type, message_type, "interval", "subject", severity_level, created_at`
+
+const heartbeatsRepoQueryInsert = `
+insert into heartbeats ("name", device_id, device_type, message_type, "interval", "subject", severity_level, created_at)
+values
+(:name, :device_id, :device_type, :message_type, :interval, :subject, :severity_level, :created_at)
+returning ` + heartbeatsColumns + `;
+`
+
+func (r heartbeatsRepo) Create(opts models.Heartbeat) (models.Heartbeat, error) {
+	query, args, err := sqlx.Named(heartbeatsRepoQueryInsert,
+		map[string]any{
+			"name":           opts.Name,
+			"device_id":      opts.DeviceId,
+			"device_type":    opts.DeviceType,
+			"message_type":   opts.MessageType,
+			"interval":       opts.Interval,
+			"subject":        opts.Subject,
+			"severity_level": opts.SeverityLevel,
+			"created_at":     time.Now(),
+		},
+	)
+	if err != nil {
+		return models.Heartbeat{}, fmt.Errorf("sqlx.Named: %w", err)
+	}
+	query = sqlx.Rebind(sqlx.BindType(r.tx.DriverName()), query)
+
+	var heartbeat models.Heartbeat
+	if err = r.tx.Get(&heartbeat, query, args...); err != nil {
+		return models.Heartbeat{}, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return heartbeat, nil
+}
+
+const heartbeatsRepoQueryRead = `
+select ` + heartbeatsColumns + ` from heartbeats
+order by id;
+`
+
+func (r heartbeatsRepo) Read(ctx context.Context) ([]models.Heartbeat, error) {
+	heartbeats := make([]models.Heartbeat, 0)
+
+	if err := r.tx.SelectContext(ctx, &heartbeats, heartbeatsRepoQueryRead); err != nil {
+		return nil, fmt.Errorf("r.tx.SelectContext: %w", err)
+	}
+
+	return heartbeats, nil
+}
+
+const heartbeatsRepoQueryDelete = `
+delete from heartbeats
+where id = $1;
+`
+
+const heartbeatsRepoQueryResolveAlerts = `
+update alerts
+set state = '` + models.AlertResolved + `',
+	resolved_at = $2
+where rule_type = '` + models.AlertRuleHeartbeat + `' and rule_id = $1 and state <> '` + models.AlertResolved + `';
+`
+
+func (r heartbeatsRepo) Delete(ctx context.Context, id int32) error {
+	result, err := r.tx.ExecContext(ctx, heartbeatsRepoQueryDelete, id)
+	if err != nil {
+		return fmt.Errorf("r.tx.ExecContext: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+	if deleted == 0 {
+		return repo.ErrHeartbeatNotFound
+	}
+
+	_, err = r.tx.ExecContext(ctx, heartbeatsRepoQueryResolveAlerts, id, time.Now())
+	if err != nil {
+		return fmt.Errorf("r.tx.ExecContext: %w", err)
+	}
+
+	return nil
+}
+
+const heartbeatsRepoQuerySeen = `
+select distinct device_id from messages
+where got_at > $2 and ($1 = '' or message_type = $1);
+`
+
+func (r heartbeatsRepo) Seen(ctx context.Context, messageType string, since time.Time) (map[int32]struct{}, error) {
+	var deviceIDs []int32
+	if err := r.tx.SelectContext(ctx, &deviceIDs, heartbeatsRepoQuerySeen, messageType, since); err != nil {
+		return nil, fmt.Errorf("r.tx.SelectContext: %w", err)
+	}
+
+	seen := make(map[int32]struct{}, len(deviceIDs))
+	for _, id := range deviceIDs {
+		seen[id] = struct{}{}
+	}
+
+	return seen, nil
+}
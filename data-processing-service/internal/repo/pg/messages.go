@@ -3,6 +3,7 @@ package pg
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -61,13 +62,17 @@ func (r messagesRepo) Rollback() error {
 }
 
 const messagesRepoQueryInsert = `
-insert into messages (got_at, device_id, message, message_type, severity_level, component)
+insert into messages (got_at, device_id, message, message_type, severity_level, component, event_time, received_at, attributes, message_id, silence_id)
 values
-(:got_at, :device_id, :message, :message_type, :severity_level, :component)
+(:got_at, :device_id, :message, :message_type, :severity_level, :component, :event_time, :received_at, :attributes, :message_id, :silence_id)
+on conflict (device_id, message_id) where message_id is not null do nothing
+returning id
 `
 
-func (r messagesRepo) Create(opts models.Message) error {
-	_, err := r.tx.NamedExec(messagesRepoQueryInsert,
+// Create returns the id of the new message, false if the device already sent
+// a message with the same MessageID.
+func (r messagesRepo) Create(opts models.Message) (int32, bool, error) {
+	query, args, err := sqlx.Named(messagesRepoQueryInsert,
 		map[string]any{
 			"got_at":         time.Now(),
 			"device_id":      opts.DeviceId,
@@ -75,26 +80,95 @@ func (r messagesRepo) Create(opts models.Message) error {
 			"message_type":   opts.MessageType,
 			"severity_level": opts.SeverityLevel,
 			"component":      opts.Component,
+			"event_time":     opts.EventTime,
+			"received_at":    opts.ReceivedAt,
+			"attributes":     opts.Attributes,
+			"message_id":     opts.MessageID,
+			"silence_id":     opts.SilenceID,
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("s.tx.NamedExec: %w", err)
+		return 0, false, fmt.Errorf("sqlx.Named: %w", err)
+	}
+	query = sqlx.Rebind(sqlx.BindType(r.tx.DriverName()), query)
+
+	var id int32
+	err = r.tx.Get(&id, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return id, true, nil
+}
+
+const messagesRepoQueryUpdate = `
+update messages
+set severity_level = $2, silence_id = $3
+where id = $1
+`
+
+// Update stores the severity and the silence of a message decided after it
+// was created.
+func (r messagesRepo) Update(opts models.Message) error {
+	_, err := r.tx.Exec(messagesRepoQueryUpdate, opts.Id, opts.SeverityLevel, opts.SilenceID)
+	if err != nil {
+		return fmt.Errorf("r.tx.Exec: %w", err)
 	}
 
 	return nil
 }
 
+func (r messagesRepo) Alerts() repo.Alerts {
+	return alertsRepo{
+		db:  r.db,
+		tx:  r.tx,
+		log: r.log,
+	}
+}
+
+const messagesRepoQueryExists = `
+select exists (
+	select 1 from messages where device_id = $1 and message_id = $2
+)
+`
+
+func (r messagesRepo) Exists(deviceID int32, messageID string) (bool, error) {
+	var exists bool
+	if err := r.tx.Get(&exists, messagesRepoQueryExists, deviceID, messageID); err != nil {
+		return false, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Messages stored before event_time was introduced, or sent without it, are
+// placed by the time they were received or processed.
+const (
+	messagesTimeColumn      = "got_at"
+	messagesEventTimeColumn = "coalesce(event_time, received_at, got_at)"
+)
+
+func messagesOrderColumn(byEventTime bool) string {
+	if byEventTime {
+		return messagesEventTimeColumn
+	}
+	return messagesTimeColumn
+}
+
 const messagesRepoQueryGetAllByPeriod = `
-select got_at, device_id, message, message_type 
+select got_at, device_id, message, message_type, event_time, received_at, attributes, silence_id
 from messages
-Where got_at between :start and :end
-order by got_at desc
+Where %[1]s between :start and :end
+order by %[1]s desc
 `
 
 func (r messagesRepo) GetAllByPeriod(opts repo.MessagesGetAllByPeriodOpts) ([]models.Message, error) {
 	messages := make([]models.Message, 0)
 
-	query, args, err := sqlx.Named(messagesRepoQueryGetAllByPeriod, map[string]any{
+	query, args, err := sqlx.Named(fmt.Sprintf(messagesRepoQueryGetAllByPeriod, messagesOrderColumn(opts.ByEventTime)), map[string]any{
 		"start": opts.StartTime,
 		"end":   opts.EndTime,
 	})
@@ -113,17 +187,47 @@ func (r messagesRepo) GetAllByPeriod(opts repo.MessagesGetAllByPeriodOpts) ([]mo
 }
 
 const messagesRepoQueryGetAllByDeviceId = `
-select got_at, device_id, message, message_type
+select got_at, device_id, message, message_type, event_time, received_at, attributes, silence_id
 from messages
 where device_id = :device_id
-order by got_at desc
+order by %s desc
+`
+
+func (r messagesRepo) GetAllByDeviceId(opts repo.MessagesGetAllByDeviceIdOpts) ([]models.Message, error) {
+	messages := make([]models.Message, 0)
+
+	query, args, err := sqlx.Named(fmt.Sprintf(messagesRepoQueryGetAllByDeviceId, messagesOrderColumn(opts.ByEventTime)), map[string]any{
+		"device_id": opts.DeviceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sqlx.Named: %w", err)
+	}
+	query = sqlx.Rebind(sqlx.BindType(r.tx.DriverName()), query)
+	err = r.tx.Select(&messages, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("r.tx.Select: %w", err)
+	}
+
+	return messages, nil
+}
+
+// The oldest first, in the order they were processed.
+const messagesRepoQueryGetByDeviceAndPeriod = `
+select got_at, device_id, message, message_type, coalesce(component, '') as component, event_time, received_at, attributes, silence_id
+from messages
+where device_id = :device_id and got_at between :start and :end
+order by got_at
+limit :limit
 `
 
-func (r messagesRepo) GetAllByDeviceId(deviceID int32) ([]models.Message, error) {
+func (r messagesRepo) GetByDeviceAndPeriod(opts repo.MessagesGetByDeviceAndPeriodOpts) ([]models.Message, error) {
 	messages := make([]models.Message, 0)
 
-	query, args, err := sqlx.Named(messagesRepoQueryGetAllByDeviceId, map[string]any{
-		"device_id": deviceID,
+	query, args, err := sqlx.Named(messagesRepoQueryGetByDeviceAndPeriod, map[string]any{
+		"device_id": opts.DeviceID,
+		"start":     opts.StartTime,
+		"end":       opts.EndTime,
+		"limit":     opts.Limit,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("sqlx.Named: %w", err)
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS messages_event_time_idx;
+
+ALTER TABLE messages
+	DROP COLUMN IF EXISTS attributes,
+	DROP COLUMN IF EXISTS received_at,
+	DROP COLUMN IF EXISTS event_time;
@@ -0,0 +1,7 @@
+ALTER TABLE messages
+	ADD COLUMN IF NOT EXISTS event_time timestamp NULL,
+	ADD COLUMN IF NOT EXISTS received_at timestamp NULL,
+	ADD COLUMN IF NOT EXISTS attributes jsonb NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS messages_event_time_idx
+	ON messages ((coalesce(event_time, received_at, got_at)));
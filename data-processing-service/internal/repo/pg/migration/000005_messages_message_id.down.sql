@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS messages_device_id_message_id_idx;
+
+ALTER TABLE messages
+	DROP COLUMN IF EXISTS message_id;
@@ -0,0 +1,6 @@
+ALTER TABLE messages
+	ADD COLUMN IF NOT EXISTS message_id varchar(128) NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS messages_device_id_message_id_idx
+	ON messages (device_id, message_id)
+	WHERE message_id IS NOT NULL;
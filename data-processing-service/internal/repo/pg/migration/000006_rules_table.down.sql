@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS tr_bd_rules ON rules;
+
+DROP FUNCTION IF EXISTS bd_tr_rules();
+
+drop table if exists rules;
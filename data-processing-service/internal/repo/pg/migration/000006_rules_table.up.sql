@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS rules (
+		id int GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+		"name" varchar NOT NULL,
+		device_id int NOT NULL DEFAULT 0,
+		op varchar(3) NOT NULL,
+		conditions jsonb NOT NULL,
+		"subject" varchar NOT NULL,
+		severity_level varchar(10) NOT NULL DEFAULT 'info',
+		created_at timestamp without time zone NULL,
+		updated_at timestamp without time zone NULL,
+		deleted_at timestamp without time zone NULL,
+		CONSTRAINT rules_pk PRIMARY KEY (id),
+		CONSTRAINT rules_name_unique UNIQUE ("name")
+	);
+
+CREATE OR REPLACE FUNCTION bd_tr_rules()
+ RETURNS trigger
+ LANGUAGE plpgsql
+AS $function$
+	BEGIN
+		update rules
+		set deleted_at = current_timestamp
+		where id = old.id;
+
+		return NULL;
+	END;
+$function$
+;
+
+create or replace trigger tr_bd_rules before
+delete
+	on
+	rules for each row execute function bd_tr_rules();
@@ -0,0 +1,2 @@
+ALTER TABLE rules
+	ADD COLUMN IF NOT EXISTS "window" jsonb NULL;
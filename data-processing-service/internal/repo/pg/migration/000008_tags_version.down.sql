@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS tr_aiu_tags_version ON tags;
+
+DROP FUNCTION IF EXISTS aiu_tr_tags_version();
+
+drop table if exists tags_version;
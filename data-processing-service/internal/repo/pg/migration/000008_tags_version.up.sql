@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS tags_version (
+		id boolean NOT NULL DEFAULT true,
+		"version" bigint NOT NULL DEFAULT 0,
+		CONSTRAINT tags_version_pk PRIMARY KEY (id),
+		CONSTRAINT tags_version_single_row CHECK (id)
+	);
+
+INSERT INTO tags_version (id, "version") VALUES (true, 0) ON CONFLICT DO NOTHING;
+
+-- A delete of a tag is turned into an update by tr_bd_tags, so inserts and
+-- updates cover every change of the tag set.
+CREATE OR REPLACE FUNCTION aiu_tr_tags_version()
+ RETURNS trigger
+ LANGUAGE plpgsql
+AS $function$
+	BEGIN
+		update tags_version
+		set "version" = "version" + 1;
+
+		return NULL;
+	END;
+$function$
+;
+
+create or replace trigger tr_aiu_tags_version after
+insert or update
+	on
+	tags for each statement execute function aiu_tr_tags_version();
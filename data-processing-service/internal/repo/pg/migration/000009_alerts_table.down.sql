@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS alerts_state_idx;
+
+DROP INDEX IF EXISTS alerts_active_unique;
+
+drop table if exists alerts;
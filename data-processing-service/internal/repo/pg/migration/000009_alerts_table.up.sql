@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS alerts (
+		id int GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+		rule_type varchar(20) NOT NULL,
+		rule_id int NOT NULL,
+		device_id int NOT NULL,
+		state varchar(12) NOT NULL,
+		"subject" varchar NOT NULL,
+		severity_level varchar(10) NOT NULL DEFAULT 'info',
+		message varchar NOT NULL DEFAULT '',
+		value varchar NOT NULL DEFAULT '',
+		count int NOT NULL DEFAULT 1,
+		opened_at timestamp without time zone NOT NULL,
+		last_seen_at timestamp without time zone NOT NULL,
+		acknowledged_at timestamp without time zone NULL,
+		acknowledged_by int NULL,
+		resolved_at timestamp without time zone NULL,
+		resolved_by int NULL,
+		CONSTRAINT alerts_pk PRIMARY KEY (id)
+	);
+
+-- One alert per rule and device is open or acknowledged at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS alerts_active_unique
+	ON alerts (rule_type, rule_id, device_id)
+	WHERE state <> 'RESOLVED';
+
+CREATE INDEX IF NOT EXISTS alerts_state_idx ON alerts (state);
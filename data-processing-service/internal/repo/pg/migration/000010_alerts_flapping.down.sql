@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS alert_state_changes_idx;
+DROP TABLE IF EXISTS alert_state_changes;
+
+UPDATE alerts SET state = 'OPEN' WHERE state = 'FLAPPING';
+ALTER TABLE alerts DROP COLUMN IF EXISTS flapping_since;
+ALTER TABLE alerts DROP COLUMN IF EXISTS problem;
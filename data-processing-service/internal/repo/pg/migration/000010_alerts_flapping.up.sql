@@ -0,0 +1,14 @@
+-- problem is the state of the rule at the last message, while an alert is
+-- FLAPPING it no longer follows it.
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS problem boolean NOT NULL DEFAULT true;
+ALTER TABLE alerts ADD COLUMN IF NOT EXISTS flapping_since timestamp without time zone NULL;
+
+CREATE TABLE IF NOT EXISTS alert_state_changes (
+		rule_type varchar(20) NOT NULL,
+		rule_id int NOT NULL,
+		device_id int NOT NULL,
+		changed_at timestamp without time zone NOT NULL
+	);
+
+CREATE INDEX IF NOT EXISTS alert_state_changes_idx
+	ON alert_state_changes (rule_type, rule_id, device_id, changed_at);
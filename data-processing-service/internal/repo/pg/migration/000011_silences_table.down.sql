@@ -0,0 +1,6 @@
+ALTER TABLE messages
+	DROP COLUMN IF EXISTS silence_id;
+
+DROP INDEX IF EXISTS silences_ends_at_idx;
+
+drop table if exists silences;
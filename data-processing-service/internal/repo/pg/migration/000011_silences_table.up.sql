@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS silences (
+		id int GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+		device_id int NOT NULL DEFAULT 0,
+		device_type varchar NOT NULL DEFAULT '',
+		rule_name varchar NOT NULL DEFAULT '',
+		component varchar NOT NULL DEFAULT '',
+		starts_at timestamp without time zone NOT NULL,
+		ends_at timestamp without time zone NOT NULL,
+		recurrence varchar NOT NULL DEFAULT '',
+		duration int NOT NULL DEFAULT 0,
+		created_by int NOT NULL,
+		"comment" varchar NOT NULL DEFAULT '',
+		created_at timestamp without time zone NOT NULL,
+		CONSTRAINT silences_pk PRIMARY KEY (id),
+		CONSTRAINT silences_period_check CHECK (starts_at < ends_at)
+	);
+
+CREATE INDEX IF NOT EXISTS silences_ends_at_idx ON silences (ends_at);
+
+-- The silence that muted the notifications of a message, it stays set after
+-- the silence is deleted.
+ALTER TABLE messages
+	ADD COLUMN IF NOT EXISTS silence_id int NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS messages_got_at_idx;
+
+drop table if exists heartbeats;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS heartbeats (
+		id int GENERATED BY DEFAULT AS IDENTITY NOT NULL,
+		"name" varchar NOT NULL,
+		device_id int NOT NULL DEFAULT 0,
+		device_type varchar NOT NULL DEFAULT '',
+		message_type varchar NOT NULL DEFAULT '',
+		"interval" int NOT NULL,
+		"subject" varchar NOT NULL,
+		severity_level varchar(10) NOT NULL DEFAULT 'critical',
+		created_at timestamp without time zone NOT NULL,
+		CONSTRAINT heartbeats_pk PRIMARY KEY (id),
+		CONSTRAINT heartbeats_interval_check CHECK ("interval" > 0)
+	);
+
+-- The heartbeat check only reads the messages of the last interval.
+CREATE INDEX IF NOT EXISTS messages_got_at_idx ON messages (got_at);
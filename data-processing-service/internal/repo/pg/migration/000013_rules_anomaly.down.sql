@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS anomaly_baselines;
+
+ALTER TABLE rules
+	DROP COLUMN IF EXISTS anomaly;
@@ -0,0 +1,20 @@
+ALTER TABLE rules
+	ADD COLUMN IF NOT EXISTS anomaly jsonb NULL;
+
+CREATE TABLE IF NOT EXISTS anomaly_baselines (
+		rule_id int NOT NULL,
+		device_id int NOT NULL,
+		anomaly jsonb NOT NULL DEFAULT '{}',
+		samples bigint NOT NULL DEFAULT 0,
+		"level" double precision NOT NULL DEFAULT 0,
+		variance double precision NOT NULL DEFAULT 0,
+		seasons jsonb NOT NULL DEFAULT '[]',
+		last_value double precision NOT NULL DEFAULT 0,
+		last_expected double precision NOT NULL DEFAULT 0,
+		last_score double precision NOT NULL DEFAULT 0,
+		anomalous bool NOT NULL DEFAULT false,
+		last_anomaly_at timestamp without time zone NULL,
+		updated_at timestamp without time zone NULL,
+		CONSTRAINT anomaly_baselines_pk PRIMARY KEY (rule_id, device_id),
+		CONSTRAINT anomaly_baselines_rule_fk FOREIGN KEY (rule_id) REFERENCES rules (id)
+	);
@@ -0,0 +1 @@
+-- The mirrors are not restored, alerts recover threshold tags since 000009.
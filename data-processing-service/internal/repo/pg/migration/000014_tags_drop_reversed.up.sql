@@ -0,0 +1,21 @@
+-- Threshold tags used to create a mirror of themselves when they matched,
+-- with subject OK, severity info and the reversed compare type, which deleted
+-- itself on the next match. Alerts replaced the mirrors, the ones left behind
+-- would notify OK on every healthy message.
+update tags m
+set deleted_at = current_timestamp
+where m.deleted_at is null
+	and m."subject" = 'OK'
+	and m.severity_level = 'info'
+	and m.compare_type in ('<', '>')
+	and exists (
+		select 1
+		from tags t
+		where t.id <> m.id
+			and t.device_id = m.device_id
+			and t.regexp = m.regexp
+			and t.value = m.value
+			and t.array_index = m.array_index
+			and t."subject" <> 'OK'
+			and t.compare_type = case m.compare_type when '<' then '>' else '<' end
+	);
@@ -0,0 +1,186 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+	"data-processing-service/pkg/postgres"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+type rulesRepo struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+
+	log *zap.Logger
+}
+
+func NewRulesRepo(p *postgres.Postgres, log *zap.Logger) repo.Rules {
+	return &rulesRepo{
+		db:  p.DB,
+		log: log,
+	}
+}
+
+func (r rulesRepo) BeginTx(ctx context.Context) (repo.Rules, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("r.db.BeginTx: %w", err)
+	}
+
+	r.tx = tx
+
+	return r, nil
+}
+
+func (r rulesRepo) Commit() error {
+	err := r.tx.Commit()
+	if err != nil {
+		return fmt.Errorf("r.tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r rulesRepo) Rollback() error {
+	err := r.tx.Rollback()
+	if err != nil {
+		return fmt.Errorf("r.tx.Rollback: %w", err)
+	}
+
+	return nil
+}
+
+const rulesRepoQueryInsert = `
+insert into rules (name, device_id, op, conditions, "window", anomaly, subject, severity_level, created_at)
+values
+(:name, :device_id, :op, :conditions, :window, :anomaly, :subject, :severity_level, :created_at)
+returning id, "name", device_id, op, conditions, "window", anomaly, subject, severity_level, created_at, updated_at;
+`
+
+func (r rulesRepo) Create(opts models.Rule) (models.Rule, error) {
+	query, args, err := sqlx.Named(rulesRepoQueryInsert,
+		map[string]any{
+			"name":           opts.Name,
+			"device_id":      opts.DeviceId,
+			"op":             opts.Op,
+			"conditions":     opts.Conditions,
+			"window":         opts.Window,
+			"anomaly":        opts.Anomaly,
+			"subject":        opts.Subject,
+			"severity_level": opts.SeverityLevel,
+			"created_at":     time.Now(),
+		},
+	)
+	if err != nil {
+		return models.Rule{}, fmt.Errorf("sqlx.Named: %w", err)
+	}
+	query = sqlx.Rebind(sqlx.BindType(r.tx.DriverName()), query)
+
+	var rule models.Rule
+	err = r.tx.Get(&rule, query, args...)
+	if err != nil {
+		var pgerr *pgconn.PgError
+		if errors.As(err, &pgerr) {
+			if pgerr.Code == pgErrCodeUniqueViolation {
+				return models.Rule{}, repo.ErrRuleExists
+			}
+		}
+
+		return models.Rule{}, fmt.Errorf("s.tx.Get: %w", err)
+	}
+	return rule, nil
+}
+
+const rulesRepoQueryRead = `
+select id, "name", device_id, op, conditions, "window", anomaly, subject, severity_level, created_at, updated_at from rules
+where deleted_at is null;
+`
+
+func (r rulesRepo) Read(ctx context.Context) (repo.ReadRulesResult, error) {
+	var result repo.ReadRulesResult
+	err := r.tx.SelectContext(ctx, &result.Rules, rulesRepoQueryRead)
+	if err != nil {
+		return repo.ReadRulesResult{}, fmt.Errorf("r.tx.SelectContext: %w", err)
+	}
+
+	return result, nil
+}
+
+const rulesRepoQueryUpdate = `
+update rules
+set name = coalesce(:name, name),
+	device_id = coalesce(:device_id, device_id),
+	op = coalesce(:op, op),
+	conditions = coalesce(:conditions, conditions),
+	"window" = case when :remove_window then null else coalesce(:window, "window") end,
+	anomaly = case when :remove_anomaly then null else coalesce(:anomaly, anomaly) end,
+	subject = coalesce(:subject, subject),
+	severity_level = coalesce(:severity_level, severity_level),
+	updated_at = :updated_at
+where id = :id and deleted_at is null;
+`
+
+func (r rulesRepo) Update(ctx context.Context, opts repo.UpdateRulesOpts) error {
+	_, err := r.tx.NamedExecContext(ctx, rulesRepoQueryUpdate,
+		struct {
+			ID            int32                          `db:"id"`
+			Name          *string                        `db:"name"`
+			DeviceId      *int32                         `db:"device_id"`
+			Op            *string                        `db:"op"`
+			Conditions    *models.SqlJsonbRuleConditions `db:"conditions"`
+			Window        *models.RuleWindow             `db:"window"`
+			RemoveWindow  bool                           `db:"remove_window"`
+			Anomaly       *models.RuleAnomaly            `db:"anomaly"`
+			RemoveAnomaly bool                           `db:"remove_anomaly"`
+			Subject       *string                        `db:"subject"`
+			SeverityLevel *string                        `db:"severity_level"`
+			UpdatedAt     time.Time                      `db:"updated_at"`
+		}{
+			ID:            opts.ID,
+			Name:          opts.Name,
+			DeviceId:      opts.DeviceId,
+			Op:            opts.Op,
+			Conditions:    opts.Conditions,
+			Window:        opts.Window,
+			RemoveWindow:  opts.RemoveWindow,
+			Anomaly:       opts.Anomaly,
+			RemoveAnomaly: opts.RemoveAnomaly,
+			Subject:       opts.Subject,
+			SeverityLevel: opts.SeverityLevel,
+			UpdatedAt:     time.Now(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("s.tx.NamedExecContext: %w", err)
+	}
+	return nil
+}
+
+const rulesRepoQueryDelete = `
+delete from rules
+where id = :id and deleted_at is null;
+`
+
+func (r rulesRepo) Delete(ctx context.Context, id int32) error {
+	_, err := r.tx.NamedExecContext(ctx, rulesRepoQueryDelete,
+		map[string]any{
+			"id": id,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("s.tx.NamedExecContext: %w", err)
+	}
+	return nil
+}
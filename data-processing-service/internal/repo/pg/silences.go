@@ -0,0 +1,140 @@
+package pg
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+	"data-processing-service/pkg/postgres"
+
+	"github.com/jmoiron/sqlx"
+	"go.uber.org/zap"
+)
+
+type silencesRepo struct {
+	db *sqlx.DB
+	tx *sqlx.Tx
+
+	log *zap.Logger
+}
+
+func NewSilencesRepo(p *postgres.Postgres, log *zap.Logger) repo.Silences {
+	return &silencesRepo{
+		db:  p.DB,
+		log: log,
+	}
+}
+
+func (r silencesRepo) BeginTx(ctx context.Context) (repo.Silences, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{
+		Isolation: 0,
+		ReadOnly:  false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("r.db.BeginTx: %w", err)
+	}
+
+	r.tx = tx
+
+	return r, nil
+}
+
+func (r silencesRepo) Commit() error {
+	err := r.tx.Commit()
+	if err != nil {
+		return fmt.Errorf("r.tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r silencesRepo) Rollback() error {
+	err := r.tx.Rollback()
+	if err != nil {
+		return fmt.Errorf("r.tx.Rollback: %w", err)
+	}
+
+	return nil
+}
+
+const silencesColumns = `id, device_id, device_type, rule_name, component, starts_at, ends_at, recurrence, duration,
+created_by, "comment", created_at`
+
+const silencesRepoQueryInsert = `
+insert into silences (device_id, device_type, rule_name, component, starts_at, ends_at, recurrence, duration,
+	created_by, "comment", created_at)
+values
+(:device_id, :device_type, :rule_name, :component, :starts_at, :ends_at, :recurrence, :duration,
+	:created_by, :comment, :created_at)
+returning ` + silencesColumns + `;
+`
+
+func (r silencesRepo) Create(opts models.Silence) (models.Silence, error) {
+	query, args, err := sqlx.Named(silencesRepoQueryInsert,
+		map[string]any{
+			"device_id":   opts.DeviceId,
+			"device_type": opts.DeviceType,
+			"rule_name":   opts.RuleName,
+			"component":   opts.Component,
+			"starts_at":   opts.StartsAt,
+			"ends_at":     opts.EndsAt,
+			"recurrence":  opts.Recurrence,
+			"duration":    opts.Duration,
+			"created_by":  opts.CreatedBy,
+			"comment":     opts.Comment,
+			"created_at":  time.Now(),
+		},
+	)
+	if err != nil {
+		return models.Silence{}, fmt.Errorf("sqlx.Named: %w", err)
+	}
+	query = sqlx.Rebind(sqlx.BindType(r.tx.DriverName()), query)
+
+	var silence models.Silence
+	if err = r.tx.Get(&silence, query, args...); err != nil {
+		return models.Silence{}, fmt.Errorf("r.tx.Get: %w", err)
+	}
+
+	return silence, nil
+}
+
+const silencesRepoQueryRead = `
+select ` + silencesColumns + ` from silences
+where ends_at > $1
+order by starts_at desc;
+`
+
+func (r silencesRepo) Read(ctx context.Context, since time.Time) ([]models.Silence, error) {
+	silences := make([]models.Silence, 0)
+
+	if err := r.tx.SelectContext(ctx, &silences, silencesRepoQueryRead, since); err != nil {
+		return nil, fmt.Errorf("r.tx.SelectContext: %w", err)
+	}
+
+	return silences, nil
+}
+
+const silencesRepoQueryDelete = `
+delete from silences
+where id = $1;
+`
+
+func (r silencesRepo) Delete(ctx context.Context, id int32) error {
+	result, err := r.tx.ExecContext(ctx, silencesRepoQueryDelete, id)
+	if err != nil {
+		return fmt.Errorf("r.tx.ExecContext: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("result.RowsAffected: %w", err)
+	}
+	if deleted == 0 {
+		return repo.ErrSilenceNotFound
+	}
+
+	return nil
+}
@@ -111,9 +111,20 @@ select id, "name", device_id, regexp, compare_type, value, array_index, subject,
 where deleted_at is null;
 `
 
+// Read takes the version before the tags, a change in between makes the
+// version older than the tags but never newer.
 func (r tagsRepo) Read(ctx context.Context) (repo.ReadTagsResult, error) {
-	var result repo.ReadTagsResult
-	err := r.tx.SelectContext(ctx, &result.Tags, tagsRepoQueryRead)
+	var (
+		result repo.ReadTagsResult
+		err    error
+	)
+
+	result.Version, err = r.Version(ctx)
+	if err != nil {
+		return repo.ReadTagsResult{}, err
+	}
+
+	err = r.tx.SelectContext(ctx, &result.Tags, tagsRepoQueryRead)
 	if err != nil {
 		return repo.ReadTagsResult{}, fmt.Errorf("r.tx.SelectContext: %w", err)
 	}
@@ -121,6 +132,20 @@ func (r tagsRepo) Read(ctx context.Context) (repo.ReadTagsResult, error) {
 	return result, nil
 }
 
+const tagsRepoQueryVersion = `
+select "version" from tags_version;
+`
+
+func (r tagsRepo) Version(ctx context.Context) (int64, error) {
+	var version int64
+	err := r.tx.GetContext(ctx, &version, tagsRepoQueryVersion)
+	if err != nil {
+		return 0, fmt.Errorf("r.tx.GetContext: %w", err)
+	}
+
+	return version, nil
+}
+
 const tagsRepoQueryUpdate = `
 update tags 
 set name = coalesce(:name, name),
@@ -3,6 +3,7 @@ package repo
 import (
 	"context"
 	"data-processing-service/internal/models"
+	"time"
 )
 
 type Tags interface {
@@ -12,18 +13,132 @@ type Tags interface {
 
 	Create(opts models.Tag) (models.Tag, error)
 	Read(ctx context.Context) (ReadTagsResult, error)
+	Version(ctx context.Context) (int64, error)
 	Update(ctx context.Context, opts UpdateTagsOpts) error
 	Delete(ctx context.Context, id int32) error
 }
 
+type Rules interface {
+	BeginTx(ctx context.Context) (Rules, error)
+	Commit() error
+	Rollback() error
+
+	Create(opts models.Rule) (models.Rule, error)
+	Read(ctx context.Context) (ReadRulesResult, error)
+	Update(ctx context.Context, opts UpdateRulesOpts) error
+	Delete(ctx context.Context, id int32) error
+}
+
+// Baselines keeps what anomaly rules learned per device.
+type Baselines interface {
+	BeginTx(ctx context.Context) (Baselines, error)
+	Commit() error
+	Rollback() error
+
+	// Lock locks the baseline of the rule and device, creating an empty one
+	// first if there is none.
+	Lock(ruleID int32, deviceID int32) (models.AnomalyBaseline, error)
+	Save(baseline models.AnomalyBaseline) error
+	// Read returns the baselines of the rules that were not deleted.
+	Read(ctx context.Context, opts ReadBaselinesOpts) ([]models.AnomalyBaseline, error)
+}
+
+type Alerts interface {
+	BeginTx(ctx context.Context) (Alerts, error)
+	Commit() error
+	Rollback() error
+
+	// Active locks the active alert of the rule and device, ok is false if
+	// there is none.
+	Active(ruleType string, ruleID int32, deviceID int32) (alert models.Alert, ok bool, err error)
+	// Raise opens an alert for the rule and device or counts into the one
+	// already active, opened tells which.
+	Raise(opts models.Alert) (alert models.Alert, opened bool, err error)
+	// Recover resolves the active alert of the rule and device, ok is false
+	// if there is none.
+	Recover(ruleType string, ruleID int32, deviceID int32) (alert models.Alert, ok bool, err error)
+	// Recovered marks the active alert as no longer raised without
+	// resolving it.
+	Recovered(id int32) (models.Alert, error)
+	// Flapping moves an active alert into FLAPPING at since, a nil since
+	// moves it back to the state it had before.
+	Flapping(id int32, since *time.Time) (models.Alert, error)
+	// ReadFlapping locks the FLAPPING alerts no other transaction holds.
+	ReadFlapping() ([]models.Alert, error)
+	// AddStateChange records a change between raised and recovered and drops
+	// the changes before since.
+	AddStateChange(ruleType string, ruleID int32, deviceID int32, at time.Time, since time.Time) error
+	// StateChanges counts the changes of the rule and device after since.
+	StateChanges(ruleType string, ruleID int32, deviceID int32, since time.Time) (int, error)
+	Read(ctx context.Context, opts ReadAlertsOpts) ([]models.Alert, error)
+	Acknowledge(ctx context.Context, id int32, userID int32) (models.Alert, error)
+	Resolve(ctx context.Context, id int32, userID int32) (models.Alert, error)
+}
+
+type Silences interface {
+	BeginTx(ctx context.Context) (Silences, error)
+	Commit() error
+	Rollback() error
+
+	Create(opts models.Silence) (models.Silence, error)
+	// Read returns the silences ending after since, the ones starting last
+	// first.
+	Read(ctx context.Context, since time.Time) ([]models.Silence, error)
+	Delete(ctx context.Context, id int32) error
+}
+
+type Heartbeats interface {
+	BeginTx(ctx context.Context) (Heartbeats, error)
+	Commit() error
+	Rollback() error
+
+	Create(opts models.Heartbeat) (models.Heartbeat, error)
+	Read(ctx context.Context) ([]models.Heartbeat, error)
+	// Delete also resolves the active alerts of the heartbeat.
+	Delete(ctx context.Context, id int32) error
+	// Seen returns the devices that sent a message of messageType, of any
+	// type if it is empty, after since.
+	Seen(ctx context.Context, messageType string, since time.Time) (map[int32]struct{}, error)
+}
+
+// Leases elect a single owner of a job among the replicas.
+type Leases interface {
+	// Acquire takes the lease for owner or renews it if owner holds it
+	// already, ok is false while another owner holds it.
+	Acquire(name string, owner string) (ok bool, err error)
+}
+
+// Windows checkpoints the state of windowed rules where every replica sees
+// it. Save only succeeds if the entry is still at revision, 0 means it must
+// not exist yet.
+type Windows interface {
+	Load(key string) (state []byte, revision uint64, err error)
+	Save(key string, state []byte, revision uint64) (uint64, error)
+}
+
+// Notifications keeps the last sent notifications where every replica sees
+// them. Save only succeeds if the entry is still at revision, 0 means it must
+// not exist yet.
+type Notifications interface {
+	Load(key string) (notification models.SendedNotification, revision uint64, err error)
+	Save(key string, notification models.SendedNotification, revision uint64) (uint64, error)
+	Keys() ([]string, error)
+}
+
 type Messages interface {
 	BeginTx(ctx context.Context) (Messages, error)
 	Commit() error
 	Rollback() error
 
-	Create(opts models.Message) error
+	Create(opts models.Message) (int32, bool, error)
+	Update(opts models.Message) error
+	// Alerts works in the transaction of the messages, it is committed or
+	// rolled back with them.
+	Alerts() Alerts
+	Exists(deviceID int32, messageID string) (bool, error)
 	GetAllByPeriod(opts MessagesGetAllByPeriodOpts) ([]models.Message, error)
-	GetAllByDeviceId(deviceID int32) ([]models.Message, error)
+	GetAllByDeviceId(opts MessagesGetAllByDeviceIdOpts) ([]models.Message, error)
+	GetByDeviceAndPeriod(opts MessagesGetByDeviceAndPeriodOpts) ([]models.Message, error)
 	GetCountByMessageType(messageType string) (GetCountByMessageTypeResult, error)
 	MonthReport() ([]models.MonthReportRow, error)
 }
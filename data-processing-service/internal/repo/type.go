@@ -19,11 +19,59 @@ type UpdateTagsOpts struct {
 
 type ReadTagsResult struct {
 	Tags []models.Tag
+	// Version of the tag set, it grows with every change.
+	Version int64
+}
+
+type UpdateRulesOpts struct {
+	ID         int32
+	Name       *string
+	DeviceId   *int32
+	Op         *string
+	Conditions *models.SqlJsonbRuleConditions
+	Window     *models.RuleWindow
+	// RemoveWindow turns a windowed rule back into a plain one.
+	RemoveWindow bool
+	Anomaly      *models.RuleAnomaly
+	// RemoveAnomaly turns an anomaly rule back into a plain one.
+	RemoveAnomaly bool
+	Subject       *string
+	SeverityLevel *string
+}
+
+type ReadRulesResult struct {
+	Rules []models.Rule
+}
+
+// ReadAlertsOpts filters by the fields that are set.
+type ReadAlertsOpts struct {
+	State    *string
+	DeviceID *int32
+	Limit    int
+}
+
+// ReadBaselinesOpts filters by the fields that are set.
+type ReadBaselinesOpts struct {
+	RuleID   *int32
+	DeviceID *int32
 }
 
 type MessagesGetAllByPeriodOpts struct {
+	StartTime   time.Time
+	EndTime     time.Time
+	ByEventTime bool
+}
+
+type MessagesGetAllByDeviceIdOpts struct {
+	DeviceID    int32
+	ByEventTime bool
+}
+
+type MessagesGetByDeviceAndPeriodOpts struct {
+	DeviceID  int32
 	StartTime time.Time
 	EndTime   time.Time
+	Limit     int
 }
 
 type GetCountByMessageTypeResult struct {
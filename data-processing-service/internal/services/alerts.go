@@ -0,0 +1,566 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+// RecoveredSubject is the subject of the notification sent when an alert
+// resolves by itself.
+const RecoveredSubject = "OK"
+
+// The subjects of the notifications sent instead of the ones of a flapping
+// alert.
+const (
+	FlappingSubject        = "FLAPPING"
+	FlappingStoppedSubject = "FLAPPING STOPPED"
+)
+
+const (
+	defaultAlertsLimit = 100
+	maxAlertsLimit     = 1000
+)
+
+type Alerts interface {
+	Read(ctx context.Context, params ReadAlertsParams) ([]models.Alert, error)
+	Acknowledge(ctx context.Context, alertID int32, userID int32) (models.Alert, error)
+	Resolve(ctx context.Context, alertID int32, userID int32) (models.Alert, error)
+}
+
+type AlertsService struct {
+	repo repo.Alerts
+}
+
+func NewAlertsService(r repo.Alerts) Alerts {
+	return &AlertsService{
+		repo: r,
+	}
+}
+
+type (
+	ReadAlertsParams struct {
+		State    *string
+		DeviceID *int32
+		// Limit is 100 if not set and at most 1000.
+		Limit int
+	}
+)
+
+// Read returns the newest alerts first.
+func (s *AlertsService) Read(ctx context.Context, params ReadAlertsParams) ([]models.Alert, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultAlertsLimit
+	}
+	limit = min(limit, maxAlertsLimit)
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	alerts, err := tx.Read(ctx, repo.ReadAlertsOpts{
+		State:    params.State,
+		DeviceID: params.DeviceID,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tx.Read: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return alerts, nil
+}
+
+// Acknowledge only moves an OPEN alert, it stays active until resolved.
+func (s *AlertsService) Acknowledge(ctx context.Context, alertID int32, userID int32) (models.Alert, error) {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	alert, err := tx.Acknowledge(ctx, alertID, userID)
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("tx.Acknowledge: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return alert, nil
+}
+
+// Resolve closes an active alert, the next message that raises it again opens
+// a new one.
+func (s *AlertsService) Resolve(ctx context.Context, alertID int32, userID int32) (models.Alert, error) {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	alert, err := tx.Resolve(ctx, alertID, userID)
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("tx.Resolve: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Alert{}, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return alert, nil
+}
+
+// isThresholdTag tells the tags that raise alerts, a value on the other side
+// of their threshold recovers them.
+func isThresholdTag(tag models.Tag) bool {
+	return tag.CompareType == ">" || tag.CompareType == "<"
+}
+
+// recovered is the reverse comparison of a threshold tag, a value equal to
+// the threshold neither raises nor recovers.
+func recovered(tag models.Tag, value string) bool {
+	number, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return false
+	}
+	threshold, err := strconv.ParseFloat(strings.TrimSpace(tag.Value), 64)
+	if err != nil {
+		return false
+	}
+
+	if tag.CompareType == ">" {
+		return number < threshold
+	}
+	return number > threshold
+}
+
+// alertChange is what a message did to the alert of its tag.
+type alertChange int
+
+const (
+	// alertNotified is sent as the notification of the tag.
+	alertNotified alertChange = iota
+	alertRecovered
+	alertFlappingStarted
+	// alertHeld is not notified while the alert is FLAPPING.
+	alertHeld
+	// alertUnchanged is a healthy value without an alert to recover, it is
+	// not notified.
+	alertUnchanged
+)
+
+// alertState is what tagAlert needs of repo.Alerts. The dry run of a tag
+// keeps it in memory.
+type alertState interface {
+	Active(ruleType string, ruleID int32, deviceID int32) (models.Alert, bool, error)
+	Raise(opts models.Alert) (models.Alert, bool, error)
+	Recover(ruleType string, ruleID int32, deviceID int32) (models.Alert, bool, error)
+	Recovered(id int32) (models.Alert, error)
+	Flapping(id int32, since *time.Time) (models.Alert, error)
+	AddStateChange(ruleType string, ruleID int32, deviceID int32, at time.Time, since time.Time) error
+	StateChanges(ruleType string, ruleID int32, deviceID int32, since time.Time) (int, error)
+}
+
+// tagAlert raises the alert of a threshold tag or recovers it within the
+// transaction that stored the message. Every change between raised and
+// recovered is counted, FlapThreshold of them within FlapWindow put the alert
+// into FLAPPING until SettleFlapping ends it. The alert id is 0 without an
+// alert, the text is set for alertFlappingStarted.
+func (ms *MessagesService) tagAlert(log *zap.Logger, tx alertState, match tagMatch, message models.Message, now time.Time) (alertChange, int32, string, error) {
+	tag := match.tag
+	active, ok, err := tx.Active(models.AlertRuleTag, tag.ID, message.DeviceId)
+	if err != nil {
+		return alertNotified, 0, "", fmt.Errorf("tx.Active: %w", err)
+	}
+
+	flapping := ok && active.State == models.AlertFlapping
+	changed := match.passed != (ok && active.Problem)
+
+	changes := 0
+	if changed && ms.flapThreshold > 0 {
+		since := now.Add(-ms.flapWindow)
+		err = tx.AddStateChange(models.AlertRuleTag, tag.ID, message.DeviceId, now, since)
+		if err != nil {
+			return alertNotified, 0, "", fmt.Errorf("tx.AddStateChange: %w", err)
+		}
+		changes, err = tx.StateChanges(models.AlertRuleTag, tag.ID, message.DeviceId, since)
+		if err != nil {
+			return alertNotified, 0, "", fmt.Errorf("tx.StateChanges: %w", err)
+		}
+	}
+	startFlapping := !flapping && ms.flapThreshold > 0 && changes >= ms.flapThreshold
+
+	change := alertNotified
+	alert := active
+	switch {
+	case match.passed:
+		var opened bool
+		alert, opened, err = tx.Raise(models.Alert{
+			RuleType:      models.AlertRuleTag,
+			RuleID:        tag.ID,
+			DeviceId:      message.DeviceId,
+			Subject:       tag.Subject,
+			SeverityLevel: tag.SeverityLevel,
+			Message:       message.Message,
+			Value:         match.value,
+		})
+		if err != nil {
+			return alertNotified, 0, "", fmt.Errorf("tx.Raise: %w", err)
+		}
+		if opened {
+			log.Info("alert opened", zap.Int32("alert id", alert.ID), zap.Int32("tag id", tag.ID),
+				zap.Int32("device id", message.DeviceId))
+		}
+
+	case !changed:
+		// Nothing to recover.
+		change = alertUnchanged
+
+	case flapping || startFlapping:
+		alert, err = tx.Recovered(active.ID)
+		if err != nil {
+			return alertNotified, 0, "", fmt.Errorf("tx.Recovered: %w", err)
+		}
+
+	default:
+		alert, _, err = tx.Recover(models.AlertRuleTag, tag.ID, message.DeviceId)
+		if err != nil {
+			return alertNotified, 0, "", fmt.Errorf("tx.Recover: %w", err)
+		}
+		change = alertRecovered
+	}
+
+	switch {
+	case flapping:
+		change = alertHeld
+	case startFlapping:
+		alert, err = tx.Flapping(alert.ID, &now)
+		if err != nil {
+			return alertNotified, 0, "", fmt.Errorf("tx.Flapping: %w", err)
+		}
+		change = alertFlappingStarted
+	}
+
+	switch change {
+	case alertRecovered:
+		log.Info("alert recovered", zap.Int32("alert id", alert.ID), zap.Int32("tag id", tag.ID),
+			zap.Int32("device id", message.DeviceId))
+	case alertFlappingStarted:
+		log.Info("alert flapping", zap.Int32("alert id", alert.ID), zap.Int32("tag id", tag.ID),
+			zap.Int32("device id", message.DeviceId), zap.Int("changes", changes))
+		return change, alert.ID, fmt.Sprintf("%s: %d state changes within %s, notifications are held until it settles",
+			alert.Subject, changes, ms.flapWindow), nil
+	}
+
+	return change, alert.ID, "", nil
+}
+
+// alertSourceName is the name of the tag, rule or heartbeat that raised the
+// alert, empty if it is gone.
+func (ms *MessagesService) alertSourceName(alert models.Alert) string {
+	switch alert.RuleType {
+	case models.AlertRuleTag:
+		return tagName(alert.DeviceId, alert.RuleID)
+	case NotifySourceRule:
+		return ruleName(alert.RuleID)
+	case models.AlertRuleHeartbeat:
+		return ms.heartbeatName(alert.RuleID)
+	}
+	return ""
+}
+
+// ruleName is empty for a rule that is gone.
+func ruleName(ruleID int32) string {
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+
+	for _, deviceRules := range rules {
+		for _, rule := range deviceRules {
+			if rule.rule.ID == ruleID {
+				return rule.rule.Name
+			}
+		}
+	}
+	return ""
+}
+
+// heartbeatName is empty for a heartbeat that is gone or can not be read.
+func (ms *MessagesService) heartbeatName(heartbeatID int32) string {
+	if ms.heartbeatRepo == nil {
+		return ""
+	}
+
+	ctx := context.Background()
+	tx, err := ms.heartbeatRepo.BeginTx(ctx)
+	if err != nil {
+		ms.log.Error("ms.heartbeatRepo.BeginTx", zap.Error(err))
+		return ""
+	}
+	defer tx.Rollback()
+
+	heartbeats, err := tx.Read(ctx)
+	if err != nil {
+		ms.log.Error("tx.Read", zap.Error(err))
+		return ""
+	}
+
+	for _, heartbeat := range heartbeats {
+		if heartbeat.ID == heartbeatID {
+			return heartbeat.Name
+		}
+	}
+	return ""
+}
+
+// tagName is empty for a tag that is gone.
+func tagName(deviceID int32, tagID int32) string {
+	tagsMutex.Lock()
+	defer tagsMutex.Unlock()
+
+	for _, tag := range tags[deviceID] {
+		if tag.ID == tagID {
+			return tag.Name
+		}
+	}
+	return ""
+}
+
+// DeviceNotification is a notification not caused by a message of the device.
+type DeviceNotification struct {
+	DeviceID     int32
+	Notification CreateMessageResponse
+}
+
+// SettleFlapping ends FLAPPING for the alerts whose rule changed state less
+// than half of FlapThreshold times within FlapWindow. An alert still raised
+// becomes OPEN or ACKNOWLEDGED again, the others resolve. Only the replica
+// holding the flapping lease settles. It returns one notification per settled
+// alert that is not silenced.
+func (ms *MessagesService) SettleFlapping() []DeviceNotification {
+	if ms.flapThreshold <= 0 || ms.leaseRepo == nil {
+		return nil
+	}
+
+	owner, err := ms.leaseRepo.Acquire(flappingLease, ms.instance)
+	if err != nil {
+		ms.log.Warn("ms.leaseRepo.Acquire", zap.Error(err))
+		return nil
+	}
+	if !owner {
+		return nil
+	}
+
+	tx, err := ms.alertRepo.BeginTx(context.Background())
+	if err != nil {
+		ms.log.Error("ms.alertRepo.BeginTx", zap.Error(err))
+		return nil
+	}
+	defer tx.Rollback()
+
+	alerts, err := tx.ReadFlapping()
+	if err != nil {
+		ms.log.Error("tx.ReadFlapping", zap.Error(err))
+		return nil
+	}
+
+	since := time.Now().Add(-ms.flapWindow)
+
+	var settled []DeviceNotification
+	for _, alert := range alerts {
+		changes, err := tx.StateChanges(alert.RuleType, alert.RuleID, alert.DeviceId, since)
+		if err != nil {
+			ms.log.Error("tx.StateChanges", zap.Error(err), zap.Int32("alert id", alert.ID))
+			return nil
+		}
+		if changes*2 >= ms.flapThreshold {
+			continue
+		}
+
+		text := fmt.Sprintf("%s: flapping stopped, still raised", alert.Subject)
+		if alert.Problem {
+			if _, err = tx.Flapping(alert.ID, nil); err != nil {
+				ms.log.Error("tx.Flapping", zap.Error(err), zap.Int32("alert id", alert.ID))
+				return nil
+			}
+		} else {
+			text = fmt.Sprintf("%s: flapping stopped, recovered", alert.Subject)
+			if _, _, err = tx.Recover(alert.RuleType, alert.RuleID, alert.DeviceId); err != nil {
+				ms.log.Error("tx.Recover", zap.Error(err), zap.Int32("alert id", alert.ID))
+				return nil
+			}
+		}
+
+		settled = append(settled, DeviceNotification{
+			DeviceID: alert.DeviceId,
+			Notification: CreateMessageResponse{
+				Text:       text,
+				Subject:    FlappingStoppedSubject,
+				SourceType: alert.RuleType,
+				SourceID:   alert.RuleID,
+				SourceName: ms.alertSourceName(alert),
+			},
+		})
+	}
+
+	if err = tx.Commit(); err != nil {
+		ms.log.Error("tx.Commit", zap.Error(err))
+		return nil
+	}
+
+	sent := make([]DeviceNotification, 0, len(settled))
+	for _, n := range settled {
+		ms.log.Info("alert settled", zap.String("rule type", n.Notification.SourceType),
+			zap.Int32("rule id", n.Notification.SourceID), zap.Int32("device id", n.DeviceID))
+
+		if _, ok := ms.silenceOf(models.Message{DeviceId: n.DeviceID}, n.Notification.SourceName); ok {
+			continue
+		}
+
+		for _, notify := range ms.suppressRepeats(n.DeviceID, []CreateMessageResponse{n.Notification}) {
+			sent = append(sent, DeviceNotification{DeviceID: n.DeviceID, Notification: notify})
+		}
+	}
+
+	return sent
+}
+
+type alertKey struct {
+	ruleType string
+	ruleID   int32
+	deviceID int32
+}
+
+// memoryAlerts is the alert state of a dry run. It starts without alerts and
+// stamps them with now instead of the wall clock.
+type memoryAlerts struct {
+	now     time.Time
+	lastID  int32
+	active  map[alertKey]models.Alert
+	changes map[alertKey][]time.Time
+}
+
+func newMemoryAlerts() *memoryAlerts {
+	return &memoryAlerts{
+		active:  map[alertKey]models.Alert{},
+		changes: map[alertKey][]time.Time{},
+	}
+}
+
+func (m *memoryAlerts) Active(ruleType string, ruleID int32, deviceID int32) (models.Alert, bool, error) {
+	alert, ok := m.active[alertKey{ruleType, ruleID, deviceID}]
+	return alert, ok, nil
+}
+
+func (m *memoryAlerts) Raise(opts models.Alert) (models.Alert, bool, error) {
+	key := alertKey{opts.RuleType, opts.RuleID, opts.DeviceId}
+	alert, ok := m.active[key]
+	if !ok {
+		m.lastID++
+		alert = models.Alert{
+			ID:       m.lastID,
+			RuleType: opts.RuleType,
+			RuleID:   opts.RuleID,
+			DeviceId: opts.DeviceId,
+			State:    models.AlertOpen,
+			OpenedAt: m.now,
+		}
+	}
+	alert.Subject = opts.Subject
+	alert.SeverityLevel = opts.SeverityLevel
+	alert.Message = opts.Message
+	alert.Value = opts.Value
+	alert.Count++
+	alert.LastSeenAt = m.now
+	alert.Problem = true
+	m.active[key] = alert
+
+	return alert, !ok, nil
+}
+
+func (m *memoryAlerts) Recover(ruleType string, ruleID int32, deviceID int32) (models.Alert, bool, error) {
+	key := alertKey{ruleType, ruleID, deviceID}
+	alert, ok := m.active[key]
+	if !ok {
+		return models.Alert{}, false, nil
+	}
+	delete(m.active, key)
+
+	now := m.now
+	alert.State = models.AlertResolved
+	alert.ResolvedAt = &now
+	return alert, true, nil
+}
+
+func (m *memoryAlerts) Recovered(id int32) (models.Alert, error) {
+	return m.update(id, func(alert *models.Alert) {
+		alert.Problem = false
+	})
+}
+
+func (m *memoryAlerts) Flapping(id int32, since *time.Time) (models.Alert, error) {
+	return m.update(id, func(alert *models.Alert) {
+		alert.FlappingSince = since
+		switch {
+		case since != nil:
+			alert.State = models.AlertFlapping
+		case alert.AcknowledgedAt != nil:
+			alert.State = models.AlertAcknowledged
+		default:
+			alert.State = models.AlertOpen
+		}
+	})
+}
+
+func (m *memoryAlerts) update(id int32, change func(alert *models.Alert)) (models.Alert, error) {
+	for key, alert := range m.active {
+		if alert.ID == id {
+			change(&alert)
+			m.active[key] = alert
+			return alert, nil
+		}
+	}
+
+	return models.Alert{}, repo.ErrAlertNotFound
+}
+
+func (m *memoryAlerts) AddStateChange(ruleType string, ruleID int32, deviceID int32, at time.Time, since time.Time) error {
+	key := alertKey{ruleType, ruleID, deviceID}
+	kept := m.changes[key][:0]
+	for _, changedAt := range m.changes[key] {
+		if changedAt.After(since) {
+			kept = append(kept, changedAt)
+		}
+	}
+	m.changes[key] = append(kept, at)
+
+	return nil
+}
+
+func (m *memoryAlerts) StateChanges(ruleType string, ruleID int32, deviceID int32, since time.Time) (int, error) {
+	count := 0
+	for _, changedAt := range m.changes[alertKey{ruleType, ruleID, deviceID}] {
+		if changedAt.After(since) {
+			count++
+		}
+	}
+
+	return count, nil
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+const (
+	AnomalyAbove = "above"
+	AnomalyBelow = "below"
+
+	AnomalyHourOfDay  = "hour_of_day"
+	AnomalyHourOfWeek = "hour_of_week"
+
+	defaultAnomalyAlpha      = 0.1
+	defaultAnomalySigma      = 3
+	defaultAnomalyMinSamples = 30
+
+	// minAnomalyDeviation keeps the score of a value finite after a run of
+	// equal values, relative to the magnitude of the baseline.
+	minAnomalyDeviation = 1e-6
+)
+
+// withAnomalyDefaults fills in the parameters that were left out.
+func withAnomalyDefaults(a models.RuleAnomaly) models.RuleAnomaly {
+	if a.Alpha == 0 {
+		a.Alpha = defaultAnomalyAlpha
+	}
+	if a.Sigma == 0 {
+		a.Sigma = defaultAnomalySigma
+	}
+	if a.MinSamples == 0 {
+		a.MinSamples = defaultAnomalyMinSamples
+	}
+	return a
+}
+
+// compiledAnomaly learns the values of the messages that pass the rule
+// conditions.
+type compiledAnomaly struct {
+	anomaly models.RuleAnomaly
+	value   func(models.Message) (float64, bool)
+	// seasons is the number of hours in the season, 0 without one.
+	seasons int
+}
+
+func compileAnomaly(a models.RuleAnomaly) (*compiledAnomaly, error) {
+	if a.Regexp == "" {
+		return nil, fmt.Errorf("%w: anomaly without regexp", ErrInvalidRule)
+	}
+	if a.Alpha <= 0 || a.Alpha > 1 {
+		return nil, fmt.Errorf("%w: anomaly alpha %v out of range (0, 1]", ErrInvalidRule, a.Alpha)
+	}
+	if a.Sigma <= 0 {
+		return nil, fmt.Errorf("%w: anomaly sigma %v not positive", ErrInvalidRule, a.Sigma)
+	}
+	if a.MinSamples < 0 {
+		return nil, fmt.Errorf("%w: anomaly min_samples %d negative", ErrInvalidRule, a.MinSamples)
+	}
+
+	switch a.Direction {
+	case "", AnomalyAbove, AnomalyBelow:
+	default:
+		return nil, fmt.Errorf("%w: unknown anomaly direction %q", ErrInvalidRule, a.Direction)
+	}
+
+	var seasons int
+	switch a.Seasonality {
+	case "":
+	case AnomalyHourOfDay:
+		seasons = 24
+	case AnomalyHourOfWeek:
+		seasons = 7 * 24
+	default:
+		return nil, fmt.Errorf("%w: unknown anomaly seasonality %q", ErrInvalidRule, a.Seasonality)
+	}
+
+	value, err := numericValue(a.Field, a.Regexp, a.ArrayIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compiledAnomaly{
+		anomaly: a,
+		value:   value,
+		seasons: seasons,
+	}, nil
+}
+
+// season is the hour of the season t falls in, in the time zone of the
+// service.
+func (a *compiledAnomaly) season(t time.Time) int {
+	if a.seasons == 24 {
+		return t.Hour()
+	}
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+// observe compares a value at t with the baseline, learns it and reports
+// whether the rule fires. It fires when a value starts to deviate, again
+// only after a value was back within the baseline. Deviating values are
+// learned too, so a lasting shift becomes the new baseline. A seasonal rule
+// also waits until the hour of t was seen once.
+func (a *compiledAnomaly) observe(baseline *models.AnomalyBaseline, t time.Time, value float64) bool {
+	if baseline.Anomaly != a.anomaly {
+		*baseline = models.AnomalyBaseline{
+			RuleID:   baseline.RuleID,
+			DeviceId: baseline.DeviceId,
+			Anomaly:  a.anomaly,
+		}
+	}
+	if len(baseline.Seasons) != a.seasons {
+		baseline.Seasons = make(models.SqlJsonbAnomalySeasons, a.seasons)
+	}
+
+	var season *models.AnomalySeason
+	if a.seasons > 0 {
+		season = &baseline.Seasons[a.season(t)]
+	}
+
+	expected := baseline.Level
+	if season != nil {
+		expected += season.Offset
+	}
+	deviation := value - expected
+	std := max(math.Sqrt(baseline.Variance), minAnomalyDeviation*max(1, math.Abs(expected)))
+	score := deviation / std
+
+	ready := baseline.Samples > 0 && baseline.Samples >= int64(a.anomaly.MinSamples) && (season == nil || season.Samples > 0)
+	var deviates bool
+	switch a.anomaly.Direction {
+	case AnomalyAbove:
+		deviates = score > a.anomaly.Sigma
+	case AnomalyBelow:
+		deviates = score < -a.anomaly.Sigma
+	default:
+		deviates = math.Abs(score) > a.anomaly.Sigma
+	}
+	deviates = deviates && ready
+
+	fired := deviates && !baseline.Anomalous
+	baseline.Anomalous = deviates
+	if deviates {
+		baseline.LastAnomalyAt = &t
+	}
+
+	alpha := a.anomaly.Alpha
+	if baseline.Samples == 0 {
+		baseline.Level = value
+		expected = value
+		score = 0
+	} else {
+		baseline.Variance = (1 - alpha) * (baseline.Variance + alpha*deviation*deviation)
+		if season != nil {
+			baseline.Level += alpha * (value - season.Offset - baseline.Level)
+		} else {
+			baseline.Level += alpha * (value - baseline.Level)
+		}
+	}
+	if season != nil {
+		if season.Samples == 0 {
+			season.Offset = value - baseline.Level
+		} else {
+			season.Offset += alpha * (value - baseline.Level - season.Offset)
+		}
+		season.Samples++
+	}
+
+	baseline.Samples++
+	baseline.LastValue = value
+	baseline.LastExpected = expected
+	baseline.LastScore = score
+
+	return fired
+}
+
+// describeAnomaly is the notification text of a fired anomaly rule.
+func describeAnomaly(baseline models.AnomalyBaseline) string {
+	return fmt.Sprintf("%s deviates %s sigma from the baseline %s",
+		strconv.FormatFloat(baseline.LastValue, 'f', -1, 64),
+		strconv.FormatFloat(baseline.LastScore, 'f', 2, 64),
+		strconv.FormatFloat(baseline.LastExpected, 'f', 2, 64),
+	)
+}
+
+// observeAnomaly learns the value of the message into the baseline of its
+// device and reports whether the rule fires. The baseline is locked while it
+// is learned, so replicas take turns. Without a baseline repo, or if it is
+// unavailable, the rule does not fire.
+func (ms *MessagesService) observeAnomaly(r compiledRule, message models.Message) (bool, string) {
+	if ms.baselineRepo == nil {
+		return false, ""
+	}
+
+	value, ok := r.anomaly.value(message)
+	if !ok {
+		return false, ""
+	}
+
+	t := time.Now()
+	if message.ReceivedAt != nil {
+		t = *message.ReceivedAt
+	}
+
+	tx, err := ms.baselineRepo.BeginTx(context.Background())
+	if err != nil {
+		ms.log.Error("ms.baselineRepo.BeginTx", zap.Error(err))
+		return false, ""
+	}
+	defer tx.Rollback()
+
+	baseline, err := tx.Lock(r.rule.ID, message.DeviceId)
+	if err != nil {
+		ms.log.Error("tx.Lock", zap.Error(err), zap.Int32("rule id", r.rule.ID))
+		return false, ""
+	}
+
+	fired := r.anomaly.observe(&baseline, t, value)
+
+	if err = tx.Save(baseline); err != nil {
+		ms.log.Error("tx.Save", zap.Error(err), zap.Int32("rule id", r.rule.ID))
+		return false, ""
+	}
+
+	if err = tx.Commit(); err != nil {
+		ms.log.Error("tx.Commit", zap.Error(err))
+		return false, ""
+	}
+
+	return fired, describeAnomaly(baseline)
+}
+
+// ReadBaselines returns what the anomaly rules learned, to see why one
+// fired.
+func (s *RulesService) ReadBaselines(ctx context.Context, opts repo.ReadBaselinesOpts) ([]models.AnomalyBaseline, error) {
+	tx, err := s.baselineRepo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s.baselineRepo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.Read(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("tx.Read: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+var ErrInvalidHeartbeat = errors.New("invalid heartbeat")
+
+const (
+	minHeartbeatInterval = 10 * time.Second
+
+	defaultHeartbeatSubject  = "HEARTBEAT MISSED"
+	defaultHeartbeatSeverity = "critical"
+
+	// heartbeatsLease is held by the replica that checks the heartbeats.
+	heartbeatsLease = "heartbeats"
+	// flappingLease is held by the replica that settles flapping alerts.
+	flappingLease = "flapping"
+)
+
+type Heartbeats interface {
+	Create(ctx context.Context, params models.Heartbeat) (models.Heartbeat, error)
+	Read(ctx context.Context) ([]models.Heartbeat, error)
+	Delete(ctx context.Context, heartbeatID int32) error
+}
+
+type HeartbeatsService struct {
+	repo repo.Heartbeats
+}
+
+func NewHeartbeatsService(r repo.Heartbeats) Heartbeats {
+	return &HeartbeatsService{
+		repo: r,
+	}
+}
+
+// Create needs either a device or a device type. A device type heartbeat
+// applies to the devices of the type when it is checked.
+func (s *HeartbeatsService) Create(ctx context.Context, params models.Heartbeat) (models.Heartbeat, error) {
+	if (params.DeviceId == 0) == (params.DeviceType == "") {
+		return models.Heartbeat{}, fmt.Errorf("%w: needs either device_id or device_type", ErrInvalidHeartbeat)
+	}
+	if interval := time.Duration(params.Interval) * time.Second; interval < minHeartbeatInterval {
+		return models.Heartbeat{}, fmt.Errorf("%w: interval %s shorter than %s", ErrInvalidHeartbeat, interval, minHeartbeatInterval)
+	}
+	if params.Subject == "" {
+		params.Subject = defaultHeartbeatSubject
+	}
+	if params.SeverityLevel == "" {
+		params.SeverityLevel = defaultHeartbeatSeverity
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return models.Heartbeat{}, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.Create(params)
+	if err != nil {
+		return models.Heartbeat{}, fmt.Errorf("tx.Create: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Heartbeat{}, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return ret, nil
+}
+
+func (s *HeartbeatsService) Read(ctx context.Context) ([]models.Heartbeat, error) {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tx.Read: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return ret, nil
+}
+
+// Delete resolves the alerts of the heartbeat without notifying.
+func (s *HeartbeatsService) Delete(ctx context.Context, heartbeatID int32) error {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.Delete(ctx, heartbeatID)
+	if err != nil {
+		return fmt.Errorf("tx.Delete: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+// CheckHeartbeats raises an alert for every device that sent no expected
+// message within the interval of a heartbeat, and resolves it once the
+// device sent one again. Only the replica holding the heartbeats lease
+// checks, the others return nothing. A heartbeat is first missed one
+// interval after it was created.
+func (ms *MessagesService) CheckHeartbeats() []DeviceNotification {
+	if ms.heartbeatRepo == nil || ms.leaseRepo == nil {
+		return nil
+	}
+
+	owner, err := ms.leaseRepo.Acquire(heartbeatsLease, ms.instance)
+	if err != nil {
+		ms.log.Warn("ms.leaseRepo.Acquire", zap.Error(err))
+		return nil
+	}
+	if !owner {
+		return nil
+	}
+
+	ctx := context.Background()
+	tx, err := ms.heartbeatRepo.BeginTx(ctx)
+	if err != nil {
+		ms.log.Error("ms.heartbeatRepo.BeginTx", zap.Error(err))
+		return nil
+	}
+	defer tx.Rollback()
+
+	heartbeats, err := tx.Read(ctx)
+	if err != nil {
+		ms.log.Error("tx.Read", zap.Error(err))
+		return nil
+	}
+
+	now := time.Now()
+
+	var notifications []DeviceNotification
+	for _, heartbeat := range heartbeats {
+		interval := time.Duration(heartbeat.Interval) * time.Second
+		if now.Sub(heartbeat.CreatedAt) < interval {
+			continue
+		}
+
+		seen, err := tx.Seen(ctx, heartbeat.MessageType, now.Add(-interval))
+		if err != nil {
+			ms.log.Error("tx.Seen", zap.Error(err), zap.Int32("heartbeat id", heartbeat.ID))
+			continue
+		}
+
+		for _, deviceID := range ms.heartbeatDevices(heartbeat) {
+			_, alive := seen[deviceID]
+			notify, ok := ms.heartbeatAlert(heartbeat, deviceID, alive)
+			if !ok {
+				continue
+			}
+			if _, silenced := ms.silenceOf(models.Message{DeviceId: deviceID}, heartbeat.Name); silenced {
+				continue
+			}
+
+			for _, n := range ms.suppressRepeats(deviceID, []CreateMessageResponse{notify}) {
+				notifications = append(notifications, DeviceNotification{DeviceID: deviceID, Notification: n})
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		ms.log.Error("tx.Commit", zap.Error(err))
+	}
+
+	return notifications
+}
+
+// heartbeatDevices are the known devices of a device type heartbeat.
+func (ms *MessagesService) heartbeatDevices(heartbeat models.Heartbeat) []int32 {
+	if heartbeat.DeviceId != 0 {
+		return []int32{heartbeat.DeviceId}
+	}
+
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	var deviceIDs []int32
+	for id, device := range deviceByDeviceID {
+		if device.DeviceType == heartbeat.DeviceType {
+			deviceIDs = append(deviceIDs, id)
+		}
+	}
+
+	return deviceIDs
+}
+
+// heartbeatAlert opens the alert of a missed heartbeat or resolves it, ok is
+// false if nothing changed.
+func (ms *MessagesService) heartbeatAlert(heartbeat models.Heartbeat, deviceID int32, alive bool) (CreateMessageResponse, bool) {
+	tx, err := ms.alertRepo.BeginTx(context.Background())
+	if err != nil {
+		ms.log.Error("ms.alertRepo.BeginTx", zap.Error(err))
+		return CreateMessageResponse{}, false
+	}
+	defer tx.Rollback()
+
+	_, active, err := tx.Active(models.AlertRuleHeartbeat, heartbeat.ID, deviceID)
+	if err != nil {
+		ms.log.Error("tx.Active", zap.Error(err), zap.Int32("heartbeat id", heartbeat.ID))
+		return CreateMessageResponse{}, false
+	}
+	if alive != active {
+		return CreateMessageResponse{}, false
+	}
+
+	interval := time.Duration(heartbeat.Interval) * time.Second
+	notify := CreateMessageResponse{
+		SourceType: models.AlertRuleHeartbeat,
+		SourceID:   heartbeat.ID,
+		SourceName: heartbeat.Name,
+	}
+
+	var alert models.Alert
+	if alive {
+		alert, _, err = tx.Recover(models.AlertRuleHeartbeat, heartbeat.ID, deviceID)
+		if err != nil {
+			ms.log.Error("tx.Recover", zap.Error(err), zap.Int32("heartbeat id", heartbeat.ID))
+			return CreateMessageResponse{}, false
+		}
+		notify.Subject = RecoveredSubject
+		notify.Text = fmt.Sprintf("%s: messages resumed", heartbeat.Name)
+	} else {
+		text := fmt.Sprintf("%s: no message within %s", heartbeat.Name, interval)
+		if heartbeat.MessageType != "" {
+			text = fmt.Sprintf("%s: no %s message within %s", heartbeat.Name, heartbeat.MessageType, interval)
+		}
+		alert, _, err = tx.Raise(models.Alert{
+			RuleType:      models.AlertRuleHeartbeat,
+			RuleID:        heartbeat.ID,
+			DeviceId:      deviceID,
+			Subject:       heartbeat.Subject,
+			SeverityLevel: heartbeat.SeverityLevel,
+			Message:       text,
+		})
+		if err != nil {
+			ms.log.Error("tx.Raise", zap.Error(err), zap.Int32("heartbeat id", heartbeat.ID))
+			return CreateMessageResponse{}, false
+		}
+		notify.Subject = heartbeat.Subject
+		notify.Text = text
+	}
+
+	if err = tx.Commit(); err != nil {
+		ms.log.Error("tx.Commit", zap.Error(err))
+		return CreateMessageResponse{}, false
+	}
+
+	notify.AlertID = alert.ID
+
+	ms.log.Info("heartbeat alert", zap.Int32("alert id", alert.ID), zap.String("state", alert.State),
+		zap.Int32("heartbeat id", heartbeat.ID), zap.Int32("device id", deviceID))
+
+	return notify, true
+}
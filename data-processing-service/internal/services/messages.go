@@ -5,56 +5,145 @@ import (
 	"data-processing-service/internal/models"
 	"data-processing-service/internal/repo"
 	"fmt"
+	"os"
 	"regexp"
 	"strconv"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 	"github.com/samber/lo"
 	"go.uber.org/zap"
 )
 
 type Messages interface {
-	SetDevices([]models.Device)
+	UpsertDevice(device models.Device)
+	DeleteDevice(deviceID int32)
+	SetDeviceLoader(loader DeviceLoader)
 	UpdateTags()
-	Create(opts models.Message) (CreateMessageResponse, bool, error)
+	TagsChanged()
+	RefreshTags(version int64)
+	CheckTagsVersion()
+	TagsVersion() int64
+	SetTagsPublisher(publisher TagsPublisher)
+	UpdateRules()
+	RulesChanged()
+	SetRulesPublisher(publisher RulesPublisher)
+	UpdateSilences()
+	SilencesChanged()
+	SetSilencesPublisher(publisher SilencesPublisher)
+	SettleFlapping() []DeviceNotification
+	CheckHeartbeats() []DeviceNotification
+	FlushSuppressed() []DeviceNotification
+	Create(opts models.Message) ([]CreateMessageResponse, error)
+	TestTag(opts TestTagOpts) ([]TestTagResult, error)
 	GetAllByPeriod(opts MessagesGetAllByPeriodOpts) ([]ReportGetAllByPeriod, error)
-	GetAllByDeviceId(deviceID int32) ([]ReportGetAllByDeviceId, error)
+	GetAllByDeviceId(opts MessagesGetAllByDeviceIdOpts) ([]ReportGetAllByDeviceId, error)
 	GetCountByMessageType(messageType string) ([]ReportGetCountByMessageType, error)
 	MonthReport() ([]models.MonthReportRow, error)
 }
 type MessagesService struct {
 	messageRepo        repo.Messages
 	tagRepo            repo.Tags
+	ruleRepo           repo.Rules
+	alertRepo          repo.Alerts
+	silenceRepo        repo.Silences
+	heartbeatRepo      repo.Heartbeats
+	leaseRepo          repo.Leases
+	windowRepo         repo.Windows
+	baselineRepo       repo.Baselines
+	notificationRepo   repo.Notifications
 	cron               *cron.Cron
 	notificationPeriod time.Duration
+	flapWindow         time.Duration
+	flapThreshold      int
+	// instance is the owner of the leases this replica takes.
+	instance string
 
 	log *zap.Logger
 }
 
 type Config struct {
-	MessageRepo        repo.Messages
-	TagRepo            repo.Tags
+	MessageRepo repo.Messages
+	TagRepo     repo.Tags
+	RuleRepo    repo.Rules
+	AlertRepo   repo.Alerts
+	SilenceRepo repo.Silences
+	// HeartbeatRepo is checked by the replica holding its lease in
+	// LeaseRepo, without both heartbeats are not checked.
+	HeartbeatRepo repo.Heartbeats
+	LeaseRepo     repo.Leases
+	// WindowRepo checkpoints windowed rules, without it they are kept in
+	// memory only.
+	WindowRepo repo.Windows
+	// BaselineRepo keeps the baselines of anomaly rules, without it they
+	// never fire.
+	BaselineRepo repo.Baselines
+	// NotificationRepo shares the sent notifications between replicas,
+	// without it every replica suppresses its own repeats.
+	NotificationRepo repo.Notifications
+	// NotificationPeriod is how long repeats of a notification are
+	// suppressed, 0 sends every one.
 	NotificationPeriod time.Duration
-	Log                *zap.Logger
+	// FlapThreshold changes between raised and recovered within FlapWindow
+	// make an alert FLAPPING, 0 turns flap detection off.
+	FlapWindow    time.Duration
+	FlapThreshold int
+	Log           *zap.Logger
+}
+
+// InstanceName tells the replicas apart, it is the hostname or a random id if
+// there is none.
+func InstanceName(log *zap.Logger) string {
+	instance, err := os.Hostname()
+	if err != nil || instance == "" {
+		instance = uuid.NewString()
+		log.Warn("os.Hostname", zap.Error(err), zap.String("instance", instance))
+	}
+
+	return instance
 }
 
 func NewMessagesService(cfg Config) Messages {
+	instance := InstanceName(cfg.Log)
+
 	messagesService := &MessagesService{
-		messageRepo: cfg.MessageRepo,
-		tagRepo:     cfg.TagRepo,
-		log:         cfg.Log,
+		messageRepo:        cfg.MessageRepo,
+		tagRepo:            cfg.TagRepo,
+		ruleRepo:           cfg.RuleRepo,
+		alertRepo:          cfg.AlertRepo,
+		silenceRepo:        cfg.SilenceRepo,
+		heartbeatRepo:      cfg.HeartbeatRepo,
+		leaseRepo:          cfg.LeaseRepo,
+		windowRepo:         cfg.WindowRepo,
+		baselineRepo:       cfg.BaselineRepo,
+		notificationRepo:   cfg.NotificationRepo,
+		notificationPeriod: cfg.NotificationPeriod,
+		flapWindow:         cfg.FlapWindow,
+		flapThreshold:      cfg.FlapThreshold,
+		instance:           instance,
+		log:                cfg.Log,
 	}
 	messagesService.UpdateTags()
+	messagesService.UpdateRules()
+	messagesService.UpdateSilences()
 	return messagesService
 }
 
 var (
 	devicesMutex     sync.Mutex
 	deviceByDeviceID = map[int32]models.Device{}
+	// Ids the loader did not find, they are not asked for again until the
+	// device shows up.
+	missingDeviceIDs = map[int32]struct{}{}
+	deviceLoader     DeviceLoader
 )
 
+// DeviceLoader reads a device missing from the local cache from the source
+// of the cache, ok is false if there is no such device.
+type DeviceLoader func(deviceID int32) (device models.Device, ok bool, err error)
+
 var defaultDevice = models.Device{
 	ID:          -1,
 	Name:        "unknown device",
@@ -63,23 +152,119 @@ var defaultDevice = models.Device{
 	Responsible: []int32{},
 }
 
-func (s *MessagesService) SetDevices(newDevices []models.Device) {
+func (s *MessagesService) UpsertDevice(device models.Device) {
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	deviceByDeviceID[device.ID] = device
+	delete(missingDeviceIDs, device.ID)
+
+	s.log.Debug("upsert device", zap.Any("device", device))
+}
+
+func (s *MessagesService) DeleteDevice(deviceID int32) {
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	delete(deviceByDeviceID, deviceID)
+
+	s.log.Debug("delete device", zap.Int32("device_id", deviceID))
+}
+
+func (s *MessagesService) SetDeviceLoader(loader DeviceLoader) {
+	devicesMutex.Lock()
+	defer devicesMutex.Unlock()
+
+	deviceLoader = loader
+}
+
+// deviceByID reads through to the loader on a miss, a device created a moment
+// ago may not have reached the watcher yet.
+func (s *MessagesService) deviceByID(deviceID int32) (models.Device, bool) {
+	devicesMutex.Lock()
+	device, ok := deviceByDeviceID[deviceID]
+	_, missing := missingDeviceIDs[deviceID]
+	loader := deviceLoader
+	devicesMutex.Unlock()
+
+	if ok || missing || loader == nil {
+		return device, ok
+	}
+
+	device, ok, err := loader(deviceID)
+	if err != nil {
+		s.log.Warn("deviceLoader", zap.Error(err), zap.Int32("device_id", deviceID))
+		return models.Device{}, false
+	}
+
 	devicesMutex.Lock()
 	defer devicesMutex.Unlock()
-	deviceByDeviceID = make(map[int32]models.Device)
-	for _, device := range newDevices {
-		deviceByDeviceID[device.ID] = device
+
+	if !ok {
+		missingDeviceIDs[deviceID] = struct{}{}
+		return models.Device{}, false
+	}
+
+	// The watcher may have been faster and its entry is the newer one.
+	if cached, found := deviceByDeviceID[deviceID]; found {
+		return cached, true
 	}
+	deviceByDeviceID[deviceID] = device
 
-	s.log.Debug("set devices", zap.Any("devices", deviceByDeviceID))
+	return device, true
 }
 
 var (
 	tagsMutex sync.Mutex
 	tags      = map[int32][]models.Tag{}
+	// tagsVersion is the version of the tag set loaded into tags.
+	tagsVersion   int64
+	tagsPublisher TagsPublisher
 )
 
-func (ms *MessagesService) UpdateTags() {
+// TagsPublisher announces a new version of the tag set to every replica.
+type TagsPublisher func(version int64)
+
+func (ms *MessagesService) SetTagsPublisher(publisher TagsPublisher) {
+	tagsMutex.Lock()
+	defer tagsMutex.Unlock()
+
+	tagsPublisher = publisher
+}
+
+func (ms *MessagesService) TagsVersion() int64 {
+	tagsMutex.Lock()
+	defer tagsMutex.Unlock()
+
+	return tagsVersion
+}
+
+// TagsChanged reloads the tags after a change made by this replica and
+// announces the version, the other replicas reload on it.
+func (ms *MessagesService) TagsChanged() {
+	ms.UpdateTags()
+
+	tagsMutex.Lock()
+	version, publisher := tagsVersion, tagsPublisher
+	tagsMutex.Unlock()
+
+	if publisher != nil {
+		publisher(version)
+	}
+}
+
+// RefreshTags reloads the tags unless the loaded ones are at least version.
+func (ms *MessagesService) RefreshTags(version int64) {
+	if ms.TagsVersion() >= version {
+		return
+	}
+
+	ms.UpdateTags()
+}
+
+// CheckTagsVersion reloads the tags if they changed without this replica
+// hearing of it.
+func (ms *MessagesService) CheckTagsVersion() {
 	tx, err := ms.tagRepo.BeginTx(context.Background())
 	if err != nil {
 		ms.log.Error("tx.BeginTx", zap.Error(err))
@@ -87,156 +272,590 @@ func (ms *MessagesService) UpdateTags() {
 	}
 	defer tx.Rollback()
 
-	tagsMutex.Lock()
-	defer tagsMutex.Unlock()
+	version, err := tx.Version(context.Background())
+	if err != nil {
+		ms.log.Error("tx.Version", zap.Error(err))
+		return
+	}
+
+	ms.RefreshTags(version)
+}
 
-	tags = make(map[int32][]models.Tag)
+func (ms *MessagesService) UpdateTags() {
+	tx, err := ms.tagRepo.BeginTx(context.Background())
+	if err != nil {
+		ms.log.Error("tx.BeginTx", zap.Error(err))
+		return
+	}
+	defer tx.Rollback()
+
+	// The loaded tags stay in use if the read fails.
 	dbTags, err := tx.Read(context.Background())
 	if err != nil {
 		ms.log.Error("ms.tagRepo.Read", zap.Error(err))
 		return
 	}
 
+	loaded := make(map[int32][]models.Tag)
 	for _, dbTag := range dbTags.Tags {
-		switch dbTag.CompareType {
-		case "=":
-			dbTag.CompareFunc = func(a string, b string) bool { return a == b }
-		case "<":
-			dbTag.CompareFunc = func(a string, b string) bool {
-				first, err := strconv.ParseFloat(a, 64)
-				if err != nil {
-					ms.log.Error("strconv.ParseFloat", zap.Error(err))
-				}
-
-				second, err := strconv.ParseFloat(b, 64)
-				if err != nil {
-					ms.log.Error("strconv.ParseFloat", zap.Error(err))
-				}
-				return first < second
+		if err = ms.compileTag(&dbTag); err != nil {
+			ms.log.Error("ms.compileTag", zap.Error(err), zap.Int32("tag id", dbTag.ID))
+			continue
+		}
+
+		loaded[dbTag.DeviceId] = append(loaded[dbTag.DeviceId], dbTag)
+	}
+
+	tagsMutex.Lock()
+	tags = loaded
+	tagsVersion = dbTags.Version
+	tagsMutex.Unlock()
+
+	ms.log.Info("tags loaded", zap.Int64("version", dbTags.Version), zap.Int("tags", len(dbTags.Tags)))
+}
+
+// compileTag sets the compare func and the compiled regexp of a tag.
+func (ms *MessagesService) compileTag(tag *models.Tag) error {
+	switch tag.CompareType {
+	case "=":
+		tag.CompareFunc = func(a string, b string) bool { return a == b }
+	case "<":
+		tag.CompareFunc = func(a string, b string) bool {
+			first, err := strconv.ParseFloat(a, 64)
+			if err != nil {
+				ms.log.Error("strconv.ParseFloat", zap.Error(err))
 			}
 
-		case ">":
-			dbTag.CompareFunc = func(a string, b string) bool {
-				first, err := strconv.ParseFloat(a, 64)
-				if err != nil {
-					ms.log.Error("strconv.ParseFloat", zap.Error(err))
-				}
-
-				second, err := strconv.ParseFloat(b, 64)
-				if err != nil {
-					ms.log.Error("strconv.ParseFloat", zap.Error(err))
-				}
-				return first > second
+			second, err := strconv.ParseFloat(b, 64)
+			if err != nil {
+				ms.log.Error("strconv.ParseFloat", zap.Error(err))
 			}
-		default:
-			ms.log.Error("unknown compare type", zap.String("compare type", dbTag.CompareType))
-			continue
+			return first < second
 		}
 
-		dbTag.CompiledRegexp, err = regexp.Compile(dbTag.Regexp)
+	case ">":
+		tag.CompareFunc = func(a string, b string) bool {
+			first, err := strconv.ParseFloat(a, 64)
+			if err != nil {
+				ms.log.Error("strconv.ParseFloat", zap.Error(err))
+			}
+
+			second, err := strconv.ParseFloat(b, 64)
+			if err != nil {
+				ms.log.Error("strconv.ParseFloat", zap.Error(err))
+			}
+			return first > second
+		}
+	default:
+		return fmt.Errorf("%w: unknown compare type %q", ErrInvalidTag, tag.CompareType)
+	}
+
+	compiled, err := regexp.Compile(tag.Regexp)
+	if err != nil {
+		return fmt.Errorf("%w: regexp.Compile: %w", ErrInvalidTag, err)
+	}
+	if tag.ArrayIndex < 0 || int(tag.ArrayIndex) > compiled.NumSubexp() {
+		return fmt.Errorf("%w: array_index %d out of range for %q", ErrInvalidTag, tag.ArrayIndex, tag.Regexp)
+	}
+	tag.CompiledRegexp = compiled
+
+	return nil
+}
+
+type compiledRule struct {
+	rule      models.Rule
+	condition condition
+	// window and anomaly are nil for a rule that fires on every matching
+	// message.
+	window  *compiledWindow
+	anomaly *compiledAnomaly
+}
+
+var (
+	rulesMutex sync.Mutex
+	// Rules by device id, the ones for every device are under 0.
+	rules          = map[int32][]compiledRule{}
+	rulesPublisher RulesPublisher
+)
+
+// RulesPublisher tells every replica to reload the rules.
+type RulesPublisher func()
+
+func (ms *MessagesService) SetRulesPublisher(publisher RulesPublisher) {
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+
+	rulesPublisher = publisher
+}
+
+// RulesChanged reloads the rules after a change made by this replica and
+// tells the other replicas to reload them too.
+func (ms *MessagesService) RulesChanged() {
+	ms.UpdateRules()
+
+	rulesMutex.Lock()
+	publisher := rulesPublisher
+	rulesMutex.Unlock()
+
+	if publisher != nil {
+		publisher()
+	}
+}
+
+// UpdateRules reloads the rules, one that does not compile is logged and
+// skipped.
+func (ms *MessagesService) UpdateRules() {
+	tx, err := ms.ruleRepo.BeginTx(context.Background())
+	if err != nil {
+		ms.log.Error("tx.BeginTx", zap.Error(err))
+		return
+	}
+	defer tx.Rollback()
+
+	dbRules, err := tx.Read(context.Background())
+	if err != nil {
+		ms.log.Error("ms.ruleRepo.Read", zap.Error(err))
+		return
+	}
+
+	newRules := make(map[int32][]compiledRule)
+	windowed := make(map[int32]struct{})
+	for _, dbRule := range dbRules.Rules {
+		cond, err := compileRule(dbRule.Op, dbRule.Conditions)
 		if err != nil {
-			ms.log.Error("regexp.Compile", zap.Error(err))
+			ms.log.Error("compileRule", zap.Error(err), zap.Int32("rule id", dbRule.ID))
 			continue
 		}
 
-		if tags[dbTag.DeviceId] == nil {
-			tags[dbTag.DeviceId] = make([]models.Tag, 0)
+		var window *compiledWindow
+		if dbRule.Window != nil {
+			window, err = compileWindow(*dbRule.Window)
+			if err != nil {
+				ms.log.Error("compileWindow", zap.Error(err), zap.Int32("rule id", dbRule.ID))
+				continue
+			}
+			windowed[dbRule.ID] = struct{}{}
 		}
-		tags[dbTag.DeviceId] = append(tags[dbTag.DeviceId], dbTag)
+
+		var anomaly *compiledAnomaly
+		if dbRule.Anomaly != nil {
+			if window != nil {
+				ms.log.Error("rule with window and anomaly", zap.Int32("rule id", dbRule.ID))
+				continue
+			}
+			anomaly, err = compileAnomaly(*dbRule.Anomaly)
+			if err != nil {
+				ms.log.Error("compileAnomaly", zap.Error(err), zap.Int32("rule id", dbRule.ID))
+				continue
+			}
+		}
+
+		newRules[dbRule.DeviceId] = append(newRules[dbRule.DeviceId], compiledRule{
+			rule:      dbRule,
+			condition: cond,
+			window:    window,
+			anomaly:   anomaly,
+		})
+	}
+
+	rulesMutex.Lock()
+	rules = newRules
+	rulesMutex.Unlock()
+
+	dropWindows(windowed)
+}
+
+type ruleMatch struct {
+	rule models.Rule
+	text string
+}
+
+// matchingRules evaluates every rule of the device and the ones for all
+// devices. Windowed and anomaly rules are left to firingRules.
+func (ms *MessagesService) matchingRules(message models.Message) []ruleMatch {
+	var matched []ruleMatch
+	for _, r := range ruleCandidates(message.DeviceId) {
+		if r.window == nil && r.anomaly == nil && r.condition(message) {
+			matched = append(matched, ruleMatch{rule: r.rule, text: message.Message})
+		}
+	}
+
+	return matched
+}
+
+// firingRules adds the message to the windows and baselines of the windowed
+// and anomaly rules it passes and returns the ones that fire. It runs once
+// the message is stored, a message that is not must not be counted.
+func (ms *MessagesService) firingRules(message models.Message) []ruleMatch {
+	var matched []ruleMatch
+	for _, r := range ruleCandidates(message.DeviceId) {
+		if (r.window == nil && r.anomaly == nil) || !r.condition(message) {
+			continue
+		}
+
+		var fired bool
+		var text string
+		if r.window != nil {
+			fired, text = ms.observeWindow(r, message)
+		} else {
+			fired, text = ms.observeAnomaly(r, message)
+		}
+		if fired {
+			matched = append(matched, ruleMatch{rule: r.rule, text: text})
+		}
+	}
+
+	return matched
+}
+
+func ruleCandidates(deviceID int32) []compiledRule {
+	rulesMutex.Lock()
+	defer rulesMutex.Unlock()
+
+	return append(append([]compiledRule{}, rules[deviceID]...), rules[0]...)
+}
+
+var severityRank = map[string]int{
+	"info":     0,
+	"warning":  1,
+	"error":    2,
+	"critical": 3,
+}
+
+// higherSeverity keeps the more severe of two levels, unknown levels rank as
+// info.
+func higherSeverity(current string, candidate string) string {
+	if current == "" || severityRank[candidate] > severityRank[current] {
+		return candidate
 	}
+	return current
 }
 
 type (
 	CreateMessageResponse struct {
 		Text    string
 		Subject string
+		// SourceType, SourceID and SourceName are the tag or rule that
+		// matched.
+		SourceType string
+		SourceID   int32
+		SourceName string
+		// AlertID is the alert the notification is about, 0 if none. A
+		// new alert of the same source is not a repeat of the previous one.
+		AlertID int32
+		// Suppressed is how many repeats were not sent before it.
+		Suppressed int32
 	}
 )
 
-func (ms *MessagesService) Create(opts models.Message) (CreateMessageResponse, bool, error) {
-	resp, err := ms.handleMessage(opts)
-	if err != nil {
-		return CreateMessageResponse{}, false, fmt.Errorf("ms.handleMessage: %w", err)
-	}
-
+// Create skips a message already stored under the same client MessageID,
+// a retry must neither be saved twice nor trigger tags again. It returns one
+// notification per matched tag or rule, except for the silenced ones and the
+// repeats within the notification period. A silenced message is still stored.
+// Tags and rules only see a message once it is stored, alerts are raised or
+// recovered and its severity and silence are written in the same transaction.
+func (ms *MessagesService) Create(opts models.Message) ([]CreateMessageResponse, error) {
 	tx, err := ms.messageRepo.BeginTx(context.Background())
 	if err != nil {
 		ms.log.Error("tx.BeginTx", zap.Error(err))
-		return CreateMessageResponse{}, false, fmt.Errorf("ms.messageRepo.Create: %w", err)
+		return nil, fmt.Errorf("ms.messageRepo.Create: %w", err)
 	}
 	defer tx.Rollback()
 
-	if err := tx.Create(resp.Message); err != nil {
-		return CreateMessageResponse{}, false, fmt.Errorf("tx.Create: %w", err)
+	if opts.MessageID != nil {
+		exists, err := tx.Exists(opts.DeviceId, *opts.MessageID)
+		if err != nil {
+			return nil, fmt.Errorf("tx.Exists: %w", err)
+		}
+		if exists {
+			return nil, nil
+		}
+	}
+
+	id, inserted, err := tx.Create(opts)
+	if err != nil {
+		return nil, fmt.Errorf("tx.Create: %w", err)
+	}
+	if !inserted {
+		return nil, nil
+	}
+	stored := opts
+	stored.Id = id
+
+	resp, err := ms.handleMessage(stored, tx.Alerts(), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("ms.handleMessage: %w", err)
+	}
+
+	message := resp.Message
+	notifications := append(resp.Notifications, ruleNotifications(&message, ms.firingRules(message))...)
+	notifications = ms.silence(&message, notifications)
+
+	if message.SeverityLevel != stored.SeverityLevel || message.SilenceID != stored.SilenceID {
+		if err = tx.Update(message); err != nil {
+			return nil, fmt.Errorf("tx.Update: %w", err)
+		}
 	}
 
 	if err = tx.Commit(); err != nil {
-		return CreateMessageResponse{}, false, fmt.Errorf("tx.Commit: %w", err)
+		return nil, fmt.Errorf("tx.Commit: %w", err)
 	}
 
-	return CreateMessageResponse{
-		Subject: resp.Subject,
-		Text:    resp.Text,
-	}, resp.NeedNotify, nil
+	return ms.suppressRepeats(message.DeviceId, notifications), nil
 }
 
 type handleMessageResponse struct {
-	Text       string
-	Subject    string
-	NeedNotify bool
-	Message    models.Message
+	Notifications []CreateMessageResponse
+	Message       models.Message
+	// Tag is the tag that handled the message, nil if none did.
+	Tag *tagMatch
+}
+
+// handleMessage evaluates the message against the current tags of its device.
+func (ms *MessagesService) handleMessage(message models.Message, alerts alertState, now time.Time) (handleMessageResponse, error) {
+	tagsMutex.Lock()
+	deviceTags := tags[message.DeviceId]
+	tagsMutex.Unlock()
+
+	return ms.evaluateMessage(deviceTags, message, alerts, now, ms.log)
 }
 
-func (ms *MessagesService) handleMessage(message models.Message) (handleMessageResponse, error) {
-	for _, tag := range tags[message.DeviceId] {
+// evaluateMessage stops at the first tag whose regexp matches but evaluates
+// every stateless rule, the message gets the highest severity of what
+// matched. A threshold tag notifies only the values that raise or recover its
+// alert in alerts, the notification of a tag whose alert is flapping is held
+// back. It touches no state but alerts, so TestTag runs it as well.
+func (ms *MessagesService) evaluateMessage(deviceTags []models.Tag, message models.Message, alerts alertState, now time.Time, log *zap.Logger) (handleMessageResponse, error) {
+	var resp handleMessageResponse
+
+	if match, ok := matchTag(deviceTags, message); ok {
+		resp.Tag = &match
+
+		notify := CreateMessageResponse{
+			Text:       message.Message,
+			Subject:    match.tag.Subject,
+			SourceType: models.AlertRuleTag,
+			SourceID:   match.tag.ID,
+			SourceName: match.tag.Name,
+		}
+		if match.passed {
+			message.SeverityLevel = match.tag.SeverityLevel
+		}
+
+		change := alertNotified
+		switch {
+		case !isThresholdTag(match.tag):
+		case match.passed || recovered(match.tag, match.value):
+			var (
+				text string
+				err  error
+			)
+			change, notify.AlertID, text, err = ms.tagAlert(log, alerts, match, message, now)
+			if err != nil {
+				return handleMessageResponse{}, fmt.Errorf("ms.tagAlert: %w", err)
+			}
+			switch change {
+			case alertRecovered:
+				notify.Subject = RecoveredSubject
+				message.SeverityLevel = "info"
+			case alertFlappingStarted:
+				notify.Subject = FlappingSubject
+				notify.Text = text
+			}
+		default:
+			// A value equal to the threshold neither raises nor recovers.
+			change = alertUnchanged
+		}
+
+		if change != alertHeld && change != alertUnchanged {
+			resp.Notifications = append(resp.Notifications, notify)
+		}
+	}
+
+	resp.Notifications = append(resp.Notifications, ruleNotifications(&message, ms.matchingRules(message))...)
+	resp.Message = message
+
+	return resp, nil
+}
+
+// ruleNotifications raises the severity of the message to the one of every
+// matched rule and returns their notifications.
+func ruleNotifications(message *models.Message, matched []ruleMatch) []CreateMessageResponse {
+	var notifications []CreateMessageResponse
+	for _, match := range matched {
+		message.SeverityLevel = higherSeverity(message.SeverityLevel, match.rule.SeverityLevel)
+		notifications = append(notifications, CreateMessageResponse{
+			Text:       match.text,
+			Subject:    match.rule.Subject,
+			SourceType: NotifySourceRule,
+			SourceID:   match.rule.ID,
+			SourceName: match.rule.Name,
+		})
+	}
+
+	return notifications
+}
+
+type tagMatch struct {
+	tag models.Tag
+	// value is the submatch the tag compares.
+	value  string
+	passed bool
+}
+
+// matchTag finds the first tag whose regexp matches the message, ok is false
+// if there is none.
+func matchTag(deviceTags []models.Tag, message models.Message) (tagMatch, bool) {
+	for _, tag := range deviceTags {
 		finded := tag.CompiledRegexp.FindStringSubmatch(message.Message)
 		if len(finded) == 0 {
 			continue
 		}
-		if tag.Compare(finded[tag.ArrayIndex]) {
-			if tag.CompareType == ">" || tag.CompareType == "<" {
-				ms.handleReversedTag(tag)
-			}
-			message.SeverityLevel = tag.SeverityLevel
-		}
-		return handleMessageResponse{
-			Text:       message.Message,
-			Subject:    tag.Subject,
-			NeedNotify: true,
-			Message:    message,
-		}, nil
+		return tagMatch{
+			tag:    tag,
+			value:  finded[tag.ArrayIndex],
+			passed: tag.Compare(finded[tag.ArrayIndex]),
+		}, true
 	}
 
-	return handleMessageResponse{Message: message, NeedNotify: false}, nil
+	return tagMatch{}, false
 }
 
-func (ms *MessagesService) handleReversedTag(tag models.Tag) {
-	if tag.Subject == "OK" {
-		err := ms.tagRepo.Delete(context.Background(), tag.ID)
+// maxTagTestMessages bounds the stored messages a tag is tested against.
+const maxTagTestMessages = 1000
+
+type (
+	TestTagOpts struct {
+		Tag models.Tag
+		// Messages are tested as sent by the device of the tag. Without them
+		// the stored messages of the device from StartTime to EndTime are.
+		Messages  []models.Message
+		StartTime time.Time
+		EndTime   time.Time
+	}
+	TestTagResult struct {
+		// Message has the severity it would be stored with.
+		Message models.Message
+		// Matched is set if the tag would handle the message, an earlier tag
+		// of the device may handle it first.
+		Matched       bool
+		Value         string
+		Passed        bool
+		Notifications []CreateMessageResponse
+	}
+)
+
+// TestTag runs the messages through the tags of the device with the draft
+// tag in place of the stored one of the same ID, or added after them for a
+// new tag. It decides like Create, with silences and the repeats within the
+// notification period, but against alerts kept in memory that start out
+// resolved. It has no side effects, windowed and anomaly rules are left out.
+func (ms *MessagesService) TestTag(opts TestTagOpts) ([]TestTagResult, error) {
+	draft := opts.Tag
+	if err := ms.compileTag(&draft); err != nil {
+		return nil, err
+	}
+
+	messages := opts.Messages
+	if len(messages) == 0 {
+		var err error
+		messages, err = ms.storedMessages(draft.DeviceId, opts.StartTime, opts.EndTime)
 		if err != nil {
-			ms.log.Error("ms.tagRepo.Delete", zap.Error(err), zap.Int32("tag id", tag.ID))
+			return nil, fmt.Errorf("ms.storedMessages: %w", err)
 		}
-	} else {
-		reversedTag := tag
-		reversedTag.Subject = "OK"
-		reversedTag.SeverityLevel = "info"
-		switch tag.CompareType {
-		case ">":
-			reversedTag.CompareType = "<"
-		case "<":
-			reversedTag.CompareType = ">"
+	}
+	if len(messages) > maxTagTestMessages {
+		messages = messages[:maxTagTestMessages]
+	}
+
+	tagsMutex.Lock()
+	deviceTags := make([]models.Tag, 0, len(tags[draft.DeviceId])+1)
+	replaced := false
+	for _, tag := range tags[draft.DeviceId] {
+		if draft.ID != 0 && tag.ID == draft.ID {
+			tag = draft
+			replaced = true
 		}
-		_, err := ms.tagRepo.Create(reversedTag)
+		deviceTags = append(deviceTags, tag)
+	}
+	tagsMutex.Unlock()
+	if !replaced {
+		deviceTags = append(deviceTags, draft)
+	}
+
+	alerts := newMemoryAlerts()
+	sended := map[string]models.SendedNotification{}
+
+	results := make([]TestTagResult, 0, len(messages))
+	for _, message := range messages {
+		message.DeviceId = draft.DeviceId
+		now := testTime(message)
+		alerts.now = now
+
+		resp, err := ms.evaluateMessage(deviceTags, message, alerts, now, zap.NewNop())
 		if err != nil {
-			ms.log.Error("ms.tagRepo.Create", zap.Error(err), zap.Any("tag", reversedTag))
+			return nil, fmt.Errorf("ms.evaluateMessage: %w", err)
 		}
+
+		message = resp.Message
+		notifications := ms.silence(&message, resp.Notifications)
+		notifications = ms.dropRepeats(notifications, func(notify CreateMessageResponse) (int32, bool) {
+			key := notificationKey(message.DeviceId, notify)
+			next, suppressed, send := ms.nextSended(sended[key], message.DeviceId, notify, now)
+			sended[key] = next
+			return suppressed, send
+		})
+
+		result := TestTagResult{Message: message, Notifications: notifications}
+		if resp.Tag != nil && resp.Tag.tag.ID == draft.ID {
+			result.Matched = true
+			result.Value = resp.Tag.value
+			result.Passed = resp.Tag.passed
+		}
+		results = append(results, result)
 	}
+
+	return results, nil
+}
+
+// testTime is when a tested message arrived, messages that were not stored
+// arrive now.
+func testTime(message models.Message) time.Time {
+	switch {
+	case message.ReceivedAt != nil:
+		return *message.ReceivedAt
+	case !message.GotAt.IsZero():
+		return message.GotAt
+	}
+	return time.Now()
+}
+
+func (ms *MessagesService) storedMessages(deviceID int32, start time.Time, end time.Time) ([]models.Message, error) {
+	tx, err := ms.messageRepo.BeginTx(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("ms.messageRepo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	messages, err := tx.GetByDeviceAndPeriod(repo.MessagesGetByDeviceAndPeriodOpts{
+		DeviceID:  deviceID,
+		StartTime: start,
+		EndTime:   end,
+		Limit:     maxTagTestMessages,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tx.GetByDeviceAndPeriod: %w", err)
+	}
+
+	return messages, nil
 }
 
 type (
 	MessagesGetAllByPeriodOpts struct {
 		StartTime time.Time
 		EndTime   time.Time
+		// ByEventTime filters and orders by the device event time.
+		ByEventTime bool
 	}
 	ReportGetAllByPeriod struct {
 		DeviceID    int32
@@ -247,6 +866,12 @@ type (
 		GotAt       time.Time
 		Message     string
 		MessageType string
+		EventTime   *time.Time
+		ReceivedAt  *time.Time
+		Attributes  map[string]string
+		// Silenced messages did not notify, SilenceID is the silence.
+		Silenced  bool
+		SilenceID int32
 	}
 )
 
@@ -260,8 +885,9 @@ func (ms *MessagesService) GetAllByPeriod(opts MessagesGetAllByPeriodOpts) ([]Re
 
 	result, err := tx.GetAllByPeriod(
 		repo.MessagesGetAllByPeriodOpts{
-			StartTime: opts.StartTime,
-			EndTime:   opts.EndTime,
+			StartTime:   opts.StartTime,
+			EndTime:     opts.EndTime,
+			ByEventTime: opts.ByEventTime,
 		},
 	)
 	if err != nil {
@@ -273,7 +899,7 @@ func (ms *MessagesService) GetAllByPeriod(opts MessagesGetAllByPeriodOpts) ([]Re
 	}
 
 	return lo.Map(result, func(r models.Message, indx int) ReportGetAllByPeriod {
-		if dev, ok := deviceByDeviceID[r.DeviceId]; ok {
+		if dev, ok := ms.deviceByID(r.DeviceId); ok {
 			return ReportGetAllByPeriod{
 				DeviceID:    r.DeviceId,
 				Name:        dev.Name,
@@ -283,6 +909,11 @@ func (ms *MessagesService) GetAllByPeriod(opts MessagesGetAllByPeriodOpts) ([]Re
 				Message:     r.Message,
 				MessageType: r.MessageType,
 				GotAt:       r.GotAt,
+				EventTime:   r.EventTime,
+				ReceivedAt:  r.ReceivedAt,
+				Attributes:  r.Attributes,
+				Silenced:    r.SilenceID != nil,
+				SilenceID:   lo.FromPtr(r.SilenceID),
 			}
 		}
 		return ReportGetAllByPeriod{
@@ -294,11 +925,20 @@ func (ms *MessagesService) GetAllByPeriod(opts MessagesGetAllByPeriodOpts) ([]Re
 			Message:     r.Message,
 			MessageType: r.MessageType,
 			GotAt:       r.GotAt,
+			EventTime:   r.EventTime,
+			ReceivedAt:  r.ReceivedAt,
+			Attributes:  r.Attributes,
+			Silenced:    r.SilenceID != nil,
+			SilenceID:   lo.FromPtr(r.SilenceID),
 		}
 	}), nil
 }
 
 type (
+	MessagesGetAllByDeviceIdOpts struct {
+		DeviceID    int32
+		ByEventTime bool
+	}
 	ReportGetAllByDeviceId struct {
 		DeviceID    int32
 		Name        string
@@ -308,10 +948,16 @@ type (
 		GotAt       time.Time
 		Message     string
 		MessageType string
+		EventTime   *time.Time
+		ReceivedAt  *time.Time
+		Attributes  map[string]string
+		// Silenced messages did not notify, SilenceID is the silence.
+		Silenced  bool
+		SilenceID int32
 	}
 )
 
-func (ms *MessagesService) GetAllByDeviceId(deviceID int32) ([]ReportGetAllByDeviceId, error) {
+func (ms *MessagesService) GetAllByDeviceId(opts MessagesGetAllByDeviceIdOpts) ([]ReportGetAllByDeviceId, error) {
 	tx, err := ms.messageRepo.BeginTx(context.Background())
 	if err != nil {
 		ms.log.Error("tx.BeginTx", zap.Error(err))
@@ -319,7 +965,12 @@ func (ms *MessagesService) GetAllByDeviceId(deviceID int32) ([]ReportGetAllByDev
 	}
 	defer tx.Rollback()
 
-	result, err := tx.GetAllByDeviceId(deviceID)
+	result, err := tx.GetAllByDeviceId(
+		repo.MessagesGetAllByDeviceIdOpts{
+			DeviceID:    opts.DeviceID,
+			ByEventTime: opts.ByEventTime,
+		},
+	)
 	if err != nil {
 		return nil, fmt.Errorf("tx.GetAllByDeviceId: %w", err)
 	}
@@ -329,7 +980,7 @@ func (ms *MessagesService) GetAllByDeviceId(deviceID int32) ([]ReportGetAllByDev
 	}
 
 	return lo.Map(result, func(r models.Message, indx int) ReportGetAllByDeviceId {
-		if dev, ok := deviceByDeviceID[r.DeviceId]; ok {
+		if dev, ok := ms.deviceByID(r.DeviceId); ok {
 			return ReportGetAllByDeviceId{
 				DeviceID:    r.DeviceId,
 				Name:        dev.Name,
@@ -339,6 +990,11 @@ func (ms *MessagesService) GetAllByDeviceId(deviceID int32) ([]ReportGetAllByDev
 				Message:     r.Message,
 				MessageType: r.MessageType,
 				GotAt:       r.GotAt,
+				EventTime:   r.EventTime,
+				ReceivedAt:  r.ReceivedAt,
+				Attributes:  r.Attributes,
+				Silenced:    r.SilenceID != nil,
+				SilenceID:   lo.FromPtr(r.SilenceID),
 			}
 		}
 		return ReportGetAllByDeviceId{
@@ -350,6 +1006,11 @@ func (ms *MessagesService) GetAllByDeviceId(deviceID int32) ([]ReportGetAllByDev
 			Message:     r.Message,
 			MessageType: r.MessageType,
 			GotAt:       r.GotAt,
+			EventTime:   r.EventTime,
+			ReceivedAt:  r.ReceivedAt,
+			Attributes:  r.Attributes,
+			Silenced:    r.SilenceID != nil,
+			SilenceID:   lo.FromPtr(r.SilenceID),
 		}
 	}), nil
 }
@@ -381,7 +1042,7 @@ func (ms *MessagesService) GetCountByMessageType(messageType string) ([]ReportGe
 	}
 
 	return lo.Map(result.Count, func(r models.CountByDeviceID, indx int) ReportGetCountByMessageType {
-		if dev, ok := deviceByDeviceID[r.DeviceId]; ok {
+		if dev, ok := ms.deviceByID(r.DeviceId); ok {
 			return ReportGetCountByMessageType{
 				DeviceID:    r.DeviceId,
 				Name:        dev.Name,
@@ -0,0 +1,223 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// NotifySourceRule is the source type of notifications of rules, the
+	// ones of tags use models.AlertRuleTag.
+	NotifySourceRule = "rule"
+
+	// notificationSaveRetries bounds the reloads after another replica saved
+	// the same notification first.
+	notificationSaveRetries = 3
+
+	// suppressedLease is held by the replica that flushes the counts of the
+	// suppressed repeats.
+	suppressedLease = "suppressed"
+)
+
+var (
+	sendedMutex sync.Mutex
+	// sendedNotifications is used without a notification repo.
+	sendedNotifications = map[string]models.SendedNotification{}
+)
+
+// notificationKey identifies repeats of a notification. The subject is hashed,
+// it may hold characters a bucket key can not. The alert id is part of it, so
+// the notification of an alert raised again after it recovered is sent even
+// within the period of the previous one.
+func notificationKey(deviceID int32, notify CreateMessageResponse) string {
+	hash := fnv.New64a()
+	hash.Write([]byte(notify.Subject))
+
+	return fmt.Sprintf("%d.%s.%d.%d.%x", deviceID, notify.SourceType, notify.SourceID, notify.AlertID, hash.Sum64())
+}
+
+// suppressRepeats drops the notifications already sent for the same device,
+// source and subject within the notification period. A notification sent
+// after the period carries the count of the repeats dropped before it. If the
+// repo is unavailable the notification is sent.
+func (ms *MessagesService) suppressRepeats(deviceID int32, notifications []CreateMessageResponse) []CreateMessageResponse {
+	return ms.dropRepeats(notifications, func(notify CreateMessageResponse) (int32, bool) {
+		return ms.markSended(deviceID, notify)
+	})
+}
+
+// dropRepeats keeps the notifications mark reports as sent.
+func (ms *MessagesService) dropRepeats(notifications []CreateMessageResponse, mark func(notify CreateMessageResponse) (int32, bool)) []CreateMessageResponse {
+	if ms.notificationPeriod <= 0 {
+		return notifications
+	}
+
+	sent := notifications[:0]
+	for _, notify := range notifications {
+		suppressed, send := mark(notify)
+		if !send {
+			continue
+		}
+
+		notify.Suppressed = suppressed
+		if suppressed > 0 {
+			notify.Text = suppressedText(notify.Text, suppressed)
+		}
+		sent = append(sent, notify)
+	}
+
+	return sent
+}
+
+// markSended records the notification and reports whether it is sent and how
+// many repeats were suppressed before it.
+func (ms *MessagesService) markSended(deviceID int32, notify CreateMessageResponse) (int32, bool) {
+	key := notificationKey(deviceID, notify)
+
+	if ms.notificationRepo == nil {
+		sendedMutex.Lock()
+		defer sendedMutex.Unlock()
+
+		next, suppressed, send := ms.nextSended(sendedNotifications[key], deviceID, notify, time.Now())
+		sendedNotifications[key] = next
+		return suppressed, send
+	}
+
+	for attempt := 0; ; attempt++ {
+		previous, revision, err := ms.notificationRepo.Load(key)
+		if err != nil && !errors.Is(err, repo.ErrNotificationNotFound) {
+			ms.log.Warn("ms.notificationRepo.Load", zap.Error(err), zap.String("notification", key))
+			return 0, true
+		}
+
+		next, suppressed, send := ms.nextSended(previous, deviceID, notify, time.Now())
+
+		_, err = ms.notificationRepo.Save(key, next, revision)
+		if err == nil {
+			return suppressed, send
+		}
+		if errors.Is(err, repo.ErrNotificationConflict) && attempt < notificationSaveRetries {
+			continue
+		}
+
+		ms.log.Warn("ms.notificationRepo.Save", zap.Error(err), zap.String("notification", key))
+		return suppressed, true
+	}
+}
+
+// nextSended counts a repeat within the period of previous, otherwise the
+// notification is sent and starts a new period.
+func (ms *MessagesService) nextSended(previous models.SendedNotification, deviceID int32, notify CreateMessageResponse, now time.Time) (models.SendedNotification, int32, bool) {
+	if now.Before(previous.ExpiredAt) {
+		previous.Message = notify.Text
+		previous.Suppressed++
+		return previous, 0, false
+	}
+
+	return models.SendedNotification{
+		Message:   notify.Text,
+		DeviceId:  deviceID,
+		Subject:   notify.Subject,
+		ExpiredAt: now.Add(ms.notificationPeriod),
+	}, previous.Suppressed, true
+}
+
+func suppressedText(text string, suppressed int32) string {
+	return fmt.Sprintf("%s\n\n%d repeats suppressed since the previous notification", text, suppressed)
+}
+
+// FlushSuppressed sends the count of the repeats suppressed within a
+// notification period that ended without another notification, the last
+// repeat is sent with it. Without a notification repo it also drops the
+// entries whose period ended, with the repo only the replica holding the
+// suppressed lease flushes.
+func (ms *MessagesService) FlushSuppressed() []DeviceNotification {
+	if ms.notificationPeriod <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	if ms.notificationRepo == nil {
+		sendedMutex.Lock()
+		defer sendedMutex.Unlock()
+
+		var flushed []DeviceNotification
+		for key, sended := range sendedNotifications {
+			if now.Before(sended.ExpiredAt) {
+				continue
+			}
+			if sended.Suppressed > 0 {
+				flushed = append(flushed, suppressedNotification(sended))
+			}
+			delete(sendedNotifications, key)
+		}
+		return flushed
+	}
+
+	if ms.leaseRepo == nil {
+		return nil
+	}
+	owner, err := ms.leaseRepo.Acquire(suppressedLease, ms.instance)
+	if err != nil {
+		ms.log.Warn("ms.leaseRepo.Acquire", zap.Error(err))
+		return nil
+	}
+	if !owner {
+		return nil
+	}
+
+	keys, err := ms.notificationRepo.Keys()
+	if err != nil {
+		ms.log.Error("ms.notificationRepo.Keys", zap.Error(err))
+		return nil
+	}
+
+	var flushed []DeviceNotification
+	for _, key := range keys {
+		sended, revision, err := ms.notificationRepo.Load(key)
+		if errors.Is(err, repo.ErrNotificationNotFound) {
+			continue
+		}
+		if err != nil {
+			ms.log.Warn("ms.notificationRepo.Load", zap.Error(err), zap.String("notification", key))
+			continue
+		}
+		if sended.Suppressed == 0 || now.Before(sended.ExpiredAt) {
+			continue
+		}
+
+		// A conflict is a notification sent meanwhile, it carries the count.
+		next := sended
+		next.Suppressed = 0
+		if _, err = ms.notificationRepo.Save(key, next, revision); err != nil {
+			if !errors.Is(err, repo.ErrNotificationConflict) {
+				ms.log.Warn("ms.notificationRepo.Save", zap.Error(err), zap.String("notification", key))
+			}
+			continue
+		}
+
+		flushed = append(flushed, suppressedNotification(sended))
+	}
+
+	return flushed
+}
+
+func suppressedNotification(sended models.SendedNotification) DeviceNotification {
+	return DeviceNotification{
+		DeviceID: sended.DeviceId,
+		Notification: CreateMessageResponse{
+			Text:       suppressedText(sended.Message, sended.Suppressed),
+			Subject:    sended.Subject,
+			Suppressed: sended.Suppressed,
+		},
+	}
+}
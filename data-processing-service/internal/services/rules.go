@@ -0,0 +1,377 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+)
+
+var ErrInvalidRule = errors.New("invalid rule")
+
+const (
+	RuleOpAnd = "and"
+	RuleOpOr  = "or"
+
+	// maxRuleDepth bounds the nesting of condition groups.
+	maxRuleDepth = 8
+
+	attributesFieldPrefix = "attributes."
+)
+
+type Rules interface {
+	Create(ctx context.Context, params models.Rule) (models.Rule, error)
+	Read(ctx context.Context) (ReadRulesResult, error)
+	Update(ctx context.Context, params UpdateRuleParams) error
+	Delete(ctx context.Context, ruleID int32) error
+	ReadBaselines(ctx context.Context, opts repo.ReadBaselinesOpts) ([]models.AnomalyBaseline, error)
+}
+
+type RulesService struct {
+	repo            repo.Rules
+	baselineRepo    repo.Baselines
+	messagesService Messages
+}
+
+func NewRulesService(r repo.Rules, baselineRepo repo.Baselines, messagesService Messages) Rules {
+	return &RulesService{
+		repo:            r,
+		baselineRepo:    baselineRepo,
+		messagesService: messagesService,
+	}
+}
+
+// Create refuses a rule that would not compile, so every stored rule is
+// evaluated. A rule has either a window or an anomaly.
+func (s *RulesService) Create(ctx context.Context, params models.Rule) (models.Rule, error) {
+	if params.SeverityLevel == "" {
+		params.SeverityLevel = "info"
+	}
+
+	if _, err := compileRule(params.Op, params.Conditions); err != nil {
+		return models.Rule{}, err
+	}
+	if params.Window != nil && params.Anomaly != nil {
+		return models.Rule{}, fmt.Errorf("%w: window and anomaly given", ErrInvalidRule)
+	}
+	if params.Window != nil {
+		if _, err := compileWindow(*params.Window); err != nil {
+			return models.Rule{}, err
+		}
+	}
+	if params.Anomaly != nil {
+		anomaly := withAnomalyDefaults(*params.Anomaly)
+		if _, err := compileAnomaly(anomaly); err != nil {
+			return models.Rule{}, err
+		}
+		params.Anomaly = &anomaly
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return models.Rule{}, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.Create(params)
+	if err != nil {
+		return models.Rule{}, fmt.Errorf("tx.Create: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Rule{}, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	s.messagesService.RulesChanged()
+
+	return ret, nil
+}
+
+type (
+	ReadRulesResult struct {
+		Rules []models.Rule
+	}
+)
+
+func (s *RulesService) Read(ctx context.Context) (ReadRulesResult, error) {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return ReadRulesResult{}, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.Read(ctx)
+	if err != nil {
+		return ReadRulesResult{}, fmt.Errorf("tx.Read: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return ReadRulesResult{}, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return ReadRulesResult{
+		Rules: ret.Rules,
+	}, nil
+}
+
+type (
+	UpdateRuleParams struct {
+		ID            int32
+		Name          *string
+		DeviceId      *int32
+		Op            *string
+		Conditions    []models.RuleCondition
+		Window        *models.RuleWindow
+		RemoveWindow  bool
+		Anomaly       *models.RuleAnomaly
+		RemoveAnomaly bool
+		Subject       *string
+		SeverityLevel *string
+	}
+)
+
+// Update replaces the conditions, the window and the anomaly as a whole if
+// they are given. Giving a window removes the anomaly and the other way
+// round.
+func (s *RulesService) Update(ctx context.Context, params UpdateRuleParams) error {
+	if params.Op != nil && *params.Op != RuleOpAnd && *params.Op != RuleOpOr {
+		return fmt.Errorf("%w: unknown op %q", ErrInvalidRule, *params.Op)
+	}
+
+	var conditions *models.SqlJsonbRuleConditions
+	if len(params.Conditions) > 0 {
+		if _, err := compileRule(RuleOpAnd, params.Conditions); err != nil {
+			return err
+		}
+		conditions = (*models.SqlJsonbRuleConditions)(&params.Conditions)
+	}
+	if params.Window != nil {
+		if params.RemoveWindow {
+			return fmt.Errorf("%w: window given and removed", ErrInvalidRule)
+		}
+		if _, err := compileWindow(*params.Window); err != nil {
+			return err
+		}
+		params.RemoveAnomaly = params.Anomaly == nil
+	}
+	if params.Anomaly != nil {
+		if params.RemoveAnomaly {
+			return fmt.Errorf("%w: anomaly given and removed", ErrInvalidRule)
+		}
+		if params.Window != nil {
+			return fmt.Errorf("%w: window and anomaly given", ErrInvalidRule)
+		}
+		anomaly := withAnomalyDefaults(*params.Anomaly)
+		if _, err := compileAnomaly(anomaly); err != nil {
+			return err
+		}
+		params.Anomaly = &anomaly
+		params.RemoveWindow = true
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.Update(ctx, repo.UpdateRulesOpts{
+		ID:            params.ID,
+		Name:          params.Name,
+		DeviceId:      params.DeviceId,
+		Op:            params.Op,
+		Conditions:    conditions,
+		Window:        params.Window,
+		RemoveWindow:  params.RemoveWindow,
+		Anomaly:       params.Anomaly,
+		RemoveAnomaly: params.RemoveAnomaly,
+		Subject:       params.Subject,
+		SeverityLevel: params.SeverityLevel,
+	})
+	if err != nil {
+		return fmt.Errorf("tx.Update: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	s.messagesService.RulesChanged()
+
+	return nil
+}
+
+func (s *RulesService) Delete(ctx context.Context, ruleID int32) error {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.Delete(ctx, ruleID)
+	if err != nil {
+		return fmt.Errorf("tx.Delete: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	s.messagesService.RulesChanged()
+
+	return nil
+}
+
+// condition reports whether a message satisfies a compiled rule condition.
+type condition func(message models.Message) bool
+
+func compileRule(op string, conditions []models.RuleCondition) (condition, error) {
+	return compileGroup(op, conditions, 1)
+}
+
+func compileGroup(op string, conditions []models.RuleCondition, depth int) (condition, error) {
+	if depth > maxRuleDepth {
+		return nil, fmt.Errorf("%w: conditions nested deeper than %d", ErrInvalidRule, maxRuleDepth)
+	}
+	if len(conditions) == 0 {
+		return nil, fmt.Errorf("%w: %q without conditions", ErrInvalidRule, op)
+	}
+
+	compiled := make([]condition, 0, len(conditions))
+	for _, c := range conditions {
+		cond, err := compileCondition(c, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		compiled = append(compiled, cond)
+	}
+
+	switch op {
+	case RuleOpAnd:
+		return func(message models.Message) bool {
+			for _, cond := range compiled {
+				if !cond(message) {
+					return false
+				}
+			}
+			return true
+		}, nil
+
+	case RuleOpOr:
+		return func(message models.Message) bool {
+			for _, cond := range compiled {
+				if cond(message) {
+					return true
+				}
+			}
+			return false
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidRule, op)
+	}
+}
+
+func compileCondition(c models.RuleCondition, depth int) (condition, error) {
+	if c.Op != "" {
+		return compileGroup(c.Op, c.Conditions, depth)
+	}
+
+	field, err := ruleField(c.Field)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(c.Regexp)
+	if err != nil {
+		return nil, fmt.Errorf("%w: regexp.Compile: %w", ErrInvalidRule, err)
+	}
+	if c.ArrayIndex < 0 || int(c.ArrayIndex) > re.NumSubexp() {
+		return nil, fmt.Errorf("%w: array_index %d out of range for %q", ErrInvalidRule, c.ArrayIndex, c.Regexp)
+	}
+
+	compare, err := ruleCompare(c.CompareType, c.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	index := c.ArrayIndex
+	return func(message models.Message) bool {
+		found := re.FindStringSubmatch(field(message))
+		if found == nil {
+			return false
+		}
+		if compare == nil {
+			return true
+		}
+		return compare(found[index])
+	}, nil
+}
+
+// ruleField returns the getter of a message field, an empty field is the
+// message text like for tags.
+func ruleField(name string) (func(models.Message) string, error) {
+	switch {
+	case name == "" || name == "message":
+		return func(m models.Message) string { return m.Message }, nil
+	case name == "component":
+		return func(m models.Message) string { return m.Component }, nil
+	case name == "message_type":
+		return func(m models.Message) string { return m.MessageType }, nil
+	case strings.HasPrefix(name, attributesFieldPrefix) && len(name) > len(attributesFieldPrefix):
+		key := strings.TrimPrefix(name, attributesFieldPrefix)
+		return func(m models.Message) string { return m.Attributes[key] }, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidRule, name)
+	}
+}
+
+// ruleCompare returns nil for an empty compare type, the condition then only
+// needs the regexp to match. Values that are not numbers never pass a numeric
+// comparison.
+func ruleCompare(compareType string, value string) (func(string) bool, error) {
+	switch compareType {
+	case "":
+		return nil, nil
+	case "=":
+		return func(s string) bool { return s == value }, nil
+	case "!=":
+		return func(s string) bool { return s != value }, nil
+	}
+
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: value %q of %q is not a number", ErrInvalidRule, value, compareType)
+	}
+
+	var compare func(a, b float64) bool
+	switch compareType {
+	case "<":
+		compare = func(a, b float64) bool { return a < b }
+	case "<=":
+		compare = func(a, b float64) bool { return a <= b }
+	case ">":
+		compare = func(a, b float64) bool { return a > b }
+	case ">=":
+		compare = func(a, b float64) bool { return a >= b }
+	default:
+		return nil, fmt.Errorf("%w: unknown compare type %q", ErrInvalidRule, compareType)
+	}
+
+	return func(s string) bool {
+		number, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil {
+			return false
+		}
+		return compare(number, threshold)
+	}, nil
+}
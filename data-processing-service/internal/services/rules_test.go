@@ -0,0 +1,120 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"data-processing-service/internal/models"
+)
+
+func TestCompileRule(t *testing.T) {
+	message := models.Message{
+		Message:    "cpu 93 on core 2",
+		Component:  "kernel",
+		Attributes: models.SqlJsonbStringMap{"site": "north"},
+	}
+
+	nested := []models.RuleCondition{{Regexp: "cpu"}}
+	for range maxRuleDepth {
+		nested = []models.RuleCondition{{Op: RuleOpAnd, Conditions: nested}}
+	}
+
+	tests := []struct {
+		name       string
+		op         string
+		conditions []models.RuleCondition
+		err        error
+		want       bool
+	}{
+		{
+			name:       "regexp only",
+			op:         RuleOpAnd,
+			conditions: []models.RuleCondition{{Regexp: "cpu"}},
+			want:       true,
+		},
+		{
+			name:       "numeric compare on a submatch",
+			op:         RuleOpAnd,
+			conditions: []models.RuleCondition{{Regexp: `cpu (\d+)`, ArrayIndex: 1, CompareType: ">", Value: "90"}},
+			want:       true,
+		},
+		{
+			name:       "numeric compare fails",
+			op:         RuleOpAnd,
+			conditions: []models.RuleCondition{{Regexp: `cpu (\d+)`, ArrayIndex: 1, CompareType: "<", Value: "90"}},
+		},
+		{
+			name:       "field and attribute",
+			op:         RuleOpAnd,
+			conditions: []models.RuleCondition{{Field: "component", Regexp: "^kernel$"}, {Field: "attributes.site", Regexp: ".+", CompareType: "=", Value: "north"}},
+			want:       true,
+		},
+		{
+			name:       "and needs every condition",
+			op:         RuleOpAnd,
+			conditions: []models.RuleCondition{{Regexp: "cpu"}, {Regexp: "disk"}},
+		},
+		{
+			name:       "or needs one condition",
+			op:         RuleOpOr,
+			conditions: []models.RuleCondition{{Regexp: "disk"}, {Op: RuleOpAnd, Conditions: []models.RuleCondition{{Regexp: "core"}}}},
+			want:       true,
+		},
+		{
+			name:       "unknown op",
+			op:         "xor",
+			conditions: []models.RuleCondition{{Regexp: "cpu"}},
+			err:        ErrInvalidRule,
+		},
+		{
+			name: "no conditions",
+			op:   RuleOpAnd,
+			err:  ErrInvalidRule,
+		},
+		{
+			name:       "too deep",
+			op:         RuleOpAnd,
+			conditions: nested,
+			err:        ErrInvalidRule,
+		},
+		{
+			name:       "unknown field",
+			op:         RuleOpAnd,
+			conditions: []models.RuleCondition{{Field: "hostname", Regexp: "cpu"}},
+			err:        ErrInvalidRule,
+		},
+		{
+			name:       "bad regexp",
+			op:         RuleOpAnd,
+			conditions: []models.RuleCondition{{Regexp: "cpu ("}},
+			err:        ErrInvalidRule,
+		},
+		{
+			name:       "array index out of range",
+			op:         RuleOpAnd,
+			conditions: []models.RuleCondition{{Regexp: "cpu", ArrayIndex: 1}},
+			err:        ErrInvalidRule,
+		},
+		{
+			name:       "numeric compare with a text value",
+			op:         RuleOpAnd,
+			conditions: []models.RuleCondition{{Regexp: "cpu", CompareType: ">", Value: "high"}},
+			err:        ErrInvalidRule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, err := compileRule(tt.op, tt.conditions)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("compileRule() error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if got := cond(message); got != tt.want {
+				t.Errorf("condition() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
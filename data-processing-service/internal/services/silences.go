@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidSilence = errors.New("invalid silence")
+
+// maxSilenceRecurrenceDuration bounds one recurring window, a longer one
+// would overlap the next of a weekly schedule.
+const maxSilenceRecurrenceDuration = 7 * 24 * time.Hour
+
+type Silences interface {
+	Create(ctx context.Context, params models.Silence) (models.Silence, error)
+	Read(ctx context.Context, params ReadSilencesParams) ([]models.Silence, error)
+	Delete(ctx context.Context, silenceID int32) error
+}
+
+type SilencesService struct {
+	repo            repo.Silences
+	messagesService Messages
+}
+
+func NewSilencesService(r repo.Silences, messagesService Messages) Silences {
+	return &SilencesService{
+		repo:            r,
+		messagesService: messagesService,
+	}
+}
+
+// Create refuses a silence that matches every message or whose recurrence
+// does not parse.
+func (s *SilencesService) Create(ctx context.Context, params models.Silence) (models.Silence, error) {
+	if _, err := compileSilence(params); err != nil {
+		return models.Silence{}, err
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return models.Silence{}, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.Create(params)
+	if err != nil {
+		return models.Silence{}, fmt.Errorf("tx.Create: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Silence{}, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	s.messagesService.SilencesChanged()
+
+	return ret, nil
+}
+
+type (
+	ReadSilencesParams struct {
+		// WithExpired also returns the silences that already ended.
+		WithExpired bool
+	}
+)
+
+func (s *SilencesService) Read(ctx context.Context, params ReadSilencesParams) ([]models.Silence, error) {
+	since := time.Now()
+	if params.WithExpired {
+		since = time.Time{}
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.Read(ctx, since)
+	if err != nil {
+		return nil, fmt.Errorf("tx.Read: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return ret, nil
+}
+
+// Delete ends a silence at once, the messages it muted stay marked.
+func (s *SilencesService) Delete(ctx context.Context, silenceID int32) error {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.Delete(ctx, silenceID)
+	if err != nil {
+		return fmt.Errorf("tx.Delete: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	s.messagesService.SilencesChanged()
+
+	return nil
+}
+
+type compiledSilence struct {
+	silence  models.Silence
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+func compileSilence(silence models.Silence) (compiledSilence, error) {
+	if silence.DeviceId == 0 && silence.DeviceType == "" && silence.RuleName == "" && silence.Component == "" {
+		return compiledSilence{}, fmt.Errorf("%w: no device_id, device_type, rule_name or component", ErrInvalidSilence)
+	}
+	if !silence.StartsAt.Before(silence.EndsAt) {
+		return compiledSilence{}, fmt.Errorf("%w: starts_at is not before ends_at", ErrInvalidSilence)
+	}
+
+	compiled := compiledSilence{silence: silence}
+	if silence.Recurrence == "" {
+		if silence.Duration != 0 {
+			return compiledSilence{}, fmt.Errorf("%w: duration without recurrence", ErrInvalidSilence)
+		}
+		return compiled, nil
+	}
+
+	schedule, err := cron.ParseStandard(silence.Recurrence)
+	if err != nil {
+		return compiledSilence{}, fmt.Errorf("%w: cron.ParseStandard: %w", ErrInvalidSilence, err)
+	}
+	compiled.schedule = schedule
+	compiled.duration = time.Duration(silence.Duration) * time.Second
+	if compiled.duration <= 0 || compiled.duration > maxSilenceRecurrenceDuration {
+		return compiledSilence{}, fmt.Errorf("%w: recurrence duration %s out of range 1s-%s",
+			ErrInvalidSilence, compiled.duration, maxSilenceRecurrenceDuration)
+	}
+
+	return compiled, nil
+}
+
+// active tells whether t is within the silence and, for a recurring one,
+// within Duration after the schedule fired.
+func (s compiledSilence) active(t time.Time) bool {
+	if t.Before(s.silence.StartsAt) || !t.Before(s.silence.EndsAt) {
+		return false
+	}
+	if s.schedule == nil {
+		return true
+	}
+
+	return !s.schedule.Next(t.Add(-s.duration)).After(t)
+}
+
+func (s compiledSilence) matches(message models.Message, device models.Device, ruleName string) bool {
+	return (s.silence.DeviceId == 0 || s.silence.DeviceId == message.DeviceId) &&
+		(s.silence.DeviceType == "" || s.silence.DeviceType == device.DeviceType) &&
+		(s.silence.RuleName == "" || s.silence.RuleName == ruleName) &&
+		(s.silence.Component == "" || s.silence.Component == message.Component)
+}
+
+var (
+	silencesMutex sync.Mutex
+	// silences holds the ones not ended when they were loaded.
+	silences          []compiledSilence
+	silencesPublisher SilencesPublisher
+)
+
+// SilencesPublisher tells every replica to reload the silences.
+type SilencesPublisher func()
+
+func (ms *MessagesService) SetSilencesPublisher(publisher SilencesPublisher) {
+	silencesMutex.Lock()
+	defer silencesMutex.Unlock()
+
+	silencesPublisher = publisher
+}
+
+// UpdateSilences keeps the loaded silences if they can not be read.
+func (ms *MessagesService) UpdateSilences() {
+	if ms.silenceRepo == nil {
+		return
+	}
+
+	tx, err := ms.silenceRepo.BeginTx(context.Background())
+	if err != nil {
+		ms.log.Error("ms.silenceRepo.BeginTx", zap.Error(err))
+		return
+	}
+	defer tx.Rollback()
+
+	loaded, err := tx.Read(context.Background(), time.Now())
+	if err != nil {
+		ms.log.Error("tx.Read", zap.Error(err))
+		return
+	}
+
+	if err = tx.Commit(); err != nil {
+		ms.log.Error("tx.Commit", zap.Error(err))
+		return
+	}
+
+	compiled := make([]compiledSilence, 0, len(loaded))
+	for _, silence := range loaded {
+		c, err := compileSilence(silence)
+		if err != nil {
+			ms.log.Warn("compileSilence", zap.Error(err), zap.Int32("silence id", silence.ID))
+			continue
+		}
+		compiled = append(compiled, c)
+	}
+
+	silencesMutex.Lock()
+	defer silencesMutex.Unlock()
+
+	silences = compiled
+
+	ms.log.Debug("silences loaded", zap.Int("count", len(compiled)))
+}
+
+// SilencesChanged reloads the silences after a change made by this replica
+// and has the other replicas reload them.
+func (ms *MessagesService) SilencesChanged() {
+	ms.UpdateSilences()
+
+	silencesMutex.Lock()
+	publisher := silencesPublisher
+	silencesMutex.Unlock()
+
+	if publisher != nil {
+		publisher()
+	}
+}
+
+// silenceOf returns the id of the first silence active for the message and
+// the tag or rule named ruleName, an empty ruleName only matches silences
+// without one.
+func (ms *MessagesService) silenceOf(message models.Message, ruleName string) (int32, bool) {
+	silencesMutex.Lock()
+	active := silences
+	silencesMutex.Unlock()
+
+	if len(active) == 0 {
+		return 0, false
+	}
+
+	t := time.Now()
+	if message.ReceivedAt != nil {
+		t = *message.ReceivedAt
+	}
+	device, _ := ms.deviceByID(message.DeviceId)
+
+	for _, s := range active {
+		if s.active(t) && s.matches(message, device, ruleName) {
+			return s.silence.ID, true
+		}
+	}
+
+	return 0, false
+}
+
+// silence drops the notifications a silence matches and marks the message
+// with the first such silence. A silence without a rule name mutes all of
+// them.
+func (ms *MessagesService) silence(message *models.Message, notifications []CreateMessageResponse) []CreateMessageResponse {
+	if id, ok := ms.silenceOf(*message, ""); ok {
+		message.SilenceID = &id
+		return nil
+	}
+
+	kept := notifications[:0]
+	for _, notify := range notifications {
+		id, ok := ms.silenceOf(*message, notify.SourceName)
+		if !ok {
+			kept = append(kept, notify)
+			continue
+		}
+		if message.SilenceID == nil {
+			message.SilenceID = &id
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"data-processing-service/internal/models"
+
+	"go.uber.org/zap"
+)
+
+func TestCompileSilence(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		silence models.Silence
+		err     error
+	}{
+		{
+			name:    "device",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end},
+		},
+		{
+			name:    "recurring",
+			silence: models.Silence{RuleName: "disk", StartsAt: start, EndsAt: end, Recurrence: "0 2 * * *", Duration: 3600},
+		},
+		{
+			name:    "matches every message",
+			silence: models.Silence{StartsAt: start, EndsAt: end},
+			err:     ErrInvalidSilence,
+		},
+		{
+			name:    "ends before it starts",
+			silence: models.Silence{DeviceId: 1, StartsAt: end, EndsAt: start},
+			err:     ErrInvalidSilence,
+		},
+		{
+			name:    "duration without recurrence",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end, Duration: 60},
+			err:     ErrInvalidSilence,
+		},
+		{
+			name:    "recurrence does not parse",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end, Recurrence: "every night", Duration: 60},
+			err:     ErrInvalidSilence,
+		},
+		{
+			name:    "recurrence without duration",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end, Recurrence: "0 2 * * *"},
+			err:     ErrInvalidSilence,
+		},
+		{
+			name:    "recurrence longer than a week",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end, Recurrence: "0 2 * * 1", Duration: 8 * 24 * 3600},
+			err:     ErrInvalidSilence,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := compileSilence(tt.silence); !errors.Is(err, tt.err) {
+				t.Errorf("compileSilence() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSilenceActive(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(30 * 24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		silence models.Silence
+		at      time.Time
+		want    bool
+	}{
+		{
+			name:    "before it starts",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end},
+			at:      start.Add(-time.Second),
+		},
+		{
+			name:    "at its start",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end},
+			at:      start,
+			want:    true,
+		},
+		{
+			name:    "at its end",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end},
+			at:      end,
+		},
+		{
+			name:    "within a recurring window",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end, Recurrence: "0 2 * * *", Duration: 3600},
+			at:      start.Add(5*24*time.Hour + 2*time.Hour + 30*time.Minute),
+			want:    true,
+		},
+		{
+			name:    "between recurring windows",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end, Recurrence: "0 2 * * *", Duration: 3600},
+			at:      start.Add(5*24*time.Hour + 4*time.Hour),
+		},
+		{
+			name:    "recurring window after the end",
+			silence: models.Silence{DeviceId: 1, StartsAt: start, EndsAt: end, Recurrence: "0 2 * * *", Duration: 3600},
+			at:      end.Add(2*time.Hour + 30*time.Minute),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := compileSilence(tt.silence)
+			if err != nil {
+				t.Fatalf("compileSilence() error = %v", err)
+			}
+			if got := s.active(tt.at); got != tt.want {
+				t.Errorf("active() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSilence(t *testing.T) {
+	now := time.Now()
+	start := now.Add(-time.Hour)
+	end := now.Add(time.Hour)
+
+	ms := &MessagesService{log: zap.NewNop()}
+	ms.UpsertDevice(models.Device{ID: 9001, DeviceType: "camera"})
+	defer ms.DeleteDevice(9001)
+
+	notifications := []CreateMessageResponse{
+		{Subject: "disk full", SourceName: "disk"},
+		{Subject: "cpu high", SourceName: "cpu"},
+	}
+
+	tests := []struct {
+		name     string
+		silences []models.Silence
+		message  models.Message
+		want     []string
+		silence  int32
+	}{
+		{
+			name:    "no silences",
+			message: models.Message{DeviceId: 9001},
+			want:    []string{"disk full", "cpu high"},
+		},
+		{
+			name:     "device silenced",
+			silences: []models.Silence{{ID: 1, DeviceId: 9001, StartsAt: start, EndsAt: end}},
+			message:  models.Message{DeviceId: 9001},
+			silence:  1,
+		},
+		{
+			name:     "other device",
+			silences: []models.Silence{{ID: 1, DeviceId: 9002, StartsAt: start, EndsAt: end}},
+			message:  models.Message{DeviceId: 9001},
+			want:     []string{"disk full", "cpu high"},
+		},
+		{
+			name:     "device type silenced",
+			silences: []models.Silence{{ID: 2, DeviceType: "camera", StartsAt: start, EndsAt: end}},
+			message:  models.Message{DeviceId: 9001},
+			silence:  2,
+		},
+		{
+			name:     "one rule silenced",
+			silences: []models.Silence{{ID: 3, DeviceId: 9001, RuleName: "cpu", StartsAt: start, EndsAt: end}},
+			message:  models.Message{DeviceId: 9001},
+			want:     []string{"disk full"},
+			silence:  3,
+		},
+		{
+			name:     "component does not match",
+			silences: []models.Silence{{ID: 4, DeviceId: 9001, Component: "kernel", StartsAt: start, EndsAt: end}},
+			message:  models.Message{DeviceId: 9001, Component: "nginx"},
+			want:     []string{"disk full", "cpu high"},
+		},
+		{
+			name:     "ended",
+			silences: []models.Silence{{ID: 5, DeviceId: 9001, StartsAt: start, EndsAt: now.Add(-time.Minute)}},
+			message:  models.Message{DeviceId: 9001},
+			want:     []string{"disk full", "cpu high"},
+		},
+	}
+
+	defer func() { silences = nil }()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			silences = nil
+			for _, silence := range tt.silences {
+				compiled, err := compileSilence(silence)
+				if err != nil {
+					t.Fatalf("compileSilence() error = %v", err)
+				}
+				silences = append(silences, compiled)
+			}
+
+			message := tt.message
+			message.ReceivedAt = &now
+			kept := ms.silence(&message, append([]CreateMessageResponse(nil), notifications...))
+
+			var got []string
+			for _, notify := range kept {
+				got = append(got, notify.Subject)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("silence() kept %q, want %q", got, tt.want)
+			}
+
+			var silence int32
+			if message.SilenceID != nil {
+				silence = *message.SilenceID
+			}
+			if silence != tt.silence {
+				t.Errorf("silence() marked the message with %d, want %d", silence, tt.silence)
+			}
+		})
+	}
+}
@@ -2,17 +2,21 @@ package services
 
 import (
 	"context" //nolint:gosec
+	"errors"
 	"fmt"
 
 	"data-processing-service/internal/models"
 	"data-processing-service/internal/repo"
 )
 
+var ErrInvalidTag = errors.New("invalid tag")
+
 type Tags interface {
 	Create(ctx context.Context, params models.Tag) (models.Tag, error)
 	Read(ctx context.Context) (ReadResult, error)
 	Update(ctx context.Context, params UpdateParams) error
 	Delete(ctx context.Context, deviceID int32) error
+	Test(ctx context.Context, params TestTagOpts) ([]TestTagResult, error)
 }
 
 type TagsService struct {
@@ -44,14 +48,15 @@ func (s *TagsService) Create(ctx context.Context, params models.Tag) (models.Tag
 		return models.Tag{}, fmt.Errorf("tx.Commit: %w", err)
 	}
 
-	s.messagesService.UpdateTags()
+	s.messagesService.TagsChanged()
 
 	return ret, nil
 }
 
 type (
 	ReadResult struct {
-		Tags []models.Tag
+		Tags    []models.Tag
+		Version int64
 	}
 )
 
@@ -73,7 +78,8 @@ func (s *TagsService) Read(ctx context.Context) (ReadResult, error) {
 	}
 
 	return ReadResult{
-		Tags: ret.Tags,
+		Tags:    ret.Tags,
+		Version: ret.Version,
 	}, nil
 }
 
@@ -118,7 +124,7 @@ func (s *TagsService) Update(ctx context.Context, params UpdateParams) error {
 		return fmt.Errorf("tx.Commit: %w", err)
 	}
 
-	s.messagesService.UpdateTags()
+	s.messagesService.TagsChanged()
 
 	return nil
 }
@@ -140,7 +146,21 @@ func (s *TagsService) Delete(ctx context.Context, tagID int32) error {
 		return fmt.Errorf("tx.Commit: %w", err)
 	}
 
-	s.messagesService.UpdateTags()
+	s.messagesService.TagsChanged()
 
 	return nil
 }
+
+// Test is a dry run of a draft tag, see Messages.TestTag.
+func (s *TagsService) Test(_ context.Context, params TestTagOpts) ([]TestTagResult, error) {
+	if len(params.Messages) == 0 && !params.EndTime.After(params.StartTime) {
+		return nil, fmt.Errorf("%w: neither messages nor a time range to test against", ErrInvalidTag)
+	}
+
+	results, err := s.messagesService.TestTag(params)
+	if err != nil {
+		return nil, fmt.Errorf("s.messagesService.TestTag: %w", err)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,418 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+
+	"go.uber.org/zap"
+)
+
+const (
+	WindowSliding  = "sliding"
+	WindowTumbling = "tumbling"
+
+	WindowCount = "count"
+	WindowSum   = "sum"
+	WindowAvg   = "avg"
+	WindowMin   = "min"
+	WindowMax   = "max"
+	WindowRate  = "rate"
+
+	maxWindowDuration = 24 * time.Hour
+	// A sliding window is kept in this many slots, so it moves in steps of
+	// a slot and its state does not grow with the message rate.
+	windowSlots = 60
+	// windowSaveRetries bounds the reloads after another replica saved the
+	// same window first.
+	windowSaveRetries = 3
+)
+
+// compiledWindow aggregates the values of the messages that pass the rule
+// conditions.
+type compiledWindow struct {
+	window    models.RuleWindow
+	duration  time.Duration
+	slotWidth time.Duration
+	value     func(models.Message) (float64, bool)
+	compare   func(float64) bool
+}
+
+func compileWindow(w models.RuleWindow) (*compiledWindow, error) {
+	duration := w.Duration()
+	if duration < time.Second || duration > maxWindowDuration {
+		return nil, fmt.Errorf("%w: window duration %s out of range 1s-%s", ErrInvalidRule, duration, maxWindowDuration)
+	}
+
+	slotWidth := duration
+	switch w.Type {
+	case WindowSliding:
+		slotWidth = max(duration/windowSlots, time.Second).Truncate(time.Second)
+	case WindowTumbling:
+	default:
+		return nil, fmt.Errorf("%w: unknown window type %q", ErrInvalidRule, w.Type)
+	}
+
+	switch w.Aggregation {
+	case WindowCount, WindowRate:
+	case WindowSum, WindowAvg, WindowMin, WindowMax:
+		if w.Regexp == "" {
+			return nil, fmt.Errorf("%w: %s window without regexp", ErrInvalidRule, w.Aggregation)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown aggregation %q", ErrInvalidRule, w.Aggregation)
+	}
+
+	value, err := windowValue(w)
+	if err != nil {
+		return nil, err
+	}
+
+	compare, err := windowCompare(w.CompareType, w.Threshold)
+	if err != nil {
+		return nil, err
+	}
+
+	return &compiledWindow{
+		window:    w,
+		duration:  duration,
+		slotWidth: slotWidth,
+		value:     value,
+		compare:   compare,
+	}, nil
+}
+
+// windowValue returns the getter of the aggregated value, ok is false for a
+// message the regexp does not match or whose submatch is not a number.
+func windowValue(w models.RuleWindow) (func(models.Message) (float64, bool), error) {
+	if w.Regexp == "" {
+		return func(models.Message) (float64, bool) { return 1, true }, nil
+	}
+
+	return numericValue(w.Field, w.Regexp, w.ArrayIndex)
+}
+
+// numericValue returns the getter of the number in the submatch at index of
+// expr in the field, ok is false if there is none.
+func numericValue(fieldName string, expr string, index int32) (func(models.Message) (float64, bool), error) {
+	field, err := ruleField(fieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: regexp.Compile: %w", ErrInvalidRule, err)
+	}
+	if index < 0 || int(index) > re.NumSubexp() {
+		return nil, fmt.Errorf("%w: array_index %d out of range for %q", ErrInvalidRule, index, expr)
+	}
+
+	return func(message models.Message) (float64, bool) {
+		found := re.FindStringSubmatch(field(message))
+		if found == nil {
+			return 0, false
+		}
+		number, err := strconv.ParseFloat(strings.TrimSpace(found[index]), 64)
+		if err != nil {
+			return 0, false
+		}
+		return number, true
+	}, nil
+}
+
+func windowCompare(compareType string, threshold float64) (func(float64) bool, error) {
+	switch compareType {
+	case "<":
+		return func(v float64) bool { return v < threshold }, nil
+	case "<=":
+		return func(v float64) bool { return v <= threshold }, nil
+	case ">":
+		return func(v float64) bool { return v > threshold }, nil
+	case ">=":
+		return func(v float64) bool { return v >= threshold }, nil
+	case "=":
+		return func(v float64) bool { return v == threshold }, nil
+	case "!=":
+		return func(v float64) bool { return v != threshold }, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown window compare type %q", ErrInvalidRule, compareType)
+	}
+}
+
+// windowSlot aggregates the values of the messages from Start (unix seconds)
+// to the start of the next slot.
+type windowSlot struct {
+	Start int64   `json:"start"`
+	Count int64   `json:"count"`
+	Sum   float64 `json:"sum"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+}
+
+// windowState is what gets checkpointed. Window is the definition the slots
+// were collected for, a changed rule starts over.
+type windowState struct {
+	Window models.RuleWindow `json:"window"`
+	Slots  []windowSlot      `json:"slots"`
+	// Fired is set once the rule notified. A sliding window fires again
+	// after the aggregate went back under the threshold, a tumbling one in
+	// the next window.
+	Fired bool `json:"fired"`
+}
+
+// observe adds a value at t to the state and reports whether the rule fires.
+// Values older than the window are dropped but still evaluate it.
+func (w *compiledWindow) observe(state *windowState, t time.Time, value float64) (bool, float64) {
+	if state.Window != w.window {
+		*state = windowState{Window: w.window}
+	}
+
+	start := t.Truncate(w.slotWidth).Unix()
+	if w.window.Type == WindowTumbling && len(state.Slots) > 0 && state.Slots[0].Start < start {
+		state.Slots = nil
+		state.Fired = false
+	}
+
+	// Slots that ended before the window began are dropped.
+	from := t.Add(-w.duration).Unix()
+	width := int64(w.slotWidth / time.Second)
+	slots := state.Slots[:0]
+	for _, slot := range state.Slots {
+		if slot.Start+width > from {
+			slots = append(slots, slot)
+		}
+	}
+	state.Slots = slots
+
+	if start+width > from && (w.window.Type == WindowSliding || len(state.Slots) == 0 || state.Slots[0].Start == start) {
+		state.Slots = addToSlot(state.Slots, start, value)
+	}
+
+	aggregate, ok := w.aggregate(state.Slots)
+	over := ok && w.compare(aggregate)
+	fired := over && !state.Fired
+
+	if w.window.Type == WindowSliding {
+		state.Fired = over
+	} else {
+		state.Fired = state.Fired || over
+	}
+
+	return fired, aggregate
+}
+
+func addToSlot(slots []windowSlot, start int64, value float64) []windowSlot {
+	i := sort.Search(len(slots), func(i int) bool { return slots[i].Start >= start })
+	if i == len(slots) || slots[i].Start != start {
+		slots = append(slots, windowSlot{})
+		copy(slots[i+1:], slots[i:])
+		slots[i] = windowSlot{Start: start, Min: value, Max: value}
+	}
+
+	slot := &slots[i]
+	slot.Count++
+	slot.Sum += value
+	slot.Min = min(slot.Min, value)
+	slot.Max = max(slot.Max, value)
+
+	return slots
+}
+
+// aggregate is not ok for an empty window except for count and rate.
+func (w *compiledWindow) aggregate(slots []windowSlot) (float64, bool) {
+	var (
+		count            int64
+		sum              float64
+		minimum, maximum float64
+	)
+	for i, slot := range slots {
+		if i == 0 || slot.Min < minimum {
+			minimum = slot.Min
+		}
+		if i == 0 || slot.Max > maximum {
+			maximum = slot.Max
+		}
+		count += slot.Count
+		sum += slot.Sum
+	}
+
+	switch w.window.Aggregation {
+	case WindowCount:
+		return float64(count), true
+	case WindowRate:
+		return sum / w.duration.Seconds(), true
+	}
+
+	if count == 0 {
+		return 0, false
+	}
+
+	switch w.window.Aggregation {
+	case WindowSum:
+		return sum, true
+	case WindowAvg:
+		return sum / float64(count), true
+	case WindowMin:
+		return minimum, true
+	default:
+		return maximum, true
+	}
+}
+
+// describe is the notification text of a fired window.
+func (w *compiledWindow) describe(aggregate float64) string {
+	return fmt.Sprintf("%s %s %s %s over %s",
+		w.window.Aggregation,
+		strconv.FormatFloat(aggregate, 'f', -1, 64),
+		w.window.CompareType,
+		strconv.FormatFloat(w.window.Threshold, 'f', -1, 64),
+		w.duration,
+	)
+}
+
+// windowEntry is the local copy of a checkpointed window, revision is the
+// one it was loaded or saved at.
+type windowEntry struct {
+	mu       sync.Mutex
+	state    windowState
+	revision uint64
+	loaded   bool
+}
+
+var (
+	windowsMutex sync.Mutex
+	// Windows by "<rule id>.<device id>", a rule for every device keeps one
+	// window per device.
+	windows = map[string]*windowEntry{}
+)
+
+func windowKey(ruleID int32, deviceID int32) string {
+	return fmt.Sprintf("%d.%d", ruleID, deviceID)
+}
+
+// dropWindows forgets the local windows of rules that are gone or have no
+// window anymore, their checkpoints expire on their own.
+func dropWindows(windowed map[int32]struct{}) {
+	windowsMutex.Lock()
+	defer windowsMutex.Unlock()
+
+	for key := range windows {
+		id, _, _ := strings.Cut(key, ".")
+		ruleID, err := strconv.ParseInt(id, 10, 32)
+		if err != nil {
+			continue
+		}
+		if _, ok := windowed[int32(ruleID)]; !ok {
+			delete(windows, key)
+		}
+	}
+}
+
+// observeWindow adds the message to the window of its device and reports
+// whether the rule fires. With a window repo the state is saved before the
+// rule fires, so of several replicas only one notifies. Without one, or if it
+// is unavailable, the window lives in memory.
+func (ms *MessagesService) observeWindow(r compiledRule, message models.Message) (bool, string) {
+	value, ok := r.window.value(message)
+	if !ok {
+		return false, ""
+	}
+
+	t := time.Now()
+	if message.ReceivedAt != nil {
+		t = *message.ReceivedAt
+	}
+
+	key := windowKey(r.rule.ID, message.DeviceId)
+
+	windowsMutex.Lock()
+	entry, ok := windows[key]
+	if !ok {
+		entry = &windowEntry{}
+		windows[key] = entry
+	}
+	windowsMutex.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+
+	for attempt := 0; ; attempt++ {
+		if !entry.loaded && ms.windowRepo != nil {
+			ms.loadWindow(key, entry)
+		}
+
+		next := windowState{
+			Window: entry.state.Window,
+			Slots:  append([]windowSlot(nil), entry.state.Slots...),
+			Fired:  entry.state.Fired,
+		}
+		fired, aggregate := r.window.observe(&next, t, value)
+		entry.state = next
+
+		if ms.windowRepo == nil {
+			return fired, r.window.describe(aggregate)
+		}
+
+		revision, err := ms.saveWindow(key, next, entry.revision)
+		if err == nil {
+			entry.revision = revision
+			return fired, r.window.describe(aggregate)
+		}
+
+		entry.loaded = false
+		if errors.Is(err, repo.ErrWindowConflict) && attempt < windowSaveRetries {
+			continue
+		}
+
+		ms.log.Warn("ms.saveWindow", zap.Error(err), zap.String("window", key))
+		return fired, r.window.describe(aggregate)
+	}
+}
+
+// loadWindow keeps the local state if the checkpoint can not be read.
+func (ms *MessagesService) loadWindow(key string, entry *windowEntry) {
+	data, revision, err := ms.windowRepo.Load(key)
+	if errors.Is(err, repo.ErrWindowNotFound) {
+		entry.state = windowState{}
+		entry.revision = 0
+		entry.loaded = true
+		return
+	}
+	if err != nil {
+		ms.log.Warn("ms.windowRepo.Load", zap.Error(err), zap.String("window", key))
+		return
+	}
+
+	var state windowState
+	if err = json.Unmarshal(data, &state); err != nil {
+		ms.log.Warn("json.Unmarshal", zap.Error(err), zap.String("window", key))
+	}
+
+	entry.state = state
+	entry.revision = revision
+	entry.loaded = true
+}
+
+func (ms *MessagesService) saveWindow(key string, state windowState, revision uint64) (uint64, error) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return 0, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	revision, err = ms.windowRepo.Save(key, data, revision)
+	if err != nil {
+		return 0, fmt.Errorf("ms.windowRepo.Save: %w", err)
+	}
+
+	return revision, nil
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"data-processing-service/internal/models"
+)
+
+func TestCompileWindow(t *testing.T) {
+	tests := []struct {
+		name   string
+		window models.RuleWindow
+		err    error
+	}{
+		{
+			name:   "sliding count",
+			window: models.RuleWindow{Type: WindowSliding, DurationSec: 60, Aggregation: WindowCount, CompareType: ">", Threshold: 3},
+		},
+		{
+			name: "tumbling avg",
+			window: models.RuleWindow{Type: WindowTumbling, DurationSec: 60, Aggregation: WindowAvg,
+				Regexp: `cpu (\d+)`, ArrayIndex: 1, CompareType: ">=", Threshold: 90},
+		},
+		{
+			name:   "too short",
+			window: models.RuleWindow{Type: WindowSliding, DurationSec: 0, Aggregation: WindowCount, CompareType: ">"},
+			err:    ErrInvalidRule,
+		},
+		{
+			name:   "too long",
+			window: models.RuleWindow{Type: WindowSliding, DurationSec: 25 * 3600, Aggregation: WindowCount, CompareType: ">"},
+			err:    ErrInvalidRule,
+		},
+		{
+			name:   "unknown type",
+			window: models.RuleWindow{Type: "hopping", DurationSec: 60, Aggregation: WindowCount, CompareType: ">"},
+			err:    ErrInvalidRule,
+		},
+		{
+			name:   "sum without regexp",
+			window: models.RuleWindow{Type: WindowSliding, DurationSec: 60, Aggregation: WindowSum, CompareType: ">"},
+			err:    ErrInvalidRule,
+		},
+		{
+			name: "array index out of range",
+			window: models.RuleWindow{Type: WindowSliding, DurationSec: 60, Aggregation: WindowMax,
+				Regexp: `cpu (\d+)`, ArrayIndex: 2, CompareType: ">"},
+			err: ErrInvalidRule,
+		},
+		{
+			name:   "unknown compare type",
+			window: models.RuleWindow{Type: WindowSliding, DurationSec: 60, Aggregation: WindowCount, CompareType: "~"},
+			err:    ErrInvalidRule,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := compileWindow(tt.window); !errors.Is(err, tt.err) {
+				t.Errorf("compileWindow() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestWindowObserve(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type observation struct {
+		after     time.Duration
+		value     float64
+		fired     bool
+		aggregate float64
+	}
+
+	tests := []struct {
+		name         string
+		window       models.RuleWindow
+		observations []observation
+	}{
+		{
+			name:   "sliding count fires once while over",
+			window: models.RuleWindow{Type: WindowSliding, DurationSec: 60, Aggregation: WindowCount, CompareType: ">=", Threshold: 2},
+			observations: []observation{
+				{after: 0, value: 1, aggregate: 1},
+				{after: time.Second, value: 1, fired: true, aggregate: 2},
+				{after: 2 * time.Second, value: 1, aggregate: 3},
+			},
+		},
+		{
+			name:   "sliding count fires again after falling under",
+			window: models.RuleWindow{Type: WindowSliding, DurationSec: 60, Aggregation: WindowCount, CompareType: ">=", Threshold: 2},
+			observations: []observation{
+				{after: 0, value: 1, aggregate: 1},
+				{after: time.Second, value: 1, fired: true, aggregate: 2},
+				{after: 2 * time.Minute, value: 1, aggregate: 1},
+				{after: 2*time.Minute + time.Second, value: 1, fired: true, aggregate: 2},
+			},
+		},
+		{
+			name:   "tumbling sum starts over in the next window",
+			window: models.RuleWindow{Type: WindowTumbling, DurationSec: 60, Aggregation: WindowSum, Regexp: `(\d+)`, CompareType: ">", Threshold: 10},
+			observations: []observation{
+				{after: 0, value: 6, aggregate: 6},
+				{after: 10 * time.Second, value: 6, fired: true, aggregate: 12},
+				{after: 20 * time.Second, value: 6, aggregate: 18},
+				{after: time.Minute, value: 6, aggregate: 6},
+				{after: time.Minute + 10*time.Second, value: 6, fired: true, aggregate: 12},
+			},
+		},
+		{
+			name:   "avg",
+			window: models.RuleWindow{Type: WindowSliding, DurationSec: 60, Aggregation: WindowAvg, Regexp: `(\d+)`, CompareType: ">", Threshold: 50},
+			observations: []observation{
+				{after: 0, value: 40, aggregate: 40},
+				{after: time.Second, value: 80, fired: true, aggregate: 60},
+			},
+		},
+		{
+			name:   "min leaves out the slots that ended",
+			window: models.RuleWindow{Type: WindowSliding, DurationSec: 60, Aggregation: WindowMin, Regexp: `(\d+)`, CompareType: "<", Threshold: 5},
+			observations: []observation{
+				{after: 0, value: 3, fired: true, aggregate: 3},
+				{after: 2 * time.Minute, value: 10, aggregate: 10},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := compileWindow(tt.window)
+			if err != nil {
+				t.Fatalf("compileWindow() error = %v", err)
+			}
+
+			var state windowState
+			for i, o := range tt.observations {
+				fired, aggregate := w.observe(&state, start.Add(o.after), o.value)
+				if fired != o.fired || aggregate != o.aggregate {
+					t.Errorf("observation %d: observe() = %v, %v, want %v, %v", i, fired, aggregate, o.fired, o.aggregate)
+				}
+			}
+		})
+	}
+}
@@ -46,7 +46,8 @@ func (h *reportsHandler) InitReportsRoutes(api fiber.Router) {
 
 type (
 	getAllByDeviceIdReq struct {
-		DeviceID int `form:"device_id" json:"device_id" validate:"required" xml:"device_id"`
+		DeviceID    int  `form:"device_id"     json:"device_id"     validate:"required" xml:"device_id"`
+		ByEventTime bool `form:"by_event_time" json:"by_event_time"                      xml:"by_event_time"`
 	}
 
 	getAllByDeviceIdResp struct {
@@ -67,7 +68,10 @@ func (h *reportsHandler) getAllByDeviceId(ctx fiber.Ctx) error {
 	}
 
 	res, err := h.messageService.GetAllByDeviceId(
-		int32(body.DeviceID),
+		services.MessagesGetAllByDeviceIdOpts{
+			DeviceID:    int32(body.DeviceID),
+			ByEventTime: body.ByEventTime,
+		},
 	)
 	if err != nil {
 		return fiber.NewError(
@@ -100,6 +104,8 @@ type (
 	getAllByPeriodReq struct {
 		StartTime time.Time `form:"start_time" json:"start_time" validate:"required" xml:"start_time"`
 		EndTime   time.Time `form:"end_time"   json:"end_time"   validate:"required" xml:"end_time"`
+		// Filter and order by the time reported by the device.
+		ByEventTime bool `form:"by_event_time" json:"by_event_time" xml:"by_event_time"`
 	}
 
 	getAllByPeriodResp struct {
@@ -122,8 +128,9 @@ func (h *reportsHandler) getAllByPeriod(ctx fiber.Ctx) error {
 
 	res, err := h.messageService.GetAllByPeriod(
 		services.MessagesGetAllByPeriodOpts{
-			StartTime: body.EndTime,
-			EndTime:   body.StartTime,
+			StartTime:   body.EndTime,
+			EndTime:     body.StartTime,
+			ByEventTime: body.ByEventTime,
 		},
 	)
 	if err != nil {
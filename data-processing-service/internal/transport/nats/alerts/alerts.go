@@ -0,0 +1,235 @@
+package alertslistener
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/services"
+	pbalerts "data-processing-service/proto/api-gateway/alerts"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	readAlertsSubject        = "alerts.read"
+	acknowledgeAlertsSubject = "alerts.acknowledge"
+	resolveAlertsSubject     = "alerts.resolve"
+
+	alertsQueue = "alerts"
+)
+
+type NatsListeners struct {
+	natsConn      *nats.Conn
+	alertsService services.Alerts
+	log           *zap.Logger
+}
+
+type Config struct {
+	NatsConn      *nats.Conn
+	AlertsService services.Alerts
+	Log           *zap.Logger
+}
+
+func NewListener(cfg Config) *NatsListeners {
+	return &NatsListeners{
+		natsConn:      cfg.NatsConn,
+		alertsService: cfg.AlertsService,
+		log:           cfg.Log,
+	}
+}
+
+func (n *NatsListeners) Listen() error {
+	_, err := n.natsConn.QueueSubscribe(readAlertsSubject, alertsQueue, n.readHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+readAlertsSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(acknowledgeAlertsSubject, alertsQueue, n.acknowledgeHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+acknowledgeAlertsSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(resolveAlertsSubject, alertsQueue, n.resolveHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+resolveAlertsSubject+"): %w", err)
+	}
+
+	return nil
+}
+
+func (n *NatsListeners) readHandler(msg *nats.Msg) {
+	var request pbalerts.ReadReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbalerts.ReadResp{Error: err.Error()})
+		return
+	}
+
+	var state *string
+	if request.GetState() != "" {
+		state = &request.State
+	}
+	var deviceID *int32
+	if request.GetDeviceID() != 0 {
+		deviceID = &request.DeviceID
+	}
+
+	alerts, err := n.alertsService.Read(context.Background(),
+		services.ReadAlertsParams{
+			State:    state,
+			DeviceID: deviceID,
+			Limit:    int(request.GetLimit()),
+		})
+	if err != nil {
+		n.log.Error("n.alertsService.Read", zap.Error(err))
+		n.sendError(msg.Reply, &pbalerts.ReadResp{Error: err.Error()})
+		return
+	}
+
+	resp := pbalerts.ReadResp{
+		Alerts: make([]*pbalerts.Alert, 0, len(alerts)),
+	}
+	for _, alert := range alerts {
+		resp.Alerts = append(resp.Alerts, convertAlertToProto(alert))
+	}
+
+	binaryResp, err := proto.Marshal(&resp)
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbalerts.ReadResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) acknowledgeHandler(msg *nats.Msg) {
+	var request pbalerts.AcknowledgeReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbalerts.AcknowledgeResp{Error: err.Error()})
+		return
+	}
+
+	alert, err := n.alertsService.Acknowledge(context.Background(), request.GetID(), request.GetUserID())
+	if err != nil {
+		n.log.Error("n.alertsService.Acknowledge", zap.Error(err))
+		n.sendError(msg.Reply, &pbalerts.AcknowledgeResp{Error: err.Error()})
+		return
+	}
+
+	binaryResp, err := proto.Marshal(&pbalerts.AcknowledgeResp{Alert: convertAlertToProto(alert)})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbalerts.AcknowledgeResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) resolveHandler(msg *nats.Msg) {
+	var request pbalerts.ResolveReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbalerts.ResolveResp{Error: err.Error()})
+		return
+	}
+
+	alert, err := n.alertsService.Resolve(context.Background(), request.GetID(), request.GetUserID())
+	if err != nil {
+		n.log.Error("n.alertsService.Resolve", zap.Error(err))
+		n.sendError(msg.Reply, &pbalerts.ResolveResp{Error: err.Error()})
+		return
+	}
+
+	binaryResp, err := proto.Marshal(&pbalerts.ResolveResp{Alert: convertAlertToProto(alert)})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbalerts.ResolveResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) sendError(subject string, message proto.Message) {
+	binaryResp, err := proto.Marshal(message)
+	if err != nil {
+		n.log.Error("sendError: proto.Marshal", zap.Error(err))
+		return
+	}
+	if err := n.natsConn.Publish(subject, binaryResp); err != nil {
+		n.log.Error("sendError: n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func convertAlertToProto(alert models.Alert) *pbalerts.Alert {
+	return &pbalerts.Alert{
+		ID:             alert.ID,
+		RuleType:       alert.RuleType,
+		RuleID:         alert.RuleID,
+		DeviceID:       alert.DeviceId,
+		State:          alert.State,
+		Subject:        alert.Subject,
+		SeverityLevel:  alert.SeverityLevel,
+		Message:        alert.Message,
+		Value:          alert.Value,
+		Count:          alert.Count,
+		OpenedAt:       timestamppb.New(alert.OpenedAt),
+		LastSeenAt:     timestamppb.New(alert.LastSeenAt),
+		AcknowledgedAt: optionalTimestamp(alert.AcknowledgedAt),
+		AcknowledgedBy: optionalInt32(alert.AcknowledgedBy),
+		ResolvedAt:     optionalTimestamp(alert.ResolvedAt),
+		ResolvedBy:     optionalInt32(alert.ResolvedBy),
+		Problem:        alert.Problem,
+		FlappingSince:  optionalTimestamp(alert.FlappingSince),
+	}
+}
+
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func optionalInt32(v *int32) int32 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
@@ -0,0 +1,196 @@
+package heartbeatslistener
+
+import (
+	"context"
+	"fmt"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/services"
+	pbheartbeats "data-processing-service/proto/api-gateway/heartbeats"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	createHeartbeatsSubject = "heartbeats.create"
+	readHeartbeatsSubject   = "heartbeats.read"
+	deleteHeartbeatsSubject = "heartbeats.delete"
+
+	heartbeatsQueue = "heartbeats"
+)
+
+type NatsListeners struct {
+	natsConn          *nats.Conn
+	heartbeatsService services.Heartbeats
+	log               *zap.Logger
+}
+
+type Config struct {
+	NatsConn          *nats.Conn
+	HeartbeatsService services.Heartbeats
+	Log               *zap.Logger
+}
+
+func NewListener(cfg Config) *NatsListeners {
+	return &NatsListeners{
+		natsConn:          cfg.NatsConn,
+		heartbeatsService: cfg.HeartbeatsService,
+		log:               cfg.Log,
+	}
+}
+
+func (n *NatsListeners) Listen() error {
+	_, err := n.natsConn.QueueSubscribe(createHeartbeatsSubject, heartbeatsQueue, n.createHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+createHeartbeatsSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(readHeartbeatsSubject, heartbeatsQueue, n.readHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+readHeartbeatsSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(deleteHeartbeatsSubject, heartbeatsQueue, n.deleteHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+deleteHeartbeatsSubject+"): %w", err)
+	}
+
+	return nil
+}
+
+func (n *NatsListeners) createHandler(msg *nats.Msg) {
+	var request pbheartbeats.CreateReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbheartbeats.CreateResp{Error: err.Error()})
+		return
+	}
+
+	created, err := n.heartbeatsService.Create(context.Background(),
+		models.Heartbeat{
+			Name:          request.Heartbeat.GetName(),
+			DeviceId:      request.Heartbeat.GetDeviceID(),
+			DeviceType:    request.Heartbeat.GetDeviceType(),
+			MessageType:   request.Heartbeat.GetMessageType(),
+			Interval:      request.Heartbeat.GetIntervalSec(),
+			Subject:       request.Heartbeat.GetSubject(),
+			SeverityLevel: request.Heartbeat.GetSeverityLevel(),
+		})
+	if err != nil {
+		n.log.Error("n.heartbeatsService.Create", zap.Error(err))
+		n.sendError(msg.Reply, &pbheartbeats.CreateResp{Error: err.Error()})
+		return
+	}
+
+	binaryResp, err := proto.Marshal(&pbheartbeats.CreateResp{
+		Created: convertHeartbeatToProto(created),
+	})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbheartbeats.CreateResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func (n *NatsListeners) readHandler(msg *nats.Msg) {
+	heartbeats, err := n.heartbeatsService.Read(context.Background())
+	if err != nil {
+		n.log.Error("n.heartbeatsService.Read", zap.Error(err))
+		n.sendError(msg.Reply, &pbheartbeats.ReadResp{Error: err.Error()})
+		return
+	}
+
+	resp := pbheartbeats.ReadResp{
+		Heartbeats: make([]*pbheartbeats.Heartbeat, 0, len(heartbeats)),
+	}
+	for _, heartbeat := range heartbeats {
+		resp.Heartbeats = append(resp.Heartbeats, convertHeartbeatToProto(heartbeat))
+	}
+
+	binaryResp, err := proto.Marshal(&resp)
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbheartbeats.ReadResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) deleteHandler(msg *nats.Msg) {
+	var request pbheartbeats.DeleteReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbheartbeats.DeleteResp{Error: err.Error()})
+		return
+	}
+
+	err = n.heartbeatsService.Delete(context.Background(), request.GetID())
+	if err != nil {
+		n.log.Error("n.heartbeatsService.Delete", zap.Error(err))
+		n.sendError(msg.Reply, &pbheartbeats.DeleteResp{Error: err.Error()})
+		return
+	}
+
+	binaryResp, err := proto.Marshal(&pbheartbeats.DeleteResp{})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbheartbeats.DeleteResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func (n *NatsListeners) sendError(subject string, message proto.Message) {
+	binaryResp, err := proto.Marshal(message)
+	if err != nil {
+		n.log.Error("sendError: proto.Marshal", zap.Error(err))
+		return
+	}
+	if err := n.natsConn.Publish(subject, binaryResp); err != nil {
+		n.log.Error("sendError: n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func convertHeartbeatToProto(heartbeat models.Heartbeat) *pbheartbeats.Heartbeat {
+	return &pbheartbeats.Heartbeat{
+		ID:            heartbeat.ID,
+		Name:          heartbeat.Name,
+		DeviceID:      heartbeat.DeviceId,
+		DeviceType:    heartbeat.DeviceType,
+		MessageType:   heartbeat.MessageType,
+		IntervalSec:   heartbeat.Interval,
+		Subject:       heartbeat.Subject,
+		SeverityLevel: heartbeat.SeverityLevel,
+		CreatedAt:     timestamppb.New(heartbeat.CreatedAt),
+	}
+}
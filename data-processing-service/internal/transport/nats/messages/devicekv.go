@@ -0,0 +1,145 @@
+package messagelisteners
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"data-processing-service/internal/models"
+	pbdevices "data-processing-service/proto/devices"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// devicesBucket is materialised by device-management-service, every device is
+// stored under its id.
+const devicesBucket = "devices"
+
+// devicesWatchRetry is the pause before a stopped watcher of the devices
+// bucket is started again.
+const devicesWatchRetry = 5 * time.Second
+
+// bindDevicesBucket binds the devices bucket. If device-management-service
+// did not create it yet, it is created with the same config, so the services
+// start in any order and the devices show up once it fills the bucket.
+func bindDevicesBucket(js nats.JetStreamContext) (nats.KeyValue, error) {
+	kv, err := js.KeyValue(devicesBucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      devicesBucket,
+			Description: "Devices by id, written by device-management-service",
+			History:     1,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("js.KeyValue("+devicesBucket+"): %w", err)
+	}
+
+	return kv, nil
+}
+
+// watchDevices loads the devices bucket into the messages service and keeps
+// applying the changes of single devices. It returns once the current content
+// is loaded.
+func (n *NatsListeners) watchDevices() error {
+	kv, err := bindDevicesBucket(n.js)
+	if err != nil {
+		return fmt.Errorf("bindDevicesBucket: %w", err)
+	}
+
+	watcher, err := kv.WatchAll()
+	if err != nil {
+		return fmt.Errorf("kv.WatchAll: %w", err)
+	}
+
+	// A nil entry marks the end of the initial values.
+	for entry := range watcher.Updates() {
+		if entry == nil {
+			break
+		}
+		n.applyDeviceEntry(entry)
+	}
+
+	n.messagesService.SetDeviceLoader(func(deviceID int32) (models.Device, bool, error) {
+		entry, err := kv.Get(strconv.FormatInt(int64(deviceID), 10))
+		if errors.Is(err, nats.ErrKeyNotFound) {
+			return models.Device{}, false, nil
+		}
+		if err != nil {
+			return models.Device{}, false, fmt.Errorf("kv.Get: %w", err)
+		}
+
+		device, err := unmarshalDevice(entry.Value())
+		if err != nil {
+			return models.Device{}, false, err
+		}
+
+		return device, true, nil
+	})
+
+	go n.followDevices(kv, watcher)
+
+	return nil
+}
+
+// followDevices applies the changes of single devices. A stopped watcher is
+// started again, its initial values bring the cache up to date.
+func (n *NatsListeners) followDevices(kv nats.KeyValue, watcher nats.KeyWatcher) {
+	for {
+		for entry := range watcher.Updates() {
+			if entry != nil {
+				n.applyDeviceEntry(entry)
+			}
+		}
+		n.log.Warn("devices bucket watcher stopped, restarting")
+
+		for {
+			time.Sleep(devicesWatchRetry)
+
+			var err error
+			watcher, err = kv.WatchAll()
+			if err == nil {
+				break
+			}
+			n.log.Error("kv.WatchAll", zap.Error(err))
+		}
+	}
+}
+
+func (n *NatsListeners) applyDeviceEntry(entry nats.KeyValueEntry) {
+	if entry.Operation() != nats.KeyValuePut {
+		deviceID, err := strconv.ParseInt(entry.Key(), 10, 32)
+		if err != nil {
+			n.log.Warn("strconv.ParseInt", zap.Error(err), zap.String("key", entry.Key()))
+			return
+		}
+		n.messagesService.DeleteDevice(int32(deviceID))
+		return
+	}
+
+	device, err := unmarshalDevice(entry.Value())
+	if err != nil {
+		n.log.Error("unmarshalDevice", zap.Error(err), zap.String("key", entry.Key()))
+		return
+	}
+
+	n.messagesService.UpsertDevice(device)
+}
+
+func unmarshalDevice(data []byte) (models.Device, error) {
+	var device pbdevices.Device
+	if err := proto.Unmarshal(data, &device); err != nil {
+		return models.Device{}, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	return models.Device{
+		ID:          device.GetID(),
+		Name:        device.GetName(),
+		DeviceType:  device.GetDeviceType(),
+		Address:     device.GetAddress(),
+		Responsible: device.GetResponsible(),
+	}, nil
+}
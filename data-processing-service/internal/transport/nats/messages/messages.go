@@ -3,11 +3,11 @@ package messagelisteners
 import (
 	"data-processing-service/internal/models"
 	"data-processing-service/internal/services"
-	pbdevices "data-processing-service/proto/devices"
 	pbmessages "data-processing-service/proto/messages"
 	pbnotification "data-processing-service/proto/notification"
 	"time"
 
+	"errors"
 	"fmt"
 
 	"github.com/nats-io/nats.go"
@@ -20,15 +20,14 @@ const (
 	saveMessageSubject = "monitoring.msg.save"
 	sendNotifySubject  = "monitoring.notify.send"
 
-	readDevicesSubject    = "devices.read"
-	devicesUpdatedSubject = "devices.updated"
-
 	reportGetAllByPeriod        = "report.get_all_by_period"
 	reportGetAllByDeviceId      = "report.get_all_by_device_id"
 	reportGetCountByMessageType = "report.get_count_by_message_type"
 	reportGetMonthReport        = "report.get_month_report"
 
 	dataProcessingQueue = "data-processing"
+
+	monitoringStream = "monitoring"
 )
 
 type NatsListeners struct {
@@ -37,6 +36,11 @@ type NatsListeners struct {
 	messagesService services.Messages
 	timeout         time.Duration
 	log             *zap.Logger
+
+	duplicatesWindow      time.Duration
+	flapCheckPeriod       time.Duration
+	heartbeatCheckPeriod  time.Duration
+	suppressedFlushPeriod time.Duration
 }
 
 type Config struct {
@@ -45,6 +49,18 @@ type Config struct {
 	Timeout         time.Duration
 	Log             *zap.Logger
 	Js              nats.JetStreamContext
+
+	// DuplicatesWindow is how long the stream remembers Nats-Msg-Id.
+	DuplicatesWindow time.Duration
+	// FlapCheckPeriod is how often flapping alerts are checked for having
+	// settled, 0 never checks.
+	FlapCheckPeriod time.Duration
+	// HeartbeatCheckPeriod is how often heartbeats are checked for missed
+	// messages, 0 never checks.
+	HeartbeatCheckPeriod time.Duration
+	// SuppressedFlushPeriod is how often the counts of suppressed repeats
+	// are sent once their notification period ended, 0 never sends them.
+	SuppressedFlushPeriod time.Duration
 }
 
 func NewListener(cfg Config) *NatsListeners {
@@ -54,24 +70,39 @@ func NewListener(cfg Config) *NatsListeners {
 		messagesService: cfg.MessagesService,
 		log:             cfg.Log,
 		timeout:         cfg.Timeout,
+
+		duplicatesWindow:      cfg.DuplicatesWindow,
+		flapCheckPeriod:       cfg.FlapCheckPeriod,
+		heartbeatCheckPeriod:  cfg.HeartbeatCheckPeriod,
+		suppressedFlushPeriod: cfg.SuppressedFlushPeriod,
 	}
 }
 
 func (n *NatsListeners) Listen() error {
-	n.js.AddStream(
-		&nats.StreamConfig{
-			Name:     "monitoring",
-			Subjects: []string{saveMessageSubject, sendNotifySubject},
-		},
-	)
-	_, err := n.js.QueueSubscribe(saveMessageSubject, dataProcessingQueue, n.createHandler)
+	streamConfig := &nats.StreamConfig{
+		Name:       monitoringStream,
+		Subjects:   []string{saveMessageSubject, sendNotifySubject},
+		Duplicates: n.duplicatesWindow,
+	}
+
+	_, err := n.js.AddStream(streamConfig)
+	if errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		_, err = n.js.UpdateStream(streamConfig)
+	}
 	if err != nil {
-		return fmt.Errorf("n.js.Subscribe("+saveMessageSubject+"): %w", err)
+		return fmt.Errorf("n.js.AddStream("+monitoringStream+"): %w", err)
 	}
 
-	_, err = n.natsConn.QueueSubscribe(devicesUpdatedSubject, dataProcessingQueue, n.updateDevicesHandler)
+	// Devices are loaded first, so the first messages are not reported with
+	// an unknown device.
+	err = n.watchDevices()
 	if err != nil {
-		return fmt.Errorf("n.natsConn.Subscribe("+devicesUpdatedSubject+"): %w", err)
+		return fmt.Errorf("n.watchDevices(): %w", err)
+	}
+
+	_, err = n.js.QueueSubscribe(saveMessageSubject, dataProcessingQueue, n.createHandler)
+	if err != nil {
+		return fmt.Errorf("n.js.Subscribe("+saveMessageSubject+"): %w", err)
 	}
 
 	_, err = n.natsConn.QueueSubscribe(reportGetAllByPeriod, dataProcessingQueue, n.getAllByPeriodHandler)
@@ -94,42 +125,46 @@ func (n *NatsListeners) Listen() error {
 		return fmt.Errorf("n.natsConn.Subscribe("+reportGetMonthReport+"): %w", err)
 	}
 
-	n.updateDevicesHandler(nil)
+	if n.flapCheckPeriod > 0 {
+		go func() {
+			ticker := time.NewTicker(n.flapCheckPeriod)
+			defer ticker.Stop()
 
-	return nil
-}
-
-func (n *NatsListeners) updateDevicesHandler(msg *nats.Msg) {
-	result, err := n.natsConn.Request(readDevicesSubject, nil, n.timeout)
-	if err != nil {
-		n.log.Error("n.natsConn.Request", zap.Error(err))
-		return
+			for range ticker.C {
+				for _, settled := range n.messagesService.SettleFlapping() {
+					n.publishNotification(settled.DeviceID, settled.Notification)
+				}
+			}
+		}()
 	}
 
-	var response pbdevices.ReadResp
-	err = proto.Unmarshal(result.Data, &response)
-	if err != nil {
-		n.log.Error("proto.Unmarshal", zap.Error(err))
-		return
-	}
+	if n.heartbeatCheckPeriod > 0 {
+		go func() {
+			ticker := time.NewTicker(n.heartbeatCheckPeriod)
+			defer ticker.Stop()
 
-	if response.Error != "" {
-		n.log.Error("response.Error", zap.String("Error", response.Error))
-		return
+			for range ticker.C {
+				for _, missed := range n.messagesService.CheckHeartbeats() {
+					n.publishNotification(missed.DeviceID, missed.Notification)
+				}
+			}
+		}()
 	}
 
-	var devices []models.Device
-	for _, device := range response.Devices {
-		devices = append(devices, models.Device{
-			ID:          device.ID,
-			Name:        device.Name,
-			DeviceType:  device.DeviceType,
-			Address:     device.Address,
-			Responsible: device.Responsible,
-		})
+	if n.suppressedFlushPeriod > 0 {
+		go func() {
+			ticker := time.NewTicker(n.suppressedFlushPeriod)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				for _, flushed := range n.messagesService.FlushSuppressed() {
+					n.publishNotification(flushed.DeviceID, flushed.Notification)
+				}
+			}
+		}()
 	}
 
-	n.messagesService.SetDevices(devices)
+	return nil
 }
 
 func (n *NatsListeners) createHandler(msg *nats.Msg) {
@@ -144,25 +179,42 @@ func (n *NatsListeners) createHandler(msg *nats.Msg) {
 		)
 	}
 
-	notify, needNotify, err := n.messagesService.Create(models.Message{
+	message := models.Message{
 		DeviceId:    request.DeviceID,
 		Message:     request.Message,
 		MessageType: request.MessageType,
 		Component:   request.Component,
-	})
+		Attributes:  request.Attributes,
+	}
+	if request.EventTime != nil {
+		eventTime := request.EventTime.AsTime().Local()
+		message.EventTime = &eventTime
+	}
+	if request.ReceivedAt != nil {
+		receivedAt := request.ReceivedAt.AsTime().Local()
+		message.ReceivedAt = &receivedAt
+	}
+	if request.MessageID != "" {
+		message.MessageID = &request.MessageID
+	}
+
+	notifications, err := n.messagesService.Create(message)
 	if err != nil {
 		n.log.Error("n.messagesService.Create", zap.Error(err))
 		return
 	}
 
-	if !needNotify {
-		return
+	for _, notify := range notifications {
+		n.publishNotification(request.DeviceID, notify)
 	}
+}
 
+func (n *NatsListeners) publishNotification(deviceID int32, notify services.CreateMessageResponse) {
 	binaryResp, err := proto.Marshal(&pbnotification.SendNotifyReq{
-		DeviceID: request.DeviceID,
-		Text:     notify.Text,
-		Subject:  notify.Subject,
+		DeviceID:   deviceID,
+		Text:       notify.Text,
+		Subject:    notify.Subject,
+		Suppressed: notify.Suppressed,
 	})
 	if err != nil {
 		n.log.Error("proto.Marshal", zap.Error(err))
@@ -171,7 +223,6 @@ func (n *NatsListeners) createHandler(msg *nats.Msg) {
 
 	if _, err := n.js.Publish(sendNotifySubject, binaryResp); err != nil {
 		n.log.Error("n.natsConn.Publish", zap.Error(err))
-		return
 	}
 }
 
@@ -191,8 +242,9 @@ func (n *NatsListeners) getAllByPeriodHandler(msg *nats.Msg) {
 	}
 
 	report, err := n.messagesService.GetAllByPeriod(services.MessagesGetAllByPeriodOpts{
-		StartTime: request.StartTime.AsTime(),
-		EndTime:   request.EndTime.AsTime(),
+		StartTime:   request.StartTime.AsTime(),
+		EndTime:     request.EndTime.AsTime(),
+		ByEventTime: request.GetByEventTime(),
 	})
 	if err != nil {
 		n.log.Error("n.messagesService.GetAllByPeriod", zap.Error(err))
@@ -227,11 +279,23 @@ func convertGetAllByPeriodToProto(report []services.ReportGetAllByPeriod) []*pbm
 			GotAt:       timestamppb.New(item.GotAt),
 			Message:     item.Message,
 			MessageType: item.MessageType,
+			EventTime:   optionalTimestamp(item.EventTime),
+			ReceivedAt:  optionalTimestamp(item.ReceivedAt),
+			Attributes:  item.Attributes,
+			Silenced:    item.Silenced,
+			SilenceID:   item.SilenceID,
 		})
 	}
 	return resp
 }
 
+func optionalTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
 func (n *NatsListeners) sendError(subject string, message proto.Message) {
 	binaryResp, err := proto.Marshal(message)
 	if err != nil {
@@ -259,7 +323,10 @@ func (n *NatsListeners) getAllByDeviceIdHandler(msg *nats.Msg) {
 		return
 	}
 
-	report, err := n.messagesService.GetAllByDeviceId(request.GetDeviceId())
+	report, err := n.messagesService.GetAllByDeviceId(services.MessagesGetAllByDeviceIdOpts{
+		DeviceID:    request.GetDeviceId(),
+		ByEventTime: request.GetByEventTime(),
+	})
 	if err != nil {
 		n.log.Error("n.messagesService.GetAllByDeviceId", zap.Error(err))
 		n.sendError(msg.Reply, &pbmessages.ReportGetAllByDeviceIdResp{Error: err.Error()})
@@ -293,6 +360,11 @@ func convertGetAllByDeviceIdToProto(report []services.ReportGetAllByDeviceId) []
 			GotAt:       timestamppb.New(item.GotAt),
 			Message:     item.Message,
 			MessageType: item.MessageType,
+			EventTime:   optionalTimestamp(item.EventTime),
+			ReceivedAt:  optionalTimestamp(item.ReceivedAt),
+			Attributes:  item.Attributes,
+			Silenced:    item.Silenced,
+			SilenceID:   item.SilenceID,
 		})
 	}
 	return resp
@@ -0,0 +1,524 @@
+package ruleslistener
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/repo"
+	"data-processing-service/internal/services"
+	pbrules "data-processing-service/proto/api-gateway/rules"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	createRulesSubject = "rules.create"
+	readRulesSubject   = "rules.read"
+	updateRulesSubject = "rules.update"
+	deleteRulesSubject = "rules.delete"
+	// readBaselinesSubject reads what the anomaly rules learned.
+	readBaselinesSubject = "rules.baselines"
+
+	// rulesChangedSubject is subscribed outside the queue group, every
+	// replica reloads the rules on it.
+	rulesChangedSubject = "rules.changed"
+
+	rulesQueue = "rules"
+)
+
+type NatsListeners struct {
+	natsConn        *nats.Conn
+	rulesService    services.Rules
+	messagesService services.Messages
+	reloadPeriod    time.Duration
+	log             *zap.Logger
+}
+
+type Config struct {
+	NatsConn        *nats.Conn
+	RulesService    services.Rules
+	MessagesService services.Messages
+	// ReloadPeriod is how often the rules are reloaded anyway, a replica
+	// that missed a change catches up then.
+	ReloadPeriod time.Duration
+	Log          *zap.Logger
+}
+
+func NewListener(cfg Config) *NatsListeners {
+	return &NatsListeners{
+		natsConn:        cfg.NatsConn,
+		rulesService:    cfg.RulesService,
+		messagesService: cfg.MessagesService,
+		reloadPeriod:    cfg.ReloadPeriod,
+		log:             cfg.Log,
+	}
+}
+
+func (n *NatsListeners) Listen() error {
+	_, err := n.natsConn.QueueSubscribe(createRulesSubject, rulesQueue, n.createHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+createRulesSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(readRulesSubject, rulesQueue, n.readHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+readRulesSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(updateRulesSubject, rulesQueue, n.updateHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+updateRulesSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(deleteRulesSubject, rulesQueue, n.deleteHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+deleteRulesSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(readBaselinesSubject, rulesQueue, n.readBaselinesHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+readBaselinesSubject+"): %w", err)
+	}
+
+	n.messagesService.SetRulesPublisher(n.publishRulesChanged)
+
+	_, err = n.natsConn.Subscribe(rulesChangedSubject, func(*nats.Msg) {
+		n.messagesService.UpdateRules()
+	})
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+rulesChangedSubject+"): %w", err)
+	}
+
+	if n.reloadPeriod > 0 {
+		go func() {
+			ticker := time.NewTicker(n.reloadPeriod)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				n.messagesService.UpdateRules()
+			}
+		}()
+	}
+
+	return nil
+}
+
+func (n *NatsListeners) publishRulesChanged() {
+	if err := n.natsConn.Publish(rulesChangedSubject, nil); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) createHandler(msg *nats.Msg) {
+	var request pbrules.CreateReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbrules.CreateResp{Error: err.Error()})
+		return
+	}
+
+	created, err := n.rulesService.Create(context.Background(),
+		models.Rule{
+			Name:          request.Rule.GetName(),
+			DeviceId:      request.Rule.GetDeviceID(),
+			Op:            request.Rule.GetOp(),
+			Conditions:    convertProtoConditions(request.Rule.GetConditions()),
+			Window:        convertProtoWindow(request.Rule.GetWindow()),
+			Anomaly:       convertProtoAnomaly(request.Rule.GetAnomaly()),
+			Subject:       request.Rule.GetSubject(),
+			SeverityLevel: request.Rule.GetSeverityLevel(),
+		})
+	if err != nil {
+		n.log.Error("n.rulesService.Create", zap.Error(err))
+		n.sendError(msg.Reply, &pbrules.CreateResp{Error: err.Error()})
+		return
+	}
+
+	binaryResp, err := proto.Marshal(&pbrules.CreateResp{
+		Created: convertRuleToProto(created),
+	})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbrules.CreateResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func (n *NatsListeners) readHandler(msg *nats.Msg) {
+	readResult, err := n.rulesService.Read(context.Background())
+	if err != nil {
+		n.log.Error("n.rulesService.Read", zap.Error(err))
+		n.sendError(msg.Reply, &pbrules.ReadResp{Error: err.Error()})
+		return
+	}
+
+	resp := pbrules.ReadResp{
+		Rules: make([]*pbrules.Rule, 0, len(readResult.Rules)),
+	}
+	for _, rule := range readResult.Rules {
+		resp.Rules = append(resp.Rules, convertRuleToProto(rule))
+	}
+
+	binaryResp, err := proto.Marshal(&resp)
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbrules.ReadResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) updateHandler(msg *nats.Msg) {
+	var request pbrules.UpdateReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbrules.UpdateResp{Error: err.Error()})
+		return
+	}
+
+	var name *string
+	if request.Rule.GetName() != "" {
+		name = &request.Rule.Name
+	}
+	var deviceId *int32
+	if request.Rule.GetDeviceID() != 0 {
+		deviceId = &request.Rule.DeviceID
+	}
+	var op *string
+	if request.Rule.GetOp() != "" {
+		op = &request.Rule.Op
+	}
+	var subject *string
+	if request.Rule.GetSubject() != "" {
+		subject = &request.Rule.Subject
+	}
+	var severityLevel *string
+	if request.Rule.GetSeverityLevel() != "" {
+		severityLevel = &request.Rule.SeverityLevel
+	}
+
+	err = n.rulesService.Update(context.Background(),
+		services.UpdateRuleParams{
+			ID:            request.Rule.GetID(),
+			Name:          name,
+			DeviceId:      deviceId,
+			Op:            op,
+			Conditions:    convertProtoConditions(request.Rule.GetConditions()),
+			Window:        convertProtoWindow(request.Rule.GetWindow()),
+			RemoveWindow:  request.GetRemoveWindow(),
+			Anomaly:       convertProtoAnomaly(request.Rule.GetAnomaly()),
+			RemoveAnomaly: request.GetRemoveAnomaly(),
+			Subject:       subject,
+			SeverityLevel: severityLevel,
+		})
+	if err != nil {
+		n.log.Error("n.rulesService.Update", zap.Error(err))
+		n.sendError(msg.Reply, &pbrules.UpdateResp{Error: err.Error()})
+		return
+	}
+
+	binaryResp, err := proto.Marshal(&pbrules.UpdateResp{})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbrules.UpdateResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func (n *NatsListeners) deleteHandler(msg *nats.Msg) {
+	var request pbrules.DeleteReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbrules.DeleteResp{Error: err.Error()})
+		return
+	}
+
+	err = n.rulesService.Delete(context.Background(), request.GetID())
+	if err != nil {
+		n.log.Error("n.rulesService.Delete", zap.Error(err))
+		n.sendError(msg.Reply, &pbrules.DeleteResp{Error: err.Error()})
+		return
+	}
+
+	binaryResp, err := proto.Marshal(&pbrules.DeleteResp{})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbrules.DeleteResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func (n *NatsListeners) readBaselinesHandler(msg *nats.Msg) {
+	var request pbrules.ReadBaselinesReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbrules.ReadBaselinesResp{Error: err.Error()})
+		return
+	}
+
+	var opts repo.ReadBaselinesOpts
+	if request.GetRuleID() != 0 {
+		opts.RuleID = &request.RuleID
+	}
+	if request.GetDeviceID() != 0 {
+		opts.DeviceID = &request.DeviceID
+	}
+
+	baselines, err := n.rulesService.ReadBaselines(context.Background(), opts)
+	if err != nil {
+		n.log.Error("n.rulesService.ReadBaselines", zap.Error(err))
+		n.sendError(msg.Reply, &pbrules.ReadBaselinesResp{Error: err.Error()})
+		return
+	}
+
+	resp := pbrules.ReadBaselinesResp{
+		Baselines: make([]*pbrules.Baseline, 0, len(baselines)),
+	}
+	for _, baseline := range baselines {
+		resp.Baselines = append(resp.Baselines, convertBaselineToProto(baseline))
+	}
+
+	binaryResp, err := proto.Marshal(&resp)
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbrules.ReadBaselinesResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) sendError(subject string, message proto.Message) {
+	binaryResp, err := proto.Marshal(message)
+	if err != nil {
+		n.log.Error("sendError: proto.Marshal", zap.Error(err))
+		return
+	}
+	if err := n.natsConn.Publish(subject, binaryResp); err != nil {
+		n.log.Error("sendError: n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func convertRuleToProto(rule models.Rule) *pbrules.Rule {
+	var createdAt *timestamppb.Timestamp
+	if rule.CreatedAt != nil {
+		createdAt = timestamppb.New(*rule.CreatedAt)
+	}
+
+	var updatedAt *timestamppb.Timestamp
+	if rule.UpdatedAt != nil {
+		updatedAt = timestamppb.New(*rule.UpdatedAt)
+	}
+
+	return &pbrules.Rule{
+		ID:            rule.ID,
+		Name:          rule.Name,
+		DeviceID:      rule.DeviceId,
+		Op:            rule.Op,
+		Conditions:    convertConditionsToProto(rule.Conditions),
+		Window:        convertWindowToProto(rule.Window),
+		Anomaly:       convertAnomalyToProto(rule.Anomaly),
+		Subject:       rule.Subject,
+		SeverityLevel: rule.SeverityLevel,
+		CreatedAt:     createdAt,
+		UpdatedAt:     updatedAt,
+	}
+}
+
+func convertConditionsToProto(conditions []models.RuleCondition) []*pbrules.Condition {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	result := make([]*pbrules.Condition, 0, len(conditions))
+	for _, c := range conditions {
+		result = append(result, &pbrules.Condition{
+			Op:          c.Op,
+			Conditions:  convertConditionsToProto(c.Conditions),
+			Field:       c.Field,
+			Regexp:      c.Regexp,
+			ArrayIndex:  c.ArrayIndex,
+			CompareType: c.CompareType,
+			Value:       c.Value,
+		})
+	}
+	return result
+}
+
+func convertProtoConditions(conditions []*pbrules.Condition) []models.RuleCondition {
+	if len(conditions) == 0 {
+		return nil
+	}
+
+	result := make([]models.RuleCondition, 0, len(conditions))
+	for _, c := range conditions {
+		result = append(result, models.RuleCondition{
+			Op:          c.GetOp(),
+			Conditions:  convertProtoConditions(c.GetConditions()),
+			Field:       c.GetField(),
+			Regexp:      c.GetRegexp(),
+			ArrayIndex:  c.GetArrayIndex(),
+			CompareType: c.GetCompareType(),
+			Value:       c.GetValue(),
+		})
+	}
+	return result
+}
+
+func convertWindowToProto(window *models.RuleWindow) *pbrules.Window {
+	if window == nil {
+		return nil
+	}
+
+	return &pbrules.Window{
+		Type:        window.Type,
+		DurationSec: window.DurationSec,
+		Aggregation: window.Aggregation,
+		Field:       window.Field,
+		Regexp:      window.Regexp,
+		ArrayIndex:  window.ArrayIndex,
+		CompareType: window.CompareType,
+		Threshold:   window.Threshold,
+	}
+}
+
+func convertProtoWindow(window *pbrules.Window) *models.RuleWindow {
+	if window == nil {
+		return nil
+	}
+
+	return &models.RuleWindow{
+		Type:        window.GetType(),
+		DurationSec: window.GetDurationSec(),
+		Aggregation: window.GetAggregation(),
+		Field:       window.GetField(),
+		Regexp:      window.GetRegexp(),
+		ArrayIndex:  window.GetArrayIndex(),
+		CompareType: window.GetCompareType(),
+		Threshold:   window.GetThreshold(),
+	}
+}
+
+func convertAnomalyToProto(anomaly *models.RuleAnomaly) *pbrules.Anomaly {
+	if anomaly == nil {
+		return nil
+	}
+
+	return &pbrules.Anomaly{
+		Field:       anomaly.Field,
+		Regexp:      anomaly.Regexp,
+		ArrayIndex:  anomaly.ArrayIndex,
+		Alpha:       anomaly.Alpha,
+		Sigma:       anomaly.Sigma,
+		Direction:   anomaly.Direction,
+		Seasonality: anomaly.Seasonality,
+		MinSamples:  anomaly.MinSamples,
+	}
+}
+
+func convertProtoAnomaly(anomaly *pbrules.Anomaly) *models.RuleAnomaly {
+	if anomaly == nil {
+		return nil
+	}
+
+	return &models.RuleAnomaly{
+		Field:       anomaly.GetField(),
+		Regexp:      anomaly.GetRegexp(),
+		ArrayIndex:  anomaly.GetArrayIndex(),
+		Alpha:       anomaly.GetAlpha(),
+		Sigma:       anomaly.GetSigma(),
+		Direction:   anomaly.GetDirection(),
+		Seasonality: anomaly.GetSeasonality(),
+		MinSamples:  anomaly.GetMinSamples(),
+	}
+}
+
+func convertBaselineToProto(baseline models.AnomalyBaseline) *pbrules.Baseline {
+	var lastAnomalyAt *timestamppb.Timestamp
+	if baseline.LastAnomalyAt != nil {
+		lastAnomalyAt = timestamppb.New(*baseline.LastAnomalyAt)
+	}
+
+	var updatedAt *timestamppb.Timestamp
+	if baseline.UpdatedAt != nil {
+		updatedAt = timestamppb.New(*baseline.UpdatedAt)
+	}
+
+	seasons := make([]*pbrules.Season, 0, len(baseline.Seasons))
+	for _, season := range baseline.Seasons {
+		seasons = append(seasons, &pbrules.Season{
+			Offset:  season.Offset,
+			Samples: season.Samples,
+		})
+	}
+
+	return &pbrules.Baseline{
+		RuleID:        baseline.RuleID,
+		DeviceID:      baseline.DeviceId,
+		Anomaly:       convertAnomalyToProto(&baseline.Anomaly),
+		Samples:       baseline.Samples,
+		Level:         baseline.Level,
+		Variance:      baseline.Variance,
+		Seasons:       seasons,
+		LastValue:     baseline.LastValue,
+		LastExpected:  baseline.LastExpected,
+		LastScore:     baseline.LastScore,
+		Anomalous:     baseline.Anomalous,
+		LastAnomalyAt: lastAnomalyAt,
+		UpdatedAt:     updatedAt,
+	}
+}
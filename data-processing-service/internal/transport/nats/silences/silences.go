@@ -0,0 +1,258 @@
+package silenceslistener
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"data-processing-service/internal/models"
+	"data-processing-service/internal/services"
+	pbsilences "data-processing-service/proto/api-gateway/silences"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	createSilencesSubject = "silences.create"
+	readSilencesSubject   = "silences.read"
+	deleteSilencesSubject = "silences.delete"
+
+	// silencesChangedSubject is subscribed outside the queue group, every
+	// replica reloads the silences on it.
+	silencesChangedSubject = "silences.changed"
+
+	silencesQueue = "silences"
+)
+
+type NatsListeners struct {
+	natsConn        *nats.Conn
+	silencesService services.Silences
+	messagesService services.Messages
+	reloadPeriod    time.Duration
+	log             *zap.Logger
+}
+
+type Config struct {
+	NatsConn        *nats.Conn
+	SilencesService services.Silences
+	MessagesService services.Messages
+	// ReloadPeriod is how often the silences are reloaded anyway, a replica
+	// that missed a change catches up then.
+	ReloadPeriod time.Duration
+	Log          *zap.Logger
+}
+
+func NewListener(cfg Config) *NatsListeners {
+	return &NatsListeners{
+		natsConn:        cfg.NatsConn,
+		silencesService: cfg.SilencesService,
+		messagesService: cfg.MessagesService,
+		reloadPeriod:    cfg.ReloadPeriod,
+		log:             cfg.Log,
+	}
+}
+
+func (n *NatsListeners) Listen() error {
+	_, err := n.natsConn.QueueSubscribe(createSilencesSubject, silencesQueue, n.createHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+createSilencesSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(readSilencesSubject, silencesQueue, n.readHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+readSilencesSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(deleteSilencesSubject, silencesQueue, n.deleteHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+deleteSilencesSubject+"): %w", err)
+	}
+
+	n.messagesService.SetSilencesPublisher(n.publishSilencesChanged)
+
+	_, err = n.natsConn.Subscribe(silencesChangedSubject, func(*nats.Msg) {
+		n.messagesService.UpdateSilences()
+	})
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+silencesChangedSubject+"): %w", err)
+	}
+
+	if n.reloadPeriod > 0 {
+		go func() {
+			ticker := time.NewTicker(n.reloadPeriod)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				n.messagesService.UpdateSilences()
+			}
+		}()
+	}
+
+	return nil
+}
+
+func (n *NatsListeners) publishSilencesChanged() {
+	if err := n.natsConn.Publish(silencesChangedSubject, nil); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) createHandler(msg *nats.Msg) {
+	var request pbsilences.CreateReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbsilences.CreateResp{Error: err.Error()})
+		return
+	}
+
+	silence := request.GetSilence()
+	created, err := n.silencesService.Create(context.Background(),
+		models.Silence{
+			DeviceId:   silence.GetDeviceID(),
+			DeviceType: silence.GetDeviceType(),
+			RuleName:   silence.GetRuleName(),
+			Component:  silence.GetComponent(),
+			StartsAt:   silence.GetStartsAt().AsTime().Local(),
+			EndsAt:     silence.GetEndsAt().AsTime().Local(),
+			Recurrence: silence.GetRecurrence(),
+			Duration:   silence.GetDurationSec(),
+			CreatedBy:  silence.GetCreatedBy(),
+			Comment:    silence.GetComment(),
+		})
+	if err != nil {
+		n.log.Error("n.silencesService.Create", zap.Error(err))
+		n.sendError(msg.Reply, &pbsilences.CreateResp{Error: err.Error()})
+		return
+	}
+
+	binaryResp, err := proto.Marshal(&pbsilences.CreateResp{
+		Created: convertSilenceToProto(created),
+	})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbsilences.CreateResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func (n *NatsListeners) readHandler(msg *nats.Msg) {
+	var request pbsilences.ReadReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbsilences.ReadResp{Error: err.Error()})
+		return
+	}
+
+	silences, err := n.silencesService.Read(context.Background(), services.ReadSilencesParams{
+		WithExpired: request.GetWithExpired(),
+	})
+	if err != nil {
+		n.log.Error("n.silencesService.Read", zap.Error(err))
+		n.sendError(msg.Reply, &pbsilences.ReadResp{Error: err.Error()})
+		return
+	}
+
+	resp := pbsilences.ReadResp{
+		Silences: make([]*pbsilences.Silence, 0, len(silences)),
+	}
+	for _, silence := range silences {
+		resp.Silences = append(resp.Silences, convertSilenceToProto(silence))
+	}
+
+	binaryResp, err := proto.Marshal(&resp)
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbsilences.ReadResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) deleteHandler(msg *nats.Msg) {
+	var request pbsilences.DeleteReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbsilences.DeleteResp{Error: err.Error()})
+		return
+	}
+
+	err = n.silencesService.Delete(context.Background(), request.GetID())
+	if err != nil {
+		n.log.Error("n.silencesService.Delete", zap.Error(err))
+		n.sendError(msg.Reply, &pbsilences.DeleteResp{Error: err.Error()})
+		return
+	}
+
+	binaryResp, err := proto.Marshal(&pbsilences.DeleteResp{})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbsilences.DeleteResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func (n *NatsListeners) sendError(subject string, message proto.Message) {
+	binaryResp, err := proto.Marshal(message)
+	if err != nil {
+		n.log.Error("sendError: proto.Marshal", zap.Error(err))
+		return
+	}
+	if err := n.natsConn.Publish(subject, binaryResp); err != nil {
+		n.log.Error("sendError: n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func convertSilenceToProto(silence models.Silence) *pbsilences.Silence {
+	return &pbsilences.Silence{
+		ID:          silence.ID,
+		DeviceID:    silence.DeviceId,
+		DeviceType:  silence.DeviceType,
+		RuleName:    silence.RuleName,
+		Component:   silence.Component,
+		StartsAt:    timestamppb.New(silence.StartsAt),
+		EndsAt:      timestamppb.New(silence.EndsAt),
+		Recurrence:  silence.Recurrence,
+		DurationSec: silence.Duration,
+		CreatedBy:   silence.CreatedBy,
+		Comment:     silence.Comment,
+		CreatedAt:   timestamppb.New(silence.CreatedAt),
+	}
+}
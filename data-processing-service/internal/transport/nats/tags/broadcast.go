@@ -0,0 +1,93 @@
+package tagslistener
+
+import (
+	"fmt"
+	"time"
+
+	pbapidevices "data-processing-service/proto/api-gateway/tags"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// Both subjects are subscribed outside the queue group, every replica gets
+// every message.
+const (
+	tagsChangedSubject = "tags.changed"
+	tagsVersionSubject = "tags.version"
+)
+
+// listenBroadcast keeps the tags of this replica in step with the changes
+// made through the others.
+func (n *NatsListeners) listenBroadcast() error {
+	n.messagesService.SetTagsPublisher(n.publishTagsChanged)
+
+	_, err := n.natsConn.Subscribe(tagsChangedSubject, n.tagsChangedHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+tagsChangedSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.Subscribe(tagsVersionSubject, n.versionHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+tagsVersionSubject+"): %w", err)
+	}
+
+	if n.versionCheckPeriod > 0 {
+		go func() {
+			ticker := time.NewTicker(n.versionCheckPeriod)
+			defer ticker.Stop()
+
+			for range ticker.C {
+				n.messagesService.CheckTagsVersion()
+			}
+		}()
+	}
+
+	return nil
+}
+
+func (n *NatsListeners) publishTagsChanged(version int64) {
+	data, err := proto.Marshal(&pbapidevices.TagsChanged{Version: version})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		return
+	}
+
+	if err = n.natsConn.Publish(tagsChangedSubject, data); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err), zap.Int64("version", version))
+	}
+}
+
+func (n *NatsListeners) tagsChangedHandler(msg *nats.Msg) {
+	var event pbapidevices.TagsChanged
+	if err := proto.Unmarshal(msg.Data, &event); err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+		return
+	}
+
+	n.messagesService.RefreshTags(event.GetVersion())
+}
+
+// versionHandler answers with the version this replica has loaded, a caller
+// collects the answers of all replicas.
+func (n *NatsListeners) versionHandler(msg *nats.Msg) {
+	binaryResp, err := proto.Marshal(&pbapidevices.VersionResp{
+		Instance: n.instance,
+		Version:  n.messagesService.TagsVersion(),
+	})
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbapidevices.VersionResp{Instance: n.instance, Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
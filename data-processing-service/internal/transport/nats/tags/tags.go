@@ -7,6 +7,7 @@ import (
 	pbapidevices "data-processing-service/proto/api-gateway/tags"
 
 	"fmt"
+	"time"
 
 	"github.com/nats-io/nats.go"
 	"go.uber.org/zap"
@@ -19,33 +20,46 @@ const (
 	readTagsSubject   = "tags.read"
 	updateTagsSubject = "tags.update"
 	deleteTagsSubject = "tags.delete"
-
-	readDevicesSubject = "devices.read"
+	testTagsSubject   = "tags.test"
 
 	tagsQueue = "devices"
 )
 
 type NatsListeners struct {
-	natsConn    *nats.Conn
-	tagsService services.Tags
-	log         *zap.Logger
+	natsConn           *nats.Conn
+	tagsService        services.Tags
+	messagesService    services.Messages
+	versionCheckPeriod time.Duration
+	instance           string
+	log                *zap.Logger
 }
 
 type Config struct {
-	NatsConn    *nats.Conn
-	TagsService services.Tags
-	Log         *zap.Logger
+	NatsConn        *nats.Conn
+	TagsService     services.Tags
+	MessagesService services.Messages
+	// VersionCheckPeriod is how often the loaded tags are compared with the
+	// stored ones, in case a broadcast was missed.
+	VersionCheckPeriod time.Duration
+	Log                *zap.Logger
 }
 
 func NewListener(cfg Config) *NatsListeners {
 	return &NatsListeners{
-		natsConn:    cfg.NatsConn,
-		tagsService: cfg.TagsService,
-		log:         cfg.Log,
+		natsConn:           cfg.NatsConn,
+		tagsService:        cfg.TagsService,
+		messagesService:    cfg.MessagesService,
+		versionCheckPeriod: cfg.VersionCheckPeriod,
+		instance:           services.InstanceName(cfg.Log),
+		log:                cfg.Log,
 	}
 }
 
 func (n *NatsListeners) Listen() error {
+	if err := n.listenBroadcast(); err != nil {
+		return fmt.Errorf("n.listenBroadcast: %w", err)
+	}
+
 	_, err := n.natsConn.QueueSubscribe(createTagsSubject, tagsQueue, n.createHandler)
 	if err != nil {
 		return fmt.Errorf("n.natsConn.Subscribe("+createTagsSubject+"): %w", err)
@@ -66,11 +80,12 @@ func (n *NatsListeners) Listen() error {
 		return fmt.Errorf("n.natsConn.Subscribe("+deleteTagsSubject+"): %w", err)
 	}
 
-	return nil
-}
-
-func (n *NatsListeners) PublishReadDevices() {
+	_, err = n.natsConn.QueueSubscribe(testTagsSubject, tagsQueue, n.testHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+testTagsSubject+"): %w", err)
+	}
 
+	return nil
 }
 
 func (n *NatsListeners) createHandler(msg *nats.Msg) {
@@ -164,7 +179,8 @@ func (n *NatsListeners) readHandler(msg *nats.Msg) {
 	}
 
 	readTagsResp := pbapidevices.ReadResp{
-		Tags: convertTagsToProtoTags(readResult.Tags),
+		Tags:    convertTagsToProtoTags(readResult.Tags),
+		Version: readResult.Version,
 	}
 
 	readTagsRespBytes, err := proto.Marshal(&readTagsResp)
@@ -323,3 +339,112 @@ func (n *NatsListeners) deleteHandler(msg *nats.Msg) {
 		return
 	}
 }
+
+func (n *NatsListeners) testHandler(msg *nats.Msg) {
+	var request pbapidevices.TestReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+
+		n.sendError(msg.Reply, &pbapidevices.TestResp{Error: err.Error()})
+		return
+	}
+
+	messages := make([]models.Message, 0, len(request.GetMessages()))
+	for _, m := range request.GetMessages() {
+		message := models.Message{
+			Message:     m.GetMessage(),
+			MessageType: m.GetMessageType(),
+			Component:   m.GetComponent(),
+			Attributes:  m.GetAttributes(),
+		}
+		if m.ReceivedAt != nil {
+			receivedAt := m.ReceivedAt.AsTime().Local()
+			message.ReceivedAt = &receivedAt
+		}
+		messages = append(messages, message)
+	}
+
+	results, err := n.tagsService.Test(context.Background(),
+		services.TestTagOpts{
+			Tag: models.Tag{
+				ID:            request.Tag.GetID(),
+				Name:          request.Tag.GetName(),
+				DeviceId:      request.Tag.GetDeviceID(),
+				Regexp:        request.Tag.GetRegexp(),
+				CompareType:   request.Tag.GetCompareType(),
+				Value:         request.Tag.GetValue(),
+				ArrayIndex:    request.Tag.GetArrayIndex(),
+				Subject:       request.Tag.GetSubject(),
+				SeverityLevel: request.Tag.GetSeverityLevel(),
+			},
+			Messages:  messages,
+			StartTime: request.GetStartTime().AsTime(),
+			EndTime:   request.GetEndTime().AsTime(),
+		})
+	if err != nil {
+		n.log.Error("n.tagsService.Test", zap.Error(err))
+		n.sendError(msg.Reply, &pbapidevices.TestResp{Error: err.Error()})
+		return
+	}
+
+	resp := pbapidevices.TestResp{
+		Results: convertTestResultsToProto(results),
+	}
+
+	binaryResp, err := proto.Marshal(&resp)
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbapidevices.TestResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+		return
+	}
+}
+
+func convertTestResultsToProto(results []services.TestTagResult) []*pbapidevices.TestResult {
+	result := make([]*pbapidevices.TestResult, 0, len(results))
+	for _, r := range results {
+		var receivedAt *timestamppb.Timestamp
+		if r.Message.ReceivedAt != nil {
+			receivedAt = timestamppb.New(*r.Message.ReceivedAt)
+		}
+		var gotAt *timestamppb.Timestamp
+		if !r.Message.GotAt.IsZero() {
+			gotAt = timestamppb.New(r.Message.GotAt)
+		}
+
+		notifications := make([]*pbapidevices.TestNotification, 0, len(r.Notifications))
+		for _, notification := range r.Notifications {
+			notifications = append(notifications, &pbapidevices.TestNotification{
+				Subject: notification.Subject,
+				Text:    notification.Text,
+			})
+		}
+
+		result = append(result, &pbapidevices.TestResult{
+			Message: &pbapidevices.TestMessage{
+				Message:       r.Message.Message,
+				MessageType:   r.Message.MessageType,
+				Component:     r.Message.Component,
+				Attributes:    r.Message.Attributes,
+				ReceivedAt:    receivedAt,
+				SeverityLevel: r.Message.SeverityLevel,
+				GotAt:         gotAt,
+			},
+			Matched:       r.Matched,
+			Value:         r.Value,
+			Passed:        r.Passed,
+			Notifications: notifications,
+		})
+	}
+	return result
+}
@@ -0,0 +1,644 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: apialerts.proto
+
+package pbalerts
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Alert is OPEN, ACKNOWLEDGED, FLAPPING or RESOLVED. ResolvedBy is 0 for an
+// alert that recovered by itself. Problem is whether the rule was raised by
+// the last message, a FLAPPING alert does not follow it.
+type Alert struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ID             int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	RuleType       string                 `protobuf:"bytes,2,opt,name=RuleType,proto3" json:"RuleType,omitempty"`
+	RuleID         int32                  `protobuf:"varint,3,opt,name=RuleID,proto3" json:"RuleID,omitempty"`
+	DeviceID       int32                  `protobuf:"varint,4,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	State          string                 `protobuf:"bytes,5,opt,name=State,proto3" json:"State,omitempty"`
+	Subject        string                 `protobuf:"bytes,6,opt,name=Subject,proto3" json:"Subject,omitempty"`
+	SeverityLevel  string                 `protobuf:"bytes,7,opt,name=SeverityLevel,proto3" json:"SeverityLevel,omitempty"`
+	Message        string                 `protobuf:"bytes,8,opt,name=Message,proto3" json:"Message,omitempty"`
+	Value          string                 `protobuf:"bytes,9,opt,name=Value,proto3" json:"Value,omitempty"`
+	Count          int32                  `protobuf:"varint,10,opt,name=Count,proto3" json:"Count,omitempty"`
+	OpenedAt       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=OpenedAt,proto3" json:"OpenedAt,omitempty"`
+	LastSeenAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=LastSeenAt,proto3" json:"LastSeenAt,omitempty"`
+	AcknowledgedAt *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=AcknowledgedAt,proto3" json:"AcknowledgedAt,omitempty"`
+	AcknowledgedBy int32                  `protobuf:"varint,14,opt,name=AcknowledgedBy,proto3" json:"AcknowledgedBy,omitempty"`
+	ResolvedAt     *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=ResolvedAt,proto3" json:"ResolvedAt,omitempty"`
+	ResolvedBy     int32                  `protobuf:"varint,16,opt,name=ResolvedBy,proto3" json:"ResolvedBy,omitempty"`
+	Problem        bool                   `protobuf:"varint,17,opt,name=Problem,proto3" json:"Problem,omitempty"`
+	FlappingSince  *timestamppb.Timestamp `protobuf:"bytes,18,opt,name=FlappingSince,proto3" json:"FlappingSince,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Alert) Reset() {
+	*x = Alert{}
+	mi := &file_apialerts_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Alert) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Alert) ProtoMessage() {}
+
+func (x *Alert) ProtoReflect() protoreflect.Message {
+	mi := &file_apialerts_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Alert.ProtoReflect.Descriptor instead.
+func (*Alert) Descriptor() ([]byte, []int) {
+	return file_apialerts_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Alert) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *Alert) GetRuleType() string {
+	if x != nil {
+		return x.RuleType
+	}
+	return ""
+}
+
+func (x *Alert) GetRuleID() int32 {
+	if x != nil {
+		return x.RuleID
+	}
+	return 0
+}
+
+func (x *Alert) GetDeviceID() int32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *Alert) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *Alert) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Alert) GetSeverityLevel() string {
+	if x != nil {
+		return x.SeverityLevel
+	}
+	return ""
+}
+
+func (x *Alert) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Alert) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *Alert) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *Alert) GetOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return nil
+}
+
+func (x *Alert) GetLastSeenAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastSeenAt
+	}
+	return nil
+}
+
+func (x *Alert) GetAcknowledgedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AcknowledgedAt
+	}
+	return nil
+}
+
+func (x *Alert) GetAcknowledgedBy() int32 {
+	if x != nil {
+		return x.AcknowledgedBy
+	}
+	return 0
+}
+
+func (x *Alert) GetResolvedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return nil
+}
+
+func (x *Alert) GetResolvedBy() int32 {
+	if x != nil {
+		return x.ResolvedBy
+	}
+	return 0
+}
+
+func (x *Alert) GetProblem() bool {
+	if x != nil {
+		return x.Problem
+	}
+	return false
+}
+
+func (x *Alert) GetFlappingSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FlappingSince
+	}
+	return nil
+}
+
+// ReadReq filters by the fields that are set, a DeviceID of 0 reads the
+// alerts of every device.
+type ReadReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         string                 `protobuf:"bytes,1,opt,name=State,proto3" json:"State,omitempty"`
+	DeviceID      int32                  `protobuf:"varint,2,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=Limit,proto3" json:"Limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadReq) Reset() {
+	*x = ReadReq{}
+	mi := &file_apialerts_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadReq) ProtoMessage() {}
+
+func (x *ReadReq) ProtoReflect() protoreflect.Message {
+	mi := &file_apialerts_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadReq.ProtoReflect.Descriptor instead.
+func (*ReadReq) Descriptor() ([]byte, []int) {
+	return file_apialerts_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReadReq) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ReadReq) GetDeviceID() int32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *ReadReq) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ReadResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alerts        []*Alert               `protobuf:"bytes,1,rep,name=Alerts,proto3" json:"Alerts,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadResp) Reset() {
+	*x = ReadResp{}
+	mi := &file_apialerts_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadResp) ProtoMessage() {}
+
+func (x *ReadResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apialerts_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadResp.ProtoReflect.Descriptor instead.
+func (*ReadResp) Descriptor() ([]byte, []int) {
+	return file_apialerts_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ReadResp) GetAlerts() []*Alert {
+	if x != nil {
+		return x.Alerts
+	}
+	return nil
+}
+
+func (x *ReadResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type AcknowledgeReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	UserID        int32                  `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcknowledgeReq) Reset() {
+	*x = AcknowledgeReq{}
+	mi := &file_apialerts_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcknowledgeReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcknowledgeReq) ProtoMessage() {}
+
+func (x *AcknowledgeReq) ProtoReflect() protoreflect.Message {
+	mi := &file_apialerts_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcknowledgeReq.ProtoReflect.Descriptor instead.
+func (*AcknowledgeReq) Descriptor() ([]byte, []int) {
+	return file_apialerts_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AcknowledgeReq) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *AcknowledgeReq) GetUserID() int32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+type AcknowledgeResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alert         *Alert                 `protobuf:"bytes,1,opt,name=Alert,proto3" json:"Alert,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcknowledgeResp) Reset() {
+	*x = AcknowledgeResp{}
+	mi := &file_apialerts_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcknowledgeResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcknowledgeResp) ProtoMessage() {}
+
+func (x *AcknowledgeResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apialerts_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcknowledgeResp.ProtoReflect.Descriptor instead.
+func (*AcknowledgeResp) Descriptor() ([]byte, []int) {
+	return file_apialerts_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AcknowledgeResp) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+func (x *AcknowledgeResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ResolveReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	UserID        int32                  `protobuf:"varint,2,opt,name=UserID,proto3" json:"UserID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveReq) Reset() {
+	*x = ResolveReq{}
+	mi := &file_apialerts_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveReq) ProtoMessage() {}
+
+func (x *ResolveReq) ProtoReflect() protoreflect.Message {
+	mi := &file_apialerts_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveReq.ProtoReflect.Descriptor instead.
+func (*ResolveReq) Descriptor() ([]byte, []int) {
+	return file_apialerts_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResolveReq) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *ResolveReq) GetUserID() int32 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+type ResolveResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Alert         *Alert                 `protobuf:"bytes,1,opt,name=Alert,proto3" json:"Alert,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveResp) Reset() {
+	*x = ResolveResp{}
+	mi := &file_apialerts_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveResp) ProtoMessage() {}
+
+func (x *ResolveResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apialerts_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveResp.ProtoReflect.Descriptor instead.
+func (*ResolveResp) Descriptor() ([]byte, []int) {
+	return file_apialerts_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ResolveResp) GetAlert() *Alert {
+	if x != nil {
+		return x.Alert
+	}
+	return nil
+}
+
+func (x *ResolveResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_apialerts_proto protoreflect.FileDescriptor
+
+const file_apialerts_proto_rawDesc = "" +
+	"\n" +
+	"\x0fapialerts.proto\x12\bpbalerts\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9b\x05\n" +
+	"\x05Alert\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\x12\x1a\n" +
+	"\bRuleType\x18\x02 \x01(\tR\bRuleType\x12\x16\n" +
+	"\x06RuleID\x18\x03 \x01(\x05R\x06RuleID\x12\x1a\n" +
+	"\bDeviceID\x18\x04 \x01(\x05R\bDeviceID\x12\x14\n" +
+	"\x05State\x18\x05 \x01(\tR\x05State\x12\x18\n" +
+	"\aSubject\x18\x06 \x01(\tR\aSubject\x12$\n" +
+	"\rSeverityLevel\x18\a \x01(\tR\rSeverityLevel\x12\x18\n" +
+	"\aMessage\x18\b \x01(\tR\aMessage\x12\x14\n" +
+	"\x05Value\x18\t \x01(\tR\x05Value\x12\x14\n" +
+	"\x05Count\x18\n" +
+	" \x01(\x05R\x05Count\x126\n" +
+	"\bOpenedAt\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\bOpenedAt\x12:\n" +
+	"\n" +
+	"LastSeenAt\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"LastSeenAt\x12B\n" +
+	"\x0eAcknowledgedAt\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\x0eAcknowledgedAt\x12&\n" +
+	"\x0eAcknowledgedBy\x18\x0e \x01(\x05R\x0eAcknowledgedBy\x12:\n" +
+	"\n" +
+	"ResolvedAt\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"ResolvedAt\x12\x1e\n" +
+	"\n" +
+	"ResolvedBy\x18\x10 \x01(\x05R\n" +
+	"ResolvedBy\x12\x18\n" +
+	"\aProblem\x18\x11 \x01(\bR\aProblem\x12@\n" +
+	"\rFlappingSince\x18\x12 \x01(\v2\x1a.google.protobuf.TimestampR\rFlappingSince\"Q\n" +
+	"\aReadReq\x12\x14\n" +
+	"\x05State\x18\x01 \x01(\tR\x05State\x12\x1a\n" +
+	"\bDeviceID\x18\x02 \x01(\x05R\bDeviceID\x12\x14\n" +
+	"\x05Limit\x18\x03 \x01(\x05R\x05Limit\"I\n" +
+	"\bReadResp\x12'\n" +
+	"\x06Alerts\x18\x01 \x03(\v2\x0f.pbalerts.AlertR\x06Alerts\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"8\n" +
+	"\x0eAcknowledgeReq\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\x05R\x06UserID\"N\n" +
+	"\x0fAcknowledgeResp\x12%\n" +
+	"\x05Alert\x18\x01 \x01(\v2\x0f.pbalerts.AlertR\x05Alert\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"4\n" +
+	"\n" +
+	"ResolveReq\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\x12\x16\n" +
+	"\x06UserID\x18\x02 \x01(\x05R\x06UserID\"J\n" +
+	"\vResolveResp\x12%\n" +
+	"\x05Alert\x18\x01 \x01(\v2\x0f.pbalerts.AlertR\x05Alert\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05ErrorB\fZ\n" +
+	".;pbalertsb\x06proto3"
+
+var (
+	file_apialerts_proto_rawDescOnce sync.Once
+	file_apialerts_proto_rawDescData []byte
+)
+
+func file_apialerts_proto_rawDescGZIP() []byte {
+	file_apialerts_proto_rawDescOnce.Do(func() {
+		file_apialerts_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_apialerts_proto_rawDesc), len(file_apialerts_proto_rawDesc)))
+	})
+	return file_apialerts_proto_rawDescData
+}
+
+var file_apialerts_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_apialerts_proto_goTypes = []any{
+	(*Alert)(nil),                 // 0: pbalerts.Alert
+	(*ReadReq)(nil),               // 1: pbalerts.ReadReq
+	(*ReadResp)(nil),              // 2: pbalerts.ReadResp
+	(*AcknowledgeReq)(nil),        // 3: pbalerts.AcknowledgeReq
+	(*AcknowledgeResp)(nil),       // 4: pbalerts.AcknowledgeResp
+	(*ResolveReq)(nil),            // 5: pbalerts.ResolveReq
+	(*ResolveResp)(nil),           // 6: pbalerts.ResolveResp
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_apialerts_proto_depIdxs = []int32{
+	7, // 0: pbalerts.Alert.OpenedAt:type_name -> google.protobuf.Timestamp
+	7, // 1: pbalerts.Alert.LastSeenAt:type_name -> google.protobuf.Timestamp
+	7, // 2: pbalerts.Alert.AcknowledgedAt:type_name -> google.protobuf.Timestamp
+	7, // 3: pbalerts.Alert.ResolvedAt:type_name -> google.protobuf.Timestamp
+	7, // 4: pbalerts.Alert.FlappingSince:type_name -> google.protobuf.Timestamp
+	0, // 5: pbalerts.ReadResp.Alerts:type_name -> pbalerts.Alert
+	0, // 6: pbalerts.AcknowledgeResp.Alert:type_name -> pbalerts.Alert
+	0, // 7: pbalerts.ResolveResp.Alert:type_name -> pbalerts.Alert
+	8, // [8:8] is the sub-list for method output_type
+	8, // [8:8] is the sub-list for method input_type
+	8, // [8:8] is the sub-list for extension type_name
+	8, // [8:8] is the sub-list for extension extendee
+	0, // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_apialerts_proto_init() }
+func file_apialerts_proto_init() {
+	if File_apialerts_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_apialerts_proto_rawDesc), len(file_apialerts_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_apialerts_proto_goTypes,
+		DependencyIndexes: file_apialerts_proto_depIdxs,
+		MessageInfos:      file_apialerts_proto_msgTypes,
+	}.Build()
+	File_apialerts_proto = out.File
+	file_apialerts_proto_goTypes = nil
+	file_apialerts_proto_depIdxs = nil
+}
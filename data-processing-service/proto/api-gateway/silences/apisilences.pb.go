@@ -0,0 +1,541 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.31.0
+// source: apisilences.proto
+
+package pbsilences
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Silence mutes the notifications of the messages it matches from StartsAt to
+// EndsAt. The matchers that are set must all match, a DeviceID of 0 and empty
+// strings match anything. With a cron Recurrence it is only active for
+// DurationSec seconds after each time the schedule fires.
+type Silence struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	DeviceID      int32                  `protobuf:"varint,2,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,3,opt,name=DeviceType,proto3" json:"DeviceType,omitempty"`
+	RuleName      string                 `protobuf:"bytes,4,opt,name=RuleName,proto3" json:"RuleName,omitempty"`
+	Component     string                 `protobuf:"bytes,5,opt,name=Component,proto3" json:"Component,omitempty"`
+	StartsAt      *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=StartsAt,proto3" json:"StartsAt,omitempty"`
+	EndsAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=EndsAt,proto3" json:"EndsAt,omitempty"`
+	Recurrence    string                 `protobuf:"bytes,8,opt,name=Recurrence,proto3" json:"Recurrence,omitempty"`
+	DurationSec   int32                  `protobuf:"varint,9,opt,name=DurationSec,proto3" json:"DurationSec,omitempty"`
+	CreatedBy     int32                  `protobuf:"varint,10,opt,name=CreatedBy,proto3" json:"CreatedBy,omitempty"`
+	Comment       string                 `protobuf:"bytes,11,opt,name=Comment,proto3" json:"Comment,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Silence) Reset() {
+	*x = Silence{}
+	mi := &file_apisilences_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Silence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Silence) ProtoMessage() {}
+
+func (x *Silence) ProtoReflect() protoreflect.Message {
+	mi := &file_apisilences_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Silence.ProtoReflect.Descriptor instead.
+func (*Silence) Descriptor() ([]byte, []int) {
+	return file_apisilences_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Silence) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *Silence) GetDeviceID() int32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *Silence) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *Silence) GetRuleName() string {
+	if x != nil {
+		return x.RuleName
+	}
+	return ""
+}
+
+func (x *Silence) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *Silence) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *Silence) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *Silence) GetRecurrence() string {
+	if x != nil {
+		return x.Recurrence
+	}
+	return ""
+}
+
+func (x *Silence) GetDurationSec() int32 {
+	if x != nil {
+		return x.DurationSec
+	}
+	return 0
+}
+
+func (x *Silence) GetCreatedBy() int32 {
+	if x != nil {
+		return x.CreatedBy
+	}
+	return 0
+}
+
+func (x *Silence) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Silence) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Silence       *Silence               `protobuf:"bytes,1,opt,name=Silence,proto3" json:"Silence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateReq) Reset() {
+	*x = CreateReq{}
+	mi := &file_apisilences_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateReq) ProtoMessage() {}
+
+func (x *CreateReq) ProtoReflect() protoreflect.Message {
+	mi := &file_apisilences_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateReq.ProtoReflect.Descriptor instead.
+func (*CreateReq) Descriptor() ([]byte, []int) {
+	return file_apisilences_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateReq) GetSilence() *Silence {
+	if x != nil {
+		return x.Silence
+	}
+	return nil
+}
+
+type CreateResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Created       *Silence               `protobuf:"bytes,1,opt,name=Created,proto3" json:"Created,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateResp) Reset() {
+	*x = CreateResp{}
+	mi := &file_apisilences_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateResp) ProtoMessage() {}
+
+func (x *CreateResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apisilences_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateResp.ProtoReflect.Descriptor instead.
+func (*CreateResp) Descriptor() ([]byte, []int) {
+	return file_apisilences_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateResp) GetCreated() *Silence {
+	if x != nil {
+		return x.Created
+	}
+	return nil
+}
+
+func (x *CreateResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type ReadReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WithExpired   bool                   `protobuf:"varint,1,opt,name=WithExpired,proto3" json:"WithExpired,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadReq) Reset() {
+	*x = ReadReq{}
+	mi := &file_apisilences_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadReq) ProtoMessage() {}
+
+func (x *ReadReq) ProtoReflect() protoreflect.Message {
+	mi := &file_apisilences_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadReq.ProtoReflect.Descriptor instead.
+func (*ReadReq) Descriptor() ([]byte, []int) {
+	return file_apisilences_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ReadReq) GetWithExpired() bool {
+	if x != nil {
+		return x.WithExpired
+	}
+	return false
+}
+
+type ReadResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Silences      []*Silence             `protobuf:"bytes,1,rep,name=Silences,proto3" json:"Silences,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadResp) Reset() {
+	*x = ReadResp{}
+	mi := &file_apisilences_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadResp) ProtoMessage() {}
+
+func (x *ReadResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apisilences_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadResp.ProtoReflect.Descriptor instead.
+func (*ReadResp) Descriptor() ([]byte, []int) {
+	return file_apisilences_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ReadResp) GetSilences() []*Silence {
+	if x != nil {
+		return x.Silences
+	}
+	return nil
+}
+
+func (x *ReadResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type DeleteReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteReq) Reset() {
+	*x = DeleteReq{}
+	mi := &file_apisilences_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReq) ProtoMessage() {}
+
+func (x *DeleteReq) ProtoReflect() protoreflect.Message {
+	mi := &file_apisilences_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReq.ProtoReflect.Descriptor instead.
+func (*DeleteReq) Descriptor() ([]byte, []int) {
+	return file_apisilences_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteReq) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+type DeleteResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Error         string                 `protobuf:"bytes,1,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResp) Reset() {
+	*x = DeleteResp{}
+	mi := &file_apisilences_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResp) ProtoMessage() {}
+
+func (x *DeleteResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apisilences_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResp.ProtoReflect.Descriptor instead.
+func (*DeleteResp) Descriptor() ([]byte, []int) {
+	return file_apisilences_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DeleteResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+var File_apisilences_proto protoreflect.FileDescriptor
+
+const file_apisilences_proto_rawDesc = "" +
+	"\n" +
+	"\x11apisilences.proto\x12\n" +
+	"pbsilences\x1a\x1fgoogle/protobuf/timestamp.proto\"\xaf\x03\n" +
+	"\aSilence\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\x12\x1a\n" +
+	"\bDeviceID\x18\x02 \x01(\x05R\bDeviceID\x12\x1e\n" +
+	"\n" +
+	"DeviceType\x18\x03 \x01(\tR\n" +
+	"DeviceType\x12\x1a\n" +
+	"\bRuleName\x18\x04 \x01(\tR\bRuleName\x12\x1c\n" +
+	"\tComponent\x18\x05 \x01(\tR\tComponent\x126\n" +
+	"\bStartsAt\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bStartsAt\x122\n" +
+	"\x06EndsAt\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x06EndsAt\x12\x1e\n" +
+	"\n" +
+	"Recurrence\x18\b \x01(\tR\n" +
+	"Recurrence\x12 \n" +
+	"\vDurationSec\x18\t \x01(\x05R\vDurationSec\x12\x1c\n" +
+	"\tCreatedBy\x18\n" +
+	" \x01(\x05R\tCreatedBy\x12\x18\n" +
+	"\aComment\x18\v \x01(\tR\aComment\x128\n" +
+	"\tCreatedAt\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tCreatedAt\":\n" +
+	"\tCreateReq\x12-\n" +
+	"\aSilence\x18\x01 \x01(\v2\x13.pbsilences.SilenceR\aSilence\"Q\n" +
+	"\n" +
+	"CreateResp\x12-\n" +
+	"\aCreated\x18\x01 \x01(\v2\x13.pbsilences.SilenceR\aCreated\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"+\n" +
+	"\aReadReq\x12 \n" +
+	"\vWithExpired\x18\x01 \x01(\bR\vWithExpired\"Q\n" +
+	"\bReadResp\x12/\n" +
+	"\bSilences\x18\x01 \x03(\v2\x13.pbsilences.SilenceR\bSilences\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"\x1b\n" +
+	"\tDeleteReq\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\"\"\n" +
+	"\n" +
+	"DeleteResp\x12\x14\n" +
+	"\x05Error\x18\x01 \x01(\tR\x05ErrorB\x0eZ\f.;pbsilencesb\x06proto3"
+
+var (
+	file_apisilences_proto_rawDescOnce sync.Once
+	file_apisilences_proto_rawDescData []byte
+)
+
+func file_apisilences_proto_rawDescGZIP() []byte {
+	file_apisilences_proto_rawDescOnce.Do(func() {
+		file_apisilences_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_apisilences_proto_rawDesc), len(file_apisilences_proto_rawDesc)))
+	})
+	return file_apisilences_proto_rawDescData
+}
+
+var file_apisilences_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_apisilences_proto_goTypes = []any{
+	(*Silence)(nil),               // 0: pbsilences.Silence
+	(*CreateReq)(nil),             // 1: pbsilences.CreateReq
+	(*CreateResp)(nil),            // 2: pbsilences.CreateResp
+	(*ReadReq)(nil),               // 3: pbsilences.ReadReq
+	(*ReadResp)(nil),              // 4: pbsilences.ReadResp
+	(*DeleteReq)(nil),             // 5: pbsilences.DeleteReq
+	(*DeleteResp)(nil),            // 6: pbsilences.DeleteResp
+	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
+}
+var file_apisilences_proto_depIdxs = []int32{
+	7, // 0: pbsilences.Silence.StartsAt:type_name -> google.protobuf.Timestamp
+	7, // 1: pbsilences.Silence.EndsAt:type_name -> google.protobuf.Timestamp
+	7, // 2: pbsilences.Silence.CreatedAt:type_name -> google.protobuf.Timestamp
+	0, // 3: pbsilences.CreateReq.Silence:type_name -> pbsilences.Silence
+	0, // 4: pbsilences.CreateResp.Created:type_name -> pbsilences.Silence
+	0, // 5: pbsilences.ReadResp.Silences:type_name -> pbsilences.Silence
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_apisilences_proto_init() }
+func file_apisilences_proto_init() {
+	if File_apisilences_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_apisilences_proto_rawDesc), len(file_apisilences_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_apisilences_proto_goTypes,
+		DependencyIndexes: file_apisilences_proto_depIdxs,
+		MessageInfos:      file_apisilences_proto_msgTypes,
+	}.Build()
+	File_apisilences_proto = out.File
+	file_apisilences_proto_goTypes = nil
+	file_apisilences_proto_depIdxs = nil
+}
@@ -246,6 +246,7 @@ type ReadResp struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Tags          []*Tag                 `protobuf:"bytes,1,rep,name=Tags,proto3" json:"Tags,omitempty"`
 	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=Version,proto3" json:"Version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -294,6 +295,13 @@ func (x *ReadResp) GetError() string {
 	return ""
 }
 
+func (x *ReadResp) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
 type UpdateReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Tag           *Tag                   `protobuf:"bytes,1,opt,name=Tag,proto3" json:"Tag,omitempty"`
@@ -470,6 +478,458 @@ func (x *DeleteResp) GetError() string {
 	return ""
 }
 
+// TestMessage is a sample message or, in a TestResult, the message with the
+// severity it would be stored with.
+type TestMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	MessageType   string                 `protobuf:"bytes,2,opt,name=MessageType,proto3" json:"MessageType,omitempty"`
+	Component     string                 `protobuf:"bytes,3,opt,name=Component,proto3" json:"Component,omitempty"`
+	Attributes    map[string]string      `protobuf:"bytes,4,rep,name=Attributes,proto3" json:"Attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ReceivedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=ReceivedAt,proto3" json:"ReceivedAt,omitempty"`
+	SeverityLevel string                 `protobuf:"bytes,6,opt,name=SeverityLevel,proto3" json:"SeverityLevel,omitempty"`
+	GotAt         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=GotAt,proto3" json:"GotAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestMessage) Reset() {
+	*x = TestMessage{}
+	mi := &file_apitags_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestMessage) ProtoMessage() {}
+
+func (x *TestMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_apitags_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestMessage.ProtoReflect.Descriptor instead.
+func (*TestMessage) Descriptor() ([]byte, []int) {
+	return file_apitags_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TestMessage) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *TestMessage) GetMessageType() string {
+	if x != nil {
+		return x.MessageType
+	}
+	return ""
+}
+
+func (x *TestMessage) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *TestMessage) GetAttributes() map[string]string {
+	if x != nil {
+		return x.Attributes
+	}
+	return nil
+}
+
+func (x *TestMessage) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *TestMessage) GetSeverityLevel() string {
+	if x != nil {
+		return x.SeverityLevel
+	}
+	return ""
+}
+
+func (x *TestMessage) GetGotAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.GotAt
+	}
+	return nil
+}
+
+// TestReq runs Tag against Messages or, without them, against the stored
+// messages of its device from StartTime to EndTime. Tag.ID of a stored tag
+// tests a change of it.
+type TestReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           *Tag                   `protobuf:"bytes,1,opt,name=Tag,proto3" json:"Tag,omitempty"`
+	Messages      []*TestMessage         `protobuf:"bytes,2,rep,name=Messages,proto3" json:"Messages,omitempty"`
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=StartTime,proto3" json:"StartTime,omitempty"`
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestReq) Reset() {
+	*x = TestReq{}
+	mi := &file_apitags_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestReq) ProtoMessage() {}
+
+func (x *TestReq) ProtoReflect() protoreflect.Message {
+	mi := &file_apitags_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestReq.ProtoReflect.Descriptor instead.
+func (*TestReq) Descriptor() ([]byte, []int) {
+	return file_apitags_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *TestReq) GetTag() *Tag {
+	if x != nil {
+		return x.Tag
+	}
+	return nil
+}
+
+func (x *TestReq) GetMessages() []*TestMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *TestReq) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *TestReq) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+type TestNotification struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subject       string                 `protobuf:"bytes,1,opt,name=Subject,proto3" json:"Subject,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=Text,proto3" json:"Text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestNotification) Reset() {
+	*x = TestNotification{}
+	mi := &file_apitags_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestNotification) ProtoMessage() {}
+
+func (x *TestNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_apitags_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestNotification.ProtoReflect.Descriptor instead.
+func (*TestNotification) Descriptor() ([]byte, []int) {
+	return file_apitags_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *TestNotification) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *TestNotification) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type TestResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       *TestMessage           `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	Matched       bool                   `protobuf:"varint,2,opt,name=Matched,proto3" json:"Matched,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=Value,proto3" json:"Value,omitempty"`
+	Passed        bool                   `protobuf:"varint,4,opt,name=Passed,proto3" json:"Passed,omitempty"`
+	Notifications []*TestNotification    `protobuf:"bytes,5,rep,name=Notifications,proto3" json:"Notifications,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestResult) Reset() {
+	*x = TestResult{}
+	mi := &file_apitags_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestResult) ProtoMessage() {}
+
+func (x *TestResult) ProtoReflect() protoreflect.Message {
+	mi := &file_apitags_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestResult.ProtoReflect.Descriptor instead.
+func (*TestResult) Descriptor() ([]byte, []int) {
+	return file_apitags_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *TestResult) GetMessage() *TestMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *TestResult) GetMatched() bool {
+	if x != nil {
+		return x.Matched
+	}
+	return false
+}
+
+func (x *TestResult) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *TestResult) GetPassed() bool {
+	if x != nil {
+		return x.Passed
+	}
+	return false
+}
+
+func (x *TestResult) GetNotifications() []*TestNotification {
+	if x != nil {
+		return x.Notifications
+	}
+	return nil
+}
+
+type TestResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*TestResult          `protobuf:"bytes,1,rep,name=Results,proto3" json:"Results,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TestResp) Reset() {
+	*x = TestResp{}
+	mi := &file_apitags_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TestResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TestResp) ProtoMessage() {}
+
+func (x *TestResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apitags_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TestResp.ProtoReflect.Descriptor instead.
+func (*TestResp) Descriptor() ([]byte, []int) {
+	return file_apitags_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *TestResp) GetResults() []*TestResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *TestResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// TagsChanged is broadcast to every data-processing replica after a change of
+// the tag set.
+type TagsChanged struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       int64                  `protobuf:"varint,1,opt,name=Version,proto3" json:"Version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TagsChanged) Reset() {
+	*x = TagsChanged{}
+	mi := &file_apitags_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TagsChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TagsChanged) ProtoMessage() {}
+
+func (x *TagsChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_apitags_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TagsChanged.ProtoReflect.Descriptor instead.
+func (*TagsChanged) Descriptor() ([]byte, []int) {
+	return file_apitags_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TagsChanged) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// VersionResp is the version of the tag set one replica has loaded.
+type VersionResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      string                 `protobuf:"bytes,1,opt,name=Instance,proto3" json:"Instance,omitempty"`
+	Version       int64                  `protobuf:"varint,2,opt,name=Version,proto3" json:"Version,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VersionResp) Reset() {
+	*x = VersionResp{}
+	mi := &file_apitags_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VersionResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VersionResp) ProtoMessage() {}
+
+func (x *VersionResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apitags_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VersionResp.ProtoReflect.Descriptor instead.
+func (*VersionResp) Descriptor() ([]byte, []int) {
+	return file_apitags_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *VersionResp) GetInstance() string {
+	if x != nil {
+		return x.Instance
+	}
+	return ""
+}
+
+func (x *VersionResp) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *VersionResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 var File_apitags_proto protoreflect.FileDescriptor
 
 const file_apitags_proto_rawDesc = "" +
@@ -495,10 +955,11 @@ const file_apitags_proto_rawDesc = "" +
 	"\n" +
 	"CreateResp\x12%\n" +
 	"\aCreated\x18\x01 \x01(\v2\v.pbtags.TagR\aCreated\x12\x14\n" +
-	"\x05Error\x18\x02 \x01(\tR\x05Error\"A\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"[\n" +
 	"\bReadResp\x12\x1f\n" +
 	"\x04Tags\x18\x01 \x03(\v2\v.pbtags.TagR\x04Tags\x12\x14\n" +
-	"\x05Error\x18\x02 \x01(\tR\x05Error\"*\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\x12\x18\n" +
+	"\aVersion\x18\x03 \x01(\x03R\aVersion\"*\n" +
 	"\tUpdateReq\x12\x1d\n" +
 	"\x03Tag\x18\x01 \x01(\v2\v.pbtags.TagR\x03Tag\"\"\n" +
 	"\n" +
@@ -508,7 +969,46 @@ const file_apitags_proto_rawDesc = "" +
 	"\x02ID\x18\x01 \x01(\x05R\x02ID\"\"\n" +
 	"\n" +
 	"DeleteResp\x12\x14\n" +
-	"\x05Error\x18\x01 \x01(\tR\x05ErrorB\n" +
+	"\x05Error\x18\x01 \x01(\tR\x05Error\"\xff\x02\n" +
+	"\vTestMessage\x12\x18\n" +
+	"\aMessage\x18\x01 \x01(\tR\aMessage\x12 \n" +
+	"\vMessageType\x18\x02 \x01(\tR\vMessageType\x12\x1c\n" +
+	"\tComponent\x18\x03 \x01(\tR\tComponent\x12C\n" +
+	"\n" +
+	"Attributes\x18\x04 \x03(\v2#.pbtags.TestMessage.AttributesEntryR\n" +
+	"Attributes\x12:\n" +
+	"\n" +
+	"ReceivedAt\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"ReceivedAt\x12$\n" +
+	"\rSeverityLevel\x18\x06 \x01(\tR\rSeverityLevel\x120\n" +
+	"\x05GotAt\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x05GotAt\x1a=\n" +
+	"\x0fAttributesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc9\x01\n" +
+	"\aTestReq\x12\x1d\n" +
+	"\x03Tag\x18\x01 \x01(\v2\v.pbtags.TagR\x03Tag\x12/\n" +
+	"\bMessages\x18\x02 \x03(\v2\x13.pbtags.TestMessageR\bMessages\x128\n" +
+	"\tStartTime\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tStartTime\x124\n" +
+	"\aEndTime\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\aEndTime\"@\n" +
+	"\x10TestNotification\x12\x18\n" +
+	"\aSubject\x18\x01 \x01(\tR\aSubject\x12\x12\n" +
+	"\x04Text\x18\x02 \x01(\tR\x04Text\"\xc3\x01\n" +
+	"\n" +
+	"TestResult\x12-\n" +
+	"\aMessage\x18\x01 \x01(\v2\x13.pbtags.TestMessageR\aMessage\x12\x18\n" +
+	"\aMatched\x18\x02 \x01(\bR\aMatched\x12\x14\n" +
+	"\x05Value\x18\x03 \x01(\tR\x05Value\x12\x16\n" +
+	"\x06Passed\x18\x04 \x01(\bR\x06Passed\x12>\n" +
+	"\rNotifications\x18\x05 \x03(\v2\x18.pbtags.TestNotificationR\rNotifications\"N\n" +
+	"\bTestResp\x12,\n" +
+	"\aResults\x18\x01 \x03(\v2\x12.pbtags.TestResultR\aResults\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"'\n" +
+	"\vTagsChanged\x12\x18\n" +
+	"\aVersion\x18\x01 \x01(\x03R\aVersion\"Y\n" +
+	"\vVersionResp\x12\x1a\n" +
+	"\bInstance\x18\x01 \x01(\tR\bInstance\x12\x18\n" +
+	"\aVersion\x18\x02 \x01(\x03R\aVersion\x12\x14\n" +
+	"\x05Error\x18\x03 \x01(\tR\x05ErrorB\n" +
 	"Z\b.;pbtagsb\x06proto3"
 
 var (
@@ -523,7 +1023,7 @@ func file_apitags_proto_rawDescGZIP() []byte {
 	return file_apitags_proto_rawDescData
 }
 
-var file_apitags_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_apitags_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_apitags_proto_goTypes = []any{
 	(*Tag)(nil),                   // 0: pbtags.Tag
 	(*CreateReq)(nil),             // 1: pbtags.CreateReq
@@ -533,20 +1033,38 @@ var file_apitags_proto_goTypes = []any{
 	(*UpdateResp)(nil),            // 5: pbtags.UpdateResp
 	(*DeleteReq)(nil),             // 6: pbtags.DeleteReq
 	(*DeleteResp)(nil),            // 7: pbtags.DeleteResp
-	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*TestMessage)(nil),           // 8: pbtags.TestMessage
+	(*TestReq)(nil),               // 9: pbtags.TestReq
+	(*TestNotification)(nil),      // 10: pbtags.TestNotification
+	(*TestResult)(nil),            // 11: pbtags.TestResult
+	(*TestResp)(nil),              // 12: pbtags.TestResp
+	(*TagsChanged)(nil),           // 13: pbtags.TagsChanged
+	(*VersionResp)(nil),           // 14: pbtags.VersionResp
+	nil,                           // 15: pbtags.TestMessage.AttributesEntry
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
 }
 var file_apitags_proto_depIdxs = []int32{
-	8, // 0: pbtags.Tag.CreatedAt:type_name -> google.protobuf.Timestamp
-	8, // 1: pbtags.Tag.UpdatedAt:type_name -> google.protobuf.Timestamp
-	0, // 2: pbtags.CreateReq.Tag:type_name -> pbtags.Tag
-	0, // 3: pbtags.CreateResp.Created:type_name -> pbtags.Tag
-	0, // 4: pbtags.ReadResp.Tags:type_name -> pbtags.Tag
-	0, // 5: pbtags.UpdateReq.Tag:type_name -> pbtags.Tag
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	16, // 0: pbtags.Tag.CreatedAt:type_name -> google.protobuf.Timestamp
+	16, // 1: pbtags.Tag.UpdatedAt:type_name -> google.protobuf.Timestamp
+	0,  // 2: pbtags.CreateReq.Tag:type_name -> pbtags.Tag
+	0,  // 3: pbtags.CreateResp.Created:type_name -> pbtags.Tag
+	0,  // 4: pbtags.ReadResp.Tags:type_name -> pbtags.Tag
+	0,  // 5: pbtags.UpdateReq.Tag:type_name -> pbtags.Tag
+	15, // 6: pbtags.TestMessage.Attributes:type_name -> pbtags.TestMessage.AttributesEntry
+	16, // 7: pbtags.TestMessage.ReceivedAt:type_name -> google.protobuf.Timestamp
+	16, // 8: pbtags.TestMessage.GotAt:type_name -> google.protobuf.Timestamp
+	0,  // 9: pbtags.TestReq.Tag:type_name -> pbtags.Tag
+	8,  // 10: pbtags.TestReq.Messages:type_name -> pbtags.TestMessage
+	16, // 11: pbtags.TestReq.StartTime:type_name -> google.protobuf.Timestamp
+	16, // 12: pbtags.TestReq.EndTime:type_name -> google.protobuf.Timestamp
+	8,  // 13: pbtags.TestResult.Message:type_name -> pbtags.TestMessage
+	10, // 14: pbtags.TestResult.Notifications:type_name -> pbtags.TestNotification
+	11, // 15: pbtags.TestResp.Results:type_name -> pbtags.TestResult
+	16, // [16:16] is the sub-list for method output_type
+	16, // [16:16] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_apitags_proto_init() }
@@ -560,7 +1078,7 @@ func file_apitags_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_apitags_proto_rawDesc), len(file_apitags_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -55,6 +55,7 @@ func Run(ctx context.Context, cfg *config.Config, stop context.CancelFunc) {
 
 	listeners := natslisteners.NewListener(natslisteners.Config{
 		NatsConn:       nats.NatsConn,
+		Js:             nats.Js,
 		DevicesService: devicesService,
 		Log:            log,
 	})
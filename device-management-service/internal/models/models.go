@@ -13,6 +13,7 @@ type Device struct {
 	DeviceType  string           `db:"device_type"`
 	Address     string           `db:"address"`
 	Responsible SqlJsonbIntArray `db:"responsible"`
+	APIKeyHash  *string          `db:"api_key_hash"`
 	CreatedAt   *time.Time       `db:"created_at"`
 	UpdatedAt   *time.Time       `db:"updated_at"`
 }
@@ -66,20 +66,21 @@ func (r devicesRepo) Rollback() error {
 }
 
 const devicesRepoQueryInsertPerson = `
-insert into devices (device_type, "name", address, responsible, created_at)
+insert into devices (device_type, "name", address, responsible, api_key_hash, api_key_rotated_at, created_at)
 values
-(:device_type, :name, :address, :responsible, :created_at)
+(:device_type, :name, :address, :responsible, :api_key_hash, :created_at, :created_at)
 returning id, device_type, "name", address, responsible, created_at, updated_at;
 `
 
 func (r devicesRepo) Create(opts models.Device) (models.Device, error) {
 	rows, err := r.tx.NamedQuery(devicesRepoQueryInsertPerson,
 		map[string]any{
-			"name":        opts.Name,
-			"device_type": opts.DeviceType,
-			"address":     opts.Address,
-			"responsible": opts.Responsible,
-			"created_at":  time.Now(),
+			"name":         opts.Name,
+			"device_type":  opts.DeviceType,
+			"address":      opts.Address,
+			"responsible":  opts.Responsible,
+			"api_key_hash": opts.APIKeyHash,
+			"created_at":   time.Now(),
 		},
 	)
 	if err != nil {
@@ -116,6 +117,24 @@ select id, device_type, "name", address, responsible, created_at, updated_at fro
 where deleted_at is null;
 `
 
+const devicesRepoQueryReadByID = `
+select id, device_type, "name", address, responsible, created_at, updated_at from devices
+where id = $1 and deleted_at is null;
+`
+
+func (r devicesRepo) ReadByID(ctx context.Context, id int32) (models.Device, error) {
+	var device models.Device
+	err := r.tx.GetContext(ctx, &device, devicesRepoQueryReadByID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Device{}, repo.ErrDeviceNotFound
+	}
+	if err != nil {
+		return models.Device{}, fmt.Errorf("r.tx.GetContext: %w", err)
+	}
+
+	return device, nil
+}
+
 func (r devicesRepo) Read(ctx context.Context) (repo.ReadDevicesResult, error) {
 	var result repo.ReadDevicesResult
 	err := r.tx.SelectContext(ctx, &result.Devices, devicesRepoQueryRead)
@@ -196,3 +215,49 @@ func (r devicesRepo) GetResponsible(ctx context.Context) ([]repo.GetResponsibleR
 
 	return responsibles, nil
 }
+
+const devicesRepoQueryRotateKey = `
+update devices
+set api_key_hash = :api_key_hash,
+	api_key_rotated_at = :rotated_at
+where id = :id and deleted_at is null;
+`
+
+func (r devicesRepo) RotateKey(ctx context.Context, id int32, keyHash string) error {
+	res, err := r.tx.NamedExecContext(ctx, devicesRepoQueryRotateKey,
+		map[string]any{
+			"id":           id,
+			"api_key_hash": keyHash,
+			"rotated_at":   time.Now(),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("s.tx.NamedExecContext: %w", err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("res.RowsAffected: %w", err)
+	}
+	if affected == 0 {
+		return repo.ErrDeviceNotFound
+	}
+
+	return nil
+}
+
+const devicesRepoQueryReadKeys = `
+select id, api_key_hash from devices
+where deleted_at is null and api_key_hash is not null;
+`
+
+func (r devicesRepo) ReadKeys(ctx context.Context) ([]repo.KeyHashResult, error) {
+	var keys []repo.KeyHashResult
+
+	err := r.tx.SelectContext(ctx, &keys, devicesRepoQueryReadKeys)
+	if err != nil {
+		return nil, fmt.Errorf("r.tx.SelectContext: %w", err)
+	}
+
+	return keys, nil
+}
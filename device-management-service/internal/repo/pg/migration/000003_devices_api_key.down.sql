@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS devices_api_key_hash_unique;
+
+ALTER TABLE devices
+	DROP COLUMN IF EXISTS api_key_rotated_at,
+	DROP COLUMN IF EXISTS api_key_hash;
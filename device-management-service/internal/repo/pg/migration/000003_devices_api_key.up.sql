@@ -0,0 +1,7 @@
+ALTER TABLE devices
+	ADD COLUMN IF NOT EXISTS api_key_hash varchar NULL,
+	ADD COLUMN IF NOT EXISTS api_key_rotated_at timestamp without time zone NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS devices_api_key_hash_unique
+	ON devices (api_key_hash)
+	WHERE api_key_hash IS NOT NULL;
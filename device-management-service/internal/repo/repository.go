@@ -12,7 +12,10 @@ type Devices interface {
 
 	Create(opts models.Device) (models.Device, error)
 	Read(ctx context.Context) (ReadDevicesResult, error)
+	ReadByID(ctx context.Context, id int32) (models.Device, error)
 	Update(ctx context.Context, opts UpdateDeviceOpts) error
 	Delete(ctx context.Context, id int32) error
 	GetResponsible(ctx context.Context) ([]GetResponsibleResult, error)
+	RotateKey(ctx context.Context, id int32, keyHash string) error
+	ReadKeys(ctx context.Context) ([]KeyHashResult, error)
 }
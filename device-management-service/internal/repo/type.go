@@ -14,6 +14,11 @@ type ReadDevicesResult struct {
 	Devices []models.Device
 }
 
+type KeyHashResult struct {
+	ID         int32  `db:"id"`
+	APIKeyHash string `db:"api_key_hash"`
+}
+
 type GetResponsibleResult struct {
 	ID           int32                   `db:"id"`
 	Responsibles models.SqlJsonbIntArray `db:"responsible"`
@@ -2,6 +2,10 @@ package services
 
 import (
 	"context" //nolint:gosec
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 
 	"device-management-service/internal/models"
@@ -11,11 +15,14 @@ import (
 )
 
 type Devices interface {
-	Create(ctx context.Context, params models.Device) (models.Device, error)
+	Create(ctx context.Context, params models.Device) (CreateResult, error)
 	Read(ctx context.Context) (ReadResult, error)
+	ReadByID(ctx context.Context, deviceID int32) (models.Device, error)
 	Update(ctx context.Context, params UpdateDeviceParams) error
 	Delete(ctx context.Context, deviceID int32) error
 	GetResponsible(ctx context.Context) ([]GetResponsibleResult, error)
+	RotateKey(ctx context.Context, deviceID int32) (string, error)
+	ReadKeys(ctx context.Context) ([]KeyHashResult, error)
 }
 
 type DeviceService struct {
@@ -28,24 +35,41 @@ func NewDevicesService(r repo.Devices) Devices {
 	}
 }
 
-func (s *DeviceService) Create(ctx context.Context, params models.Device) (models.Device, error) {
+type (
+	CreateResult struct {
+		Device models.Device
+		APIKey string
+	}
+)
+
+// Create issues an ingestion key for the new device, only its hash is stored.
+func (s *DeviceService) Create(ctx context.Context, params models.Device) (CreateResult, error) {
+	apiKey, keyHash, err := newAPIKey()
+	if err != nil {
+		return CreateResult{}, fmt.Errorf("newAPIKey: %w", err)
+	}
+	params.APIKeyHash = &keyHash
+
 	tx, err := s.repo.BeginTx(ctx)
 	if err != nil {
-		return models.Device{}, fmt.Errorf("s.repo.BeginTx: %w", err)
+		return CreateResult{}, fmt.Errorf("s.repo.BeginTx: %w", err)
 	}
 	defer tx.Rollback()
 
 	ret, err := tx.Create(params)
 	if err != nil {
-		return models.Device{}, fmt.Errorf("tx.Create: %w", err)
+		return CreateResult{}, fmt.Errorf("tx.Create: %w", err)
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return models.Device{}, fmt.Errorf("tx.Commit: %w", err)
+		return CreateResult{}, fmt.Errorf("tx.Commit: %w", err)
 	}
 
-	return ret, nil
+	return CreateResult{
+		Device: ret,
+		APIKey: apiKey,
+	}, nil
 }
 
 type (
@@ -76,6 +100,26 @@ func (s *DeviceService) Read(ctx context.Context) (ReadResult, error) {
 	}, nil
 }
 
+func (s *DeviceService) ReadByID(ctx context.Context, deviceID int32) (models.Device, error) {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return models.Device{}, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	device, err := tx.ReadByID(ctx, deviceID)
+	if err != nil {
+		return models.Device{}, fmt.Errorf("tx.ReadByID: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return models.Device{}, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return device, nil
+}
+
 type (
 	UpdateDeviceParams struct {
 		ID          int32
@@ -163,3 +207,78 @@ func (s *DeviceService) GetResponsible(ctx context.Context) ([]GetResponsibleRes
 		}
 	}), nil
 }
+
+// RotateKey replaces the ingestion key of the device, the previous key stops
+// working as soon as data-ingestion-service reloads the key hashes.
+func (s *DeviceService) RotateKey(ctx context.Context, deviceID int32) (string, error) {
+	apiKey, keyHash, err := newAPIKey()
+	if err != nil {
+		return "", fmt.Errorf("newAPIKey: %w", err)
+	}
+
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return "", fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	err = tx.RotateKey(ctx, deviceID, keyHash)
+	if err != nil {
+		return "", fmt.Errorf("tx.RotateKey: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return "", fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return apiKey, nil
+}
+
+type (
+	KeyHashResult struct {
+		DeviceID int32
+		Hash     string
+	}
+)
+
+func (s *DeviceService) ReadKeys(ctx context.Context) ([]KeyHashResult, error) {
+	tx, err := s.repo.BeginTx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("s.repo.BeginTx: %w", err)
+	}
+	defer tx.Rollback()
+
+	ret, err := tx.ReadKeys(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("tx.ReadKeys: %w", err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return lo.Map(ret, func(r repo.KeyHashResult, _ int) KeyHashResult {
+		return KeyHashResult{
+			DeviceID: r.ID,
+			Hash:     r.APIKeyHash,
+		}
+	}), nil
+}
+
+const apiKeySize = 32
+
+// newAPIKey returns a random key and its hex encoded sha256, the same hash
+// is computed by data-ingestion-service when it checks the key.
+func newAPIKey() (string, string, error) {
+	buf := make([]byte, apiKeySize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("rand.Read: %w", err)
+	}
+
+	apiKey := base64.RawURLEncoding.EncodeToString(buf)
+	sum := sha256.Sum256([]byte(apiKey))
+
+	return apiKey, hex.EncodeToString(sum[:]), nil
+}
@@ -23,6 +23,9 @@ const (
 	updateDevicesSubject = "devices.update"
 	deleteDevicesSubject = "devices.delete"
 
+	rotateKeyDevicesSubject = "devices.rotate_key"
+	readKeysDevicesSubject  = "devices.read_keys"
+
 	deviceManagementQueue = "device-management"
 
 	devicesUpdatedSubject = "devices.updated"
@@ -54,6 +57,16 @@ func (n *NatsListeners) listen() error {
 		return fmt.Errorf("n.natsConn.Subscribe("+deleteDevicesSubject+"): %w", err)
 	}
 
+	_, err = n.natsConn.QueueSubscribe(rotateKeyDevicesSubject, deviceManagementQueue, n.rotateKeyHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+rotateKeyDevicesSubject+"): %w", err)
+	}
+
+	_, err = n.natsConn.QueueSubscribe(readKeysDevicesSubject, deviceManagementQueue, n.readKeysHandler)
+	if err != nil {
+		return fmt.Errorf("n.natsConn.Subscribe("+readKeysDevicesSubject+"): %w", err)
+	}
+
 	return nil
 }
 
@@ -121,25 +134,28 @@ func (n *NatsListeners) createHandler(msg *nats.Msg) {
 		return
 	}
 
+	n.putDevice(created.Device.ID)
+
 	var createdAt *timestamppb.Timestamp
-	if created.CreatedAt != nil {
-		createdAt = timestamppb.New(*created.CreatedAt)
+	if created.Device.CreatedAt != nil {
+		createdAt = timestamppb.New(*created.Device.CreatedAt)
 	}
 	var updatedAt *timestamppb.Timestamp
-	if created.UpdatedAt != nil {
-		updatedAt = timestamppb.New(*created.UpdatedAt)
+	if created.Device.UpdatedAt != nil {
+		updatedAt = timestamppb.New(*created.Device.UpdatedAt)
 	}
 
 	resp := pbapidevices.CreateResp{
 		Created: &pbapidevices.Device{
-			ID:          int32(created.ID),
-			Name:        created.Name,
-			DeviceType:  created.DeviceType,
-			Address:     created.Address,
-			Responsible: created.Responsible,
+			ID:          int32(created.Device.ID),
+			Name:        created.Device.Name,
+			DeviceType:  created.Device.DeviceType,
+			Address:     created.Device.Address,
+			Responsible: created.Device.Responsible,
 			CreatedAt:   createdAt,
 			UpdatedAt:   updatedAt,
 		},
+		APIKey: created.APIKey,
 	}
 
 	binaryResp, err := proto.Marshal(&resp)
@@ -202,27 +218,31 @@ func (n *NatsListeners) sendError(subject string, message proto.Message) {
 func convertDevicesToProtoDevices(devices []models.Device) []*pbapidevices.Device {
 	var result []*pbapidevices.Device
 	for _, device := range devices {
-		var createdAt *timestamppb.Timestamp
-		if device.CreatedAt != nil {
-			createdAt = timestamppb.New(*device.CreatedAt)
-		}
+		result = append(result, convertDeviceToProtoDevice(device))
+	}
+	return result
+}
 
-		var updatedAt *timestamppb.Timestamp
-		if device.UpdatedAt != nil {
-			updatedAt = timestamppb.New(*device.UpdatedAt)
-		}
+func convertDeviceToProtoDevice(device models.Device) *pbapidevices.Device {
+	var createdAt *timestamppb.Timestamp
+	if device.CreatedAt != nil {
+		createdAt = timestamppb.New(*device.CreatedAt)
+	}
 
-		result = append(result, &pbapidevices.Device{
-			ID:          device.ID,
-			Name:        device.Name,
-			DeviceType:  device.DeviceType,
-			Address:     device.Address,
-			Responsible: device.Responsible,
-			CreatedAt:   createdAt,
-			UpdatedAt:   updatedAt,
-		})
+	var updatedAt *timestamppb.Timestamp
+	if device.UpdatedAt != nil {
+		updatedAt = timestamppb.New(*device.UpdatedAt)
+	}
+
+	return &pbapidevices.Device{
+		ID:          device.ID,
+		Name:        device.Name,
+		DeviceType:  device.DeviceType,
+		Address:     device.Address,
+		Responsible: device.Responsible,
+		CreatedAt:   createdAt,
+		UpdatedAt:   updatedAt,
 	}
-	return result
 }
 
 func (n *NatsListeners) updateHandler(msg *nats.Msg) {
@@ -264,6 +284,8 @@ func (n *NatsListeners) updateHandler(msg *nats.Msg) {
 		return
 	}
 
+	n.putDevice(request.Device.GetID())
+
 	resp := pbapidevices.UpdateResp{}
 
 	binaryResp, err := proto.Marshal(&resp)
@@ -302,6 +324,8 @@ func (n *NatsListeners) deleteHandler(msg *nats.Msg) {
 		return
 	}
 
+	n.putDevice(request.GetID())
+
 	resp := pbapidevices.DeleteResp{}
 
 	binaryResp, err := proto.Marshal(&resp)
@@ -320,3 +344,73 @@ func (n *NatsListeners) deleteHandler(msg *nats.Msg) {
 		n.log.Error("n.PublishUpdateEvent", zap.Error(err))
 	}
 }
+
+func (n *NatsListeners) rotateKeyHandler(msg *nats.Msg) {
+	var request pbapidevices.RotateKeyReq
+	err := proto.Unmarshal(msg.Data, &request)
+	if err != nil {
+		n.log.Error(
+			"proto.Unmarshal",
+			zap.Error(err),
+			zap.Binary("Data", msg.Data),
+			zap.String("Subject", msg.Subject),
+		)
+	}
+
+	apiKey, err := n.devicesService.RotateKey(context.Background(), request.GetID())
+	if err != nil {
+		n.log.Error("n.devicesService.RotateKey", zap.Error(err))
+		n.sendError(msg.Reply, &pbapidevices.RotateKeyResp{Error: err.Error()})
+		return
+	}
+
+	resp := pbapidevices.RotateKeyResp{
+		APIKey: apiKey,
+	}
+
+	binaryResp, err := proto.Marshal(&resp)
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbapidevices.RotateKeyResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+		return
+	}
+
+	if err := n.PublishUpdateEvent(); err != nil {
+		n.log.Error("n.PublishUpdateEvent", zap.Error(err))
+	}
+}
+
+func (n *NatsListeners) readKeysHandler(msg *nats.Msg) {
+	keys, err := n.devicesService.ReadKeys(context.Background())
+	if err != nil {
+		n.log.Error("n.devicesService.ReadKeys", zap.Error(err))
+		n.sendError(msg.Reply, &pbapidevices.ReadKeysResp{Error: err.Error()})
+		return
+	}
+
+	resp := pbapidevices.ReadKeysResp{
+		Keys: make([]*pbapidevices.KeyHash, 0, len(keys)),
+	}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, &pbapidevices.KeyHash{
+			DeviceID: key.DeviceID,
+			Hash:     key.Hash,
+		})
+	}
+
+	binaryResp, err := proto.Marshal(&resp)
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		n.sendError(msg.Reply, &pbapidevices.ReadKeysResp{Error: err.Error()})
+		return
+	}
+
+	if err := n.natsConn.Publish(msg.Reply, binaryResp); err != nil {
+		n.log.Error("n.natsConn.Publish", zap.Error(err))
+	}
+}
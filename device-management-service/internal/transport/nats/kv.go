@@ -0,0 +1,118 @@
+package natslisteners
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"device-management-service/internal/repo"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// devicesBucket holds every device under its id as a marshalled
+// pbapidevices.Device. The consumers watch it instead of asking for the whole
+// list on each change, a deleted device is a delete marker.
+const devicesBucket = "devices"
+
+func (n *NatsListeners) initDevicesBucket() error {
+	kv, err := n.js.KeyValue(devicesBucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = n.js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      devicesBucket,
+			Description: "Devices by id, written by device-management-service",
+			History:     1,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("n.js.KeyValue("+devicesBucket+"): %w", err)
+	}
+
+	n.devicesKV = kv
+
+	return n.syncDevicesBucket()
+}
+
+// syncDevicesBucket brings the bucket in line with the database, it may have
+// missed changes made while the bucket was unreachable. Unchanged entries are
+// not written again so the watchers are not woken up for nothing.
+func (n *NatsListeners) syncDevicesBucket() error {
+	readResult, err := n.devicesService.Read(context.Background())
+	if err != nil {
+		return fmt.Errorf("n.devicesService.Read: %w", err)
+	}
+
+	stale := make(map[string]struct{})
+	keys, err := n.devicesKV.Keys()
+	if err != nil && !errors.Is(err, nats.ErrNoKeysFound) {
+		return fmt.Errorf("n.devicesKV.Keys: %w", err)
+	}
+	for _, key := range keys {
+		stale[key] = struct{}{}
+	}
+
+	for _, device := range convertDevicesToProtoDevices(readResult.Devices) {
+		key := deviceKey(device.ID)
+		delete(stale, key)
+
+		value, err := proto.Marshal(device)
+		if err != nil {
+			return fmt.Errorf("proto.Marshal: %w", err)
+		}
+
+		entry, err := n.devicesKV.Get(key)
+		if err == nil && bytes.Equal(entry.Value(), value) {
+			continue
+		}
+
+		if _, err = n.devicesKV.Put(key, value); err != nil {
+			return fmt.Errorf("n.devicesKV.Put: %w", err)
+		}
+	}
+
+	for key := range stale {
+		if err = n.devicesKV.Delete(key); err != nil {
+			return fmt.Errorf("n.devicesKV.Delete: %w", err)
+		}
+	}
+
+	n.log.Info("Devices bucket synced", zap.Int("devices", len(readResult.Devices)), zap.Int("deleted", len(stale)))
+
+	return nil
+}
+
+// putDevice writes the current state of the device to the bucket, or deletes
+// it if the device is gone.
+func (n *NatsListeners) putDevice(deviceID int32) {
+	key := deviceKey(deviceID)
+
+	device, err := n.devicesService.ReadByID(context.Background(), deviceID)
+	if errors.Is(err, repo.ErrDeviceNotFound) {
+		if err = n.devicesKV.Delete(key); err != nil {
+			n.log.Error("n.devicesKV.Delete", zap.Error(err), zap.Int32("device_id", deviceID))
+		}
+		return
+	}
+	if err != nil {
+		n.log.Error("n.devicesService.ReadByID", zap.Error(err), zap.Int32("device_id", deviceID))
+		return
+	}
+
+	value, err := proto.Marshal(convertDeviceToProtoDevice(device))
+	if err != nil {
+		n.log.Error("proto.Marshal", zap.Error(err))
+		return
+	}
+
+	if _, err = n.devicesKV.Put(key, value); err != nil {
+		n.log.Error("n.devicesKV.Put", zap.Error(err), zap.Int32("device_id", deviceID))
+	}
+}
+
+func deviceKey(deviceID int32) string {
+	return strconv.FormatInt(int64(deviceID), 10)
+}
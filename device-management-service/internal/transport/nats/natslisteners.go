@@ -1,6 +1,8 @@
 package natslisteners
 
 import (
+	"fmt"
+
 	"device-management-service/internal/services"
 
 	"github.com/nats-io/nats.go"
@@ -9,12 +11,15 @@ import (
 
 type NatsListeners struct {
 	natsConn       *nats.Conn
+	js             nats.JetStreamContext
+	devicesKV      nats.KeyValue
 	devicesService services.Devices
 	log            *zap.Logger
 }
 
 type Config struct {
 	NatsConn       *nats.Conn
+	Js             nats.JetStreamContext
 	DevicesService services.Devices
 	Log            *zap.Logger
 }
@@ -22,11 +27,16 @@ type Config struct {
 func NewListener(cfg Config) *NatsListeners {
 	return &NatsListeners{
 		natsConn:       cfg.NatsConn,
+		js:             cfg.Js,
 		devicesService: cfg.DevicesService,
 		log:            cfg.Log,
 	}
 }
 
 func (n *NatsListeners) Run() error {
+	if err := n.initDevicesBucket(); err != nil {
+		return fmt.Errorf("n.initDevicesBucket: %w", err)
+	}
+
 	return n.listen()
 }
@@ -10,6 +10,7 @@ import (
 
 type Nats struct {
 	NatsConn *nats.Conn
+	Js       nats.JetStreamContext
 	log      *zap.Logger
 }
 
@@ -23,6 +24,11 @@ func NewNats(natsConnString string, log *zap.Logger) (*Nats, error) {
 		return nil, fmt.Errorf("nats.Connect: %w", err)
 	}
 
+	natsConn.Js, err = natsConn.NatsConn.JetStream()
+	if err != nil {
+		return nil, fmt.Errorf("natsConn.JetStream(): %w", err)
+	}
+
 	return natsConn, nil
 }
 
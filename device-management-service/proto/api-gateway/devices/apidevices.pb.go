@@ -159,9 +159,11 @@ func (x *CreateReq) GetDevice() *Device {
 }
 
 type CreateResp struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Created       *Device                `protobuf:"bytes,1,opt,name=Created,proto3" json:"Created,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Created *Device                `protobuf:"bytes,1,opt,name=Created,proto3" json:"Created,omitempty"`
+	Error   string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	// Ingestion key of the new device, it is returned only once.
+	APIKey        string `protobuf:"bytes,3,opt,name=APIKey,proto3" json:"APIKey,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -210,6 +212,13 @@ func (x *CreateResp) GetError() string {
 	return ""
 }
 
+func (x *CreateResp) GetAPIKey() string {
+	if x != nil {
+		return x.APIKey
+	}
+	return ""
+}
+
 type ReadResp struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Devices       []*Device              `protobuf:"bytes,1,rep,name=Devices,proto3" json:"Devices,omitempty"`
@@ -438,6 +447,206 @@ func (x *DeleteResp) GetError() string {
 	return ""
 }
 
+type RotateKeyReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateKeyReq) Reset() {
+	*x = RotateKeyReq{}
+	mi := &file_apidevices_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateKeyReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateKeyReq) ProtoMessage() {}
+
+func (x *RotateKeyReq) ProtoReflect() protoreflect.Message {
+	mi := &file_apidevices_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateKeyReq.ProtoReflect.Descriptor instead.
+func (*RotateKeyReq) Descriptor() ([]byte, []int) {
+	return file_apidevices_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RotateKeyReq) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+type RotateKeyResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	APIKey        string                 `protobuf:"bytes,1,opt,name=APIKey,proto3" json:"APIKey,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateKeyResp) Reset() {
+	*x = RotateKeyResp{}
+	mi := &file_apidevices_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateKeyResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateKeyResp) ProtoMessage() {}
+
+func (x *RotateKeyResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apidevices_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateKeyResp.ProtoReflect.Descriptor instead.
+func (*RotateKeyResp) Descriptor() ([]byte, []int) {
+	return file_apidevices_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RotateKeyResp) GetAPIKey() string {
+	if x != nil {
+		return x.APIKey
+	}
+	return ""
+}
+
+func (x *RotateKeyResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type KeyHash struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceID      int32                  `protobuf:"varint,1,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Hash          string                 `protobuf:"bytes,2,opt,name=Hash,proto3" json:"Hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyHash) Reset() {
+	*x = KeyHash{}
+	mi := &file_apidevices_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyHash) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyHash) ProtoMessage() {}
+
+func (x *KeyHash) ProtoReflect() protoreflect.Message {
+	mi := &file_apidevices_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyHash.ProtoReflect.Descriptor instead.
+func (*KeyHash) Descriptor() ([]byte, []int) {
+	return file_apidevices_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *KeyHash) GetDeviceID() int32 {
+	if x != nil {
+		return x.DeviceID
+	}
+	return 0
+}
+
+func (x *KeyHash) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type ReadKeysResp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*KeyHash             `protobuf:"bytes,1,rep,name=Keys,proto3" json:"Keys,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=Error,proto3" json:"Error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadKeysResp) Reset() {
+	*x = ReadKeysResp{}
+	mi := &file_apidevices_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadKeysResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadKeysResp) ProtoMessage() {}
+
+func (x *ReadKeysResp) ProtoReflect() protoreflect.Message {
+	mi := &file_apidevices_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadKeysResp.ProtoReflect.Descriptor instead.
+func (*ReadKeysResp) Descriptor() ([]byte, []int) {
+	return file_apidevices_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReadKeysResp) GetKeys() []*KeyHash {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *ReadKeysResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 var File_apidevices_proto protoreflect.FileDescriptor
 
 const file_apidevices_proto_rawDesc = "" +
@@ -454,11 +663,12 @@ const file_apidevices_proto_rawDesc = "" +
 	"\tCreatedAt\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tCreatedAt\x128\n" +
 	"\tUpdatedAt\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tUpdatedAt\"6\n" +
 	"\tCreateReq\x12)\n" +
-	"\x06Device\x18\x01 \x01(\v2\x11.pbdevices.DeviceR\x06Device\"O\n" +
+	"\x06Device\x18\x01 \x01(\v2\x11.pbdevices.DeviceR\x06Device\"g\n" +
 	"\n" +
 	"CreateResp\x12+\n" +
 	"\aCreated\x18\x01 \x01(\v2\x11.pbdevices.DeviceR\aCreated\x12\x14\n" +
-	"\x05Error\x18\x02 \x01(\tR\x05Error\"M\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\x12\x16\n" +
+	"\x06APIKey\x18\x03 \x01(\tR\x06APIKey\"M\n" +
 	"\bReadResp\x12+\n" +
 	"\aDevices\x18\x01 \x03(\v2\x11.pbdevices.DeviceR\aDevices\x12\x14\n" +
 	"\x05Error\x18\x02 \x01(\tR\x05Error\"6\n" +
@@ -471,7 +681,18 @@ const file_apidevices_proto_rawDesc = "" +
 	"\x02ID\x18\x01 \x01(\x05R\x02ID\"\"\n" +
 	"\n" +
 	"DeleteResp\x12\x14\n" +
-	"\x05Error\x18\x01 \x01(\tR\x05ErrorB\x10Z\x0e.;pbapidevicesb\x06proto3"
+	"\x05Error\x18\x01 \x01(\tR\x05Error\"\x1e\n" +
+	"\fRotateKeyReq\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\"=\n" +
+	"\rRotateKeyResp\x12\x16\n" +
+	"\x06APIKey\x18\x01 \x01(\tR\x06APIKey\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05Error\"9\n" +
+	"\aKeyHash\x12\x1a\n" +
+	"\bDeviceID\x18\x01 \x01(\x05R\bDeviceID\x12\x12\n" +
+	"\x04Hash\x18\x02 \x01(\tR\x04Hash\"L\n" +
+	"\fReadKeysResp\x12&\n" +
+	"\x04Keys\x18\x01 \x03(\v2\x12.pbdevices.KeyHashR\x04Keys\x12\x14\n" +
+	"\x05Error\x18\x02 \x01(\tR\x05ErrorB\x10Z\x0e.;pbapidevicesb\x06proto3"
 
 var (
 	file_apidevices_proto_rawDescOnce sync.Once
@@ -485,7 +706,7 @@ func file_apidevices_proto_rawDescGZIP() []byte {
 	return file_apidevices_proto_rawDescData
 }
 
-var file_apidevices_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_apidevices_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
 var file_apidevices_proto_goTypes = []any{
 	(*Device)(nil),                // 0: pbdevices.Device
 	(*CreateReq)(nil),             // 1: pbdevices.CreateReq
@@ -495,20 +716,25 @@ var file_apidevices_proto_goTypes = []any{
 	(*UpdateResp)(nil),            // 5: pbdevices.UpdateResp
 	(*DeleteReq)(nil),             // 6: pbdevices.DeleteReq
 	(*DeleteResp)(nil),            // 7: pbdevices.DeleteResp
-	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*RotateKeyReq)(nil),          // 8: pbdevices.RotateKeyReq
+	(*RotateKeyResp)(nil),         // 9: pbdevices.RotateKeyResp
+	(*KeyHash)(nil),               // 10: pbdevices.KeyHash
+	(*ReadKeysResp)(nil),          // 11: pbdevices.ReadKeysResp
+	(*timestamppb.Timestamp)(nil), // 12: google.protobuf.Timestamp
 }
 var file_apidevices_proto_depIdxs = []int32{
-	8, // 0: pbdevices.Device.CreatedAt:type_name -> google.protobuf.Timestamp
-	8, // 1: pbdevices.Device.UpdatedAt:type_name -> google.protobuf.Timestamp
-	0, // 2: pbdevices.CreateReq.Device:type_name -> pbdevices.Device
-	0, // 3: pbdevices.CreateResp.Created:type_name -> pbdevices.Device
-	0, // 4: pbdevices.ReadResp.Devices:type_name -> pbdevices.Device
-	0, // 5: pbdevices.UpdateReq.Device:type_name -> pbdevices.Device
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	12, // 0: pbdevices.Device.CreatedAt:type_name -> google.protobuf.Timestamp
+	12, // 1: pbdevices.Device.UpdatedAt:type_name -> google.protobuf.Timestamp
+	0,  // 2: pbdevices.CreateReq.Device:type_name -> pbdevices.Device
+	0,  // 3: pbdevices.CreateResp.Created:type_name -> pbdevices.Device
+	0,  // 4: pbdevices.ReadResp.Devices:type_name -> pbdevices.Device
+	0,  // 5: pbdevices.UpdateReq.Device:type_name -> pbdevices.Device
+	10, // 6: pbdevices.ReadKeysResp.Keys:type_name -> pbdevices.KeyHash
+	7,  // [7:7] is the sub-list for method output_type
+	7,  // [7:7] is the sub-list for method input_type
+	7,  // [7:7] is the sub-list for extension type_name
+	7,  // [7:7] is the sub-list for extension extendee
+	0,  // [0:7] is the sub-list for field type_name
 }
 
 func init() { file_apidevices_proto_init() }
@@ -522,7 +748,7 @@ func file_apidevices_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_apidevices_proto_rawDesc), len(file_apidevices_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   12,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
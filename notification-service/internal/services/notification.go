@@ -1,5 +1,7 @@
 package services
 
+import "sync"
+
 type NotificationService struct {
 }
 
@@ -7,12 +9,62 @@ func NewNotificationService() *NotificationService {
 	return &NotificationService{}
 }
 
-var ResponsiblesByDeviceId = map[int32][]string{}
+// ResponsiblesLoader resolves the emails of a device missing from the local
+// cache, ok is false if there is no such device.
+type ResponsiblesLoader func(deviceID int32) (emails []string, ok bool, err error)
+
+var (
+	responsiblesMutex      sync.Mutex
+	ResponsiblesByDeviceId = map[int32][]string{}
+	responsiblesLoader     ResponsiblesLoader
+)
+
+func (ns *NotificationService) SetResponsibles(deviceID int32, emails []string) {
+	responsiblesMutex.Lock()
+	defer responsiblesMutex.Unlock()
+
+	ResponsiblesByDeviceId[deviceID] = emails
+}
+
+func (ns *NotificationService) DeleteResponsibles(deviceID int32) {
+	responsiblesMutex.Lock()
+	defer responsiblesMutex.Unlock()
+
+	delete(ResponsiblesByDeviceId, deviceID)
+}
+
+func (ns *NotificationService) SetResponsiblesLoader(loader ResponsiblesLoader) {
+	responsiblesMutex.Lock()
+	defer responsiblesMutex.Unlock()
 
-func (ns *NotificationService) SetResponsiblesByDeviceId(resposiblesByDeviceID map[int32][]string) {
-	ResponsiblesByDeviceId = resposiblesByDeviceID
+	responsiblesLoader = loader
 }
 
+// GetResposibles reads through to the loader on a miss, a device created a
+// moment ago may not have reached the watcher yet.
 func (ns *NotificationService) GetResposibles(deviceID int32) []string {
-	return ResponsiblesByDeviceId[deviceID]
+	responsiblesMutex.Lock()
+	emails, ok := ResponsiblesByDeviceId[deviceID]
+	loader := responsiblesLoader
+	responsiblesMutex.Unlock()
+
+	if ok || loader == nil {
+		return emails
+	}
+
+	emails, ok, err := loader(deviceID)
+	if err != nil || !ok {
+		return nil
+	}
+
+	responsiblesMutex.Lock()
+	defer responsiblesMutex.Unlock()
+
+	// The watcher may have been faster and its entry is the newer one.
+	if cached, found := ResponsiblesByDeviceId[deviceID]; found {
+		return cached
+	}
+	ResponsiblesByDeviceId[deviceID] = emails
+
+	return emails
 }
@@ -0,0 +1,170 @@
+package natslisteners
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	pbdevices "notification-service/proto/devices"
+	pbusers "notification-service/proto/users"
+
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+// devicesBucket is materialised by device-management-service, every device is
+// stored under its id.
+const devicesBucket = "devices"
+
+// devicesWatchRetry is the pause before a stopped watcher of the devices
+// bucket is started again.
+const devicesWatchRetry = 5 * time.Second
+
+// bindDevicesBucket binds the devices bucket. If device-management-service
+// did not create it yet, it is created with the same config, so the services
+// start in any order and the devices show up once it fills the bucket.
+func bindDevicesBucket(js nats.JetStreamContext) (nats.KeyValue, error) {
+	kv, err := js.KeyValue(devicesBucket)
+	if errors.Is(err, nats.ErrBucketNotFound) {
+		kv, err = js.CreateKeyValue(&nats.KeyValueConfig{
+			Bucket:      devicesBucket,
+			Description: "Devices by id, written by device-management-service",
+			History:     1,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("js.KeyValue("+devicesBucket+"): %w", err)
+	}
+
+	return kv, nil
+}
+
+// watchDevices resolves the responsibles of every device in the devices
+// bucket and keeps them current as single devices change. It returns once
+// the current content is loaded.
+func (n *NatsListeners) watchDevices() error {
+	kv, err := bindDevicesBucket(n.js)
+	if err != nil {
+		return fmt.Errorf("bindDevicesBucket: %w", err)
+	}
+
+	watcher, err := kv.WatchAll()
+	if err != nil {
+		return fmt.Errorf("kv.WatchAll: %w", err)
+	}
+
+	// A nil entry marks the end of the initial values.
+	for entry := range watcher.Updates() {
+		if entry == nil {
+			break
+		}
+		n.applyDeviceEntry(entry)
+	}
+
+	n.notificationService.SetResponsiblesLoader(func(deviceID int32) ([]string, bool, error) {
+		entry, err := kv.Get(strconv.FormatInt(int64(deviceID), 10))
+		if errors.Is(err, nats.ErrKeyNotFound) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("kv.Get: %w", err)
+		}
+
+		var device pbdevices.Device
+		if err = proto.Unmarshal(entry.Value(), &device); err != nil {
+			return nil, false, fmt.Errorf("proto.Unmarshal: %w", err)
+		}
+
+		emails, err := n.getEmails(device.GetResponsible())
+		if err != nil {
+			return nil, false, fmt.Errorf("n.getEmails: %w", err)
+		}
+
+		return emails, true, nil
+	})
+
+	go n.followDevices(kv, watcher)
+
+	return nil
+}
+
+// followDevices applies the changes of single devices. A stopped watcher is
+// started again, its initial values bring the cache up to date.
+func (n *NatsListeners) followDevices(kv nats.KeyValue, watcher nats.KeyWatcher) {
+	for {
+		for entry := range watcher.Updates() {
+			if entry != nil {
+				n.applyDeviceEntry(entry)
+			}
+		}
+		n.log.Warn("devices bucket watcher stopped, restarting")
+
+		for {
+			time.Sleep(devicesWatchRetry)
+
+			var err error
+			watcher, err = kv.WatchAll()
+			if err == nil {
+				break
+			}
+			n.log.Error("kv.WatchAll", zap.Error(err))
+		}
+	}
+}
+
+func (n *NatsListeners) applyDeviceEntry(entry nats.KeyValueEntry) {
+	if entry.Operation() != nats.KeyValuePut {
+		deviceID, err := strconv.ParseInt(entry.Key(), 10, 32)
+		if err != nil {
+			n.log.Warn("strconv.ParseInt", zap.Error(err), zap.String("key", entry.Key()))
+			return
+		}
+		n.notificationService.DeleteResponsibles(int32(deviceID))
+		return
+	}
+
+	var device pbdevices.Device
+	if err := proto.Unmarshal(entry.Value(), &device); err != nil {
+		n.log.Error("proto.Unmarshal", zap.Error(err), zap.String("key", entry.Key()))
+		return
+	}
+
+	emails, err := n.getEmails(device.GetResponsible())
+	if err != nil {
+		n.log.Error("n.getEmails", zap.Error(err), zap.Int32("DeviceID", device.GetID()))
+		return
+	}
+	if len(emails) == 0 {
+		n.log.Warn("responsible not found",
+			zap.Int32s("responsibleID", device.GetResponsible()),
+			zap.Int32("DeviceID", device.GetID()),
+		)
+	}
+
+	n.notificationService.SetResponsibles(device.GetID(), emails)
+}
+
+func (n *NatsListeners) getEmails(userIDs []int32) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	emailReqBytes, err := proto.Marshal(&pbusers.GetEmailReq{UserID: userIDs})
+	if err != nil {
+		return nil, fmt.Errorf("proto.Marshal: %w", err)
+	}
+
+	emailRespMsg, err := n.natsConn.Request(getEmailSubject, emailReqBytes, n.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("n.natsConn.Request("+getEmailSubject+"): %w", err)
+	}
+
+	var emailResp pbusers.GetEmailResp
+	if err = proto.Unmarshal(emailRespMsg.Data, &emailResp); err != nil {
+		return nil, fmt.Errorf("proto.Unmarshal: %w", err)
+	}
+
+	return emailResp.GetEmail(), nil
+}
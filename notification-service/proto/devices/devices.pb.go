@@ -9,6 +9,7 @@ package pbdevices
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,6 +22,99 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Device is the value stored in the devices bucket.
+type Device struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            int32                  `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	DeviceType    string                 `protobuf:"bytes,3,opt,name=DeviceType,proto3" json:"DeviceType,omitempty"`
+	Address       string                 `protobuf:"bytes,4,opt,name=Address,proto3" json:"Address,omitempty"`
+	Responsible   []int32                `protobuf:"varint,5,rep,packed,name=Responsible,proto3" json:"Responsible,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=UpdatedAt,proto3" json:"UpdatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_devices_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_devices_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_devices_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Device) GetID() int32 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+func (x *Device) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Device) GetDeviceType() string {
+	if x != nil {
+		return x.DeviceType
+	}
+	return ""
+}
+
+func (x *Device) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Device) GetResponsible() []int32 {
+	if x != nil {
+		return x.Responsible
+	}
+	return nil
+}
+
+func (x *Device) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Device) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
 type ResposiblesByDeviceID struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ResponsibleID []int32                `protobuf:"varint,1,rep,packed,name=ResponsibleID,proto3" json:"ResponsibleID,omitempty"`
@@ -31,7 +125,7 @@ type ResposiblesByDeviceID struct {
 
 func (x *ResposiblesByDeviceID) Reset() {
 	*x = ResposiblesByDeviceID{}
-	mi := &file_devices_proto_msgTypes[0]
+	mi := &file_devices_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -43,7 +137,7 @@ func (x *ResposiblesByDeviceID) String() string {
 func (*ResposiblesByDeviceID) ProtoMessage() {}
 
 func (x *ResposiblesByDeviceID) ProtoReflect() protoreflect.Message {
-	mi := &file_devices_proto_msgTypes[0]
+	mi := &file_devices_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -56,7 +150,7 @@ func (x *ResposiblesByDeviceID) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ResposiblesByDeviceID.ProtoReflect.Descriptor instead.
 func (*ResposiblesByDeviceID) Descriptor() ([]byte, []int) {
-	return file_devices_proto_rawDescGZIP(), []int{0}
+	return file_devices_proto_rawDescGZIP(), []int{1}
 }
 
 func (x *ResposiblesByDeviceID) GetResponsibleID() []int32 {
@@ -82,7 +176,7 @@ type GetResponsibleResp struct {
 
 func (x *GetResponsibleResp) Reset() {
 	*x = GetResponsibleResp{}
-	mi := &file_devices_proto_msgTypes[1]
+	mi := &file_devices_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -94,7 +188,7 @@ func (x *GetResponsibleResp) String() string {
 func (*GetResponsibleResp) ProtoMessage() {}
 
 func (x *GetResponsibleResp) ProtoReflect() protoreflect.Message {
-	mi := &file_devices_proto_msgTypes[1]
+	mi := &file_devices_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -107,7 +201,7 @@ func (x *GetResponsibleResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetResponsibleResp.ProtoReflect.Descriptor instead.
 func (*GetResponsibleResp) Descriptor() ([]byte, []int) {
-	return file_devices_proto_rawDescGZIP(), []int{1}
+	return file_devices_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *GetResponsibleResp) GetResposiblesByDeviceID() []*ResposiblesByDeviceID {
@@ -121,7 +215,17 @@ var File_devices_proto protoreflect.FileDescriptor
 
 const file_devices_proto_rawDesc = "" +
 	"\n" +
-	"\rdevices.proto\x12\tpbmessage\"Y\n" +
+	"\rdevices.proto\x12\tpbmessage\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfc\x01\n" +
+	"\x06Device\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\x05R\x02ID\x12\x12\n" +
+	"\x04Name\x18\x02 \x01(\tR\x04Name\x12\x1e\n" +
+	"\n" +
+	"DeviceType\x18\x03 \x01(\tR\n" +
+	"DeviceType\x12\x18\n" +
+	"\aAddress\x18\x04 \x01(\tR\aAddress\x12 \n" +
+	"\vResponsible\x18\x05 \x03(\x05R\vResponsible\x128\n" +
+	"\tCreatedAt\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tCreatedAt\x128\n" +
+	"\tUpdatedAt\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tUpdatedAt\"Y\n" +
 	"\x15ResposiblesByDeviceID\x12$\n" +
 	"\rResponsibleID\x18\x01 \x03(\x05R\rResponsibleID\x12\x1a\n" +
 	"\bDeviceID\x18\x02 \x01(\x05R\bDeviceID\"l\n" +
@@ -140,18 +244,22 @@ func file_devices_proto_rawDescGZIP() []byte {
 	return file_devices_proto_rawDescData
 }
 
-var file_devices_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_devices_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_devices_proto_goTypes = []any{
-	(*ResposiblesByDeviceID)(nil), // 0: pbmessage.ResposiblesByDeviceID
-	(*GetResponsibleResp)(nil),    // 1: pbmessage.GetResponsibleResp
+	(*Device)(nil),                // 0: pbmessage.Device
+	(*ResposiblesByDeviceID)(nil), // 1: pbmessage.ResposiblesByDeviceID
+	(*GetResponsibleResp)(nil),    // 2: pbmessage.GetResponsibleResp
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
 }
 var file_devices_proto_depIdxs = []int32{
-	0, // 0: pbmessage.GetResponsibleResp.ResposiblesByDeviceID:type_name -> pbmessage.ResposiblesByDeviceID
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	3, // 0: pbmessage.Device.CreatedAt:type_name -> google.protobuf.Timestamp
+	3, // 1: pbmessage.Device.UpdatedAt:type_name -> google.protobuf.Timestamp
+	1, // 2: pbmessage.GetResponsibleResp.ResposiblesByDeviceID:type_name -> pbmessage.ResposiblesByDeviceID
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_devices_proto_init() }
@@ -165,7 +273,7 @@ func file_devices_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_devices_proto_rawDesc), len(file_devices_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -22,10 +22,12 @@ const (
 )
 
 type SendNotifyReq struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DeviceID      int32                  `protobuf:"varint,1,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
-	Text          string                 `protobuf:"bytes,2,opt,name=Text,proto3" json:"Text,omitempty"`
-	Subject       string                 `protobuf:"bytes,3,opt,name=Subject,proto3" json:"Subject,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceID int32                  `protobuf:"varint,1,opt,name=DeviceID,proto3" json:"DeviceID,omitempty"`
+	Text     string                 `protobuf:"bytes,2,opt,name=Text,proto3" json:"Text,omitempty"`
+	Subject  string                 `protobuf:"bytes,3,opt,name=Subject,proto3" json:"Subject,omitempty"`
+	// Suppressed is how many repeats were not sent since the previous one.
+	Suppressed    int32 `protobuf:"varint,4,opt,name=Suppressed,proto3" json:"Suppressed,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -81,15 +83,25 @@ func (x *SendNotifyReq) GetSubject() string {
 	return ""
 }
 
+func (x *SendNotifyReq) GetSuppressed() int32 {
+	if x != nil {
+		return x.Suppressed
+	}
+	return 0
+}
+
 var File_notification_proto protoreflect.FileDescriptor
 
 const file_notification_proto_rawDesc = "" +
 	"\n" +
-	"\x12notification.proto\x12\tpbmessage\"Y\n" +
+	"\x12notification.proto\x12\tpbmessage\"y\n" +
 	"\rSendNotifyReq\x12\x1a\n" +
 	"\bDeviceID\x18\x01 \x01(\x05R\bDeviceID\x12\x12\n" +
 	"\x04Text\x18\x02 \x01(\tR\x04Text\x12\x18\n" +
-	"\aSubject\x18\x03 \x01(\tR\aSubjectB\x12Z\x10.;pbnotificationb\x06proto3"
+	"\aSubject\x18\x03 \x01(\tR\aSubject\x12\x1e\n" +
+	"\n" +
+	"Suppressed\x18\x04 \x01(\x05R\n" +
+	"SuppressedB\x12Z\x10.;pbnotificationb\x06proto3"
 
 var (
 	file_notification_proto_rawDescOnce sync.Once